@@ -1,16 +1,34 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/marouane-souiri/vocalize/internal/config"
 	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
 
+	"github.com/marouane-souiri/vocalize/internal/implementation/argumentsparser"
 	"github.com/marouane-souiri/vocalize/internal/implementation/client"
+	"github.com/marouane-souiri/vocalize/internal/implementation/commanderrors"
 	"github.com/marouane-souiri/vocalize/internal/implementation/commandscontext"
 	"github.com/marouane-souiri/vocalize/internal/implementation/commandsmanager"
+	"github.com/marouane-souiri/vocalize/internal/implementation/cooldownstore"
 	"github.com/marouane-souiri/vocalize/internal/implementation/discordcache"
+	"github.com/marouane-souiri/vocalize/internal/implementation/guildsettings"
+	"github.com/marouane-souiri/vocalize/internal/implementation/interactionsendpoint"
+	"github.com/marouane-souiri/vocalize/internal/implementation/interactionsrouter"
+	"github.com/marouane-souiri/vocalize/internal/implementation/middlewares"
+	"github.com/marouane-souiri/vocalize/internal/implementation/prefixresolver"
 	"github.com/marouane-souiri/vocalize/internal/implementation/ratelimiter"
+	"github.com/marouane-souiri/vocalize/internal/implementation/reactionsmanager"
 	"github.com/marouane-souiri/vocalize/internal/implementation/requester"
 	"github.com/marouane-souiri/vocalize/internal/implementation/websocket"
 	"github.com/marouane-souiri/vocalize/internal/implementation/workerpool"
@@ -19,11 +37,25 @@ import (
 	"github.com/marouane-souiri/vocalize/internal/application/handlers"
 )
 
+const (
+	// interactions are small and discord gives up on them after 3 seconds, slow or big requests are cut off
+	interactionsMaxBodySize       = 1 << 20
+	interactionsReadHeaderTimeout = 5 * time.Second
+	interactionsReadTimeout       = 10 * time.Second
+	interactionsWriteTimeout      = 10 * time.Second
+	interactionsIdleTimeout       = time.Minute
+	// how long the interactions being answered get to finish on exit
+	shutdownTimeout = 5 * time.Second
+)
+
 func main() {
 	if err := config.Load(); err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	websocketManager := websocket.NewWSManager()
 	defer websocketManager.Close()
 
@@ -32,13 +64,31 @@ func main() {
 
 	discordCacheManager := discordcache.NewDiscordCacheManager()
 
-	rateLimiter := ratelimiter.NewRateLimiter()
+	var rateLimiter interfaces.RateLimiter = ratelimiter.NewRateLimiter()
+	apiBaseURL := requester.APIBaseURL
+	if proxyURL := config.Conf.Discord.RateLimitProxy.URL; proxyURL != "" {
+		// the proxy limits requests for every process sharing the token
+		rateLimiter = ratelimiter.NewNoopRateLimiter()
+		apiBaseURL = proxyURL
+	}
+
+	apiRequester := requester.NewAPIRequester(&requester.APIRequesterOptions{
+		Token:       config.Conf.Discord.Token,
+		RateLimiter: rateLimiter,
+		BaseURL:     apiBaseURL,
+		MaxRetries:  config.Conf.Discord.MaxRetries,
+		ProxySecret: config.Conf.Discord.RateLimitProxy.Secret,
+	})
 
-	apiRequester := requester.NewAPIRequester(config.Conf.Discord.Token, rateLimiter)
+	var intents uint64 = domain.Intents_GUILDS |
+		domain.Intents_GUILD_MESSAGES |
+		domain.Intents_GUILD_MESSAGE_REACTIONS |
+		domain.Intents_DIRECT_MESSAGE_REACTIONS |
+		domain.Intents_MESSAGE_CONTENT
 
 	client, err := client.NewClient(&client.CLientOptions{
 		Token:   config.Conf.Discord.Token,
-		Intents: domain.Intents_GUILDS | domain.Intents_GUILD_MESSAGES | domain.Intents_MESSAGE_CONTENT,
+		Intents: intents,
 		Ws:      websocketManager,
 		Wp:      workerpoolManager,
 		Cm:      discordCacheManager,
@@ -49,13 +99,40 @@ func main() {
 	}
 
 	commandsContextMaker := commandscontext.NewCommandsContextMaker()
-	commandsManager := commandsmanager.NewCommandsManager()
+	commandsManager := commandsmanager.NewCommandsManager(&commandsmanager.CommandsManagerOptions{
+		CaseInsensitive: config.Conf.Discord.CaseInsensitiveCommands,
+	})
+	reactionsManager := reactionsmanager.NewReactionsManager()
+	argumentsParser := argumentsparser.NewArgumentsParser(client)
+	commandErrorHandler := commanderrors.NewCommandErrorHandler()
+	interactionsRouter := interactionsrouter.NewInteractionsRouter(client, commandsManager, commandsContextMaker, argumentsParser, commandErrorHandler)
+	guildSettings, err := newGuildSettingsRepository()
+	if err != nil {
+		log.Fatalf("Failed to create guild settings repository: %v", err)
+	}
+	prefixResolver := prefixresolver.NewPrefixResolver(&prefixresolver.PrefixResolverOptions{
+		Client:          client,
+		Settings:        guildSettings,
+		DefaultPrefix:   config.Conf.Discord.Prefix,
+		CaseInsensitive: config.Conf.Discord.CaseInsensitiveCommands,
+	})
 
-	commandsManager.AddCommand(commands.NewPingCommand())
+	cooldownStore := cooldownstore.NewCooldownStore(config.Conf.Discord.CooldownMaxEntries)
+	commandsManager.Use(
+		middlewares.RequirementsMiddleware(config.Conf.Discord.OwnerIDs),
+		middlewares.LimitsMiddleware(cooldownStore, config.Conf.Discord.CooldownBypassRoleIDs),
+	)
+	commandsManager.AddCommands(
+		commands.NewPingCommand(),
+		commands.NewHelpCommand(commandsManager, interactionsRouter, config.Conf.Discord.OwnerIDs),
+		commands.NewPrefixCommand(guildSettings, prefixResolver),
+	)
+
+	client.Once("READY", handlers.ReadyHandler(client, commandsManager, config.Conf.Discord.CommandsGuildID))
 
 	client.On("GUILD_CREATE", handlers.GuildCreateHandler(client))
 	client.On("GUILD_UPDATE", handlers.GuildUpdateHandler(client))
-	client.On("GUILD_DELETE", handlers.GuildDeleteHandler(client))
+	client.On("GUILD_DELETE", handlers.GuildDeleteHandler(client, guildSettings))
 
 	client.On("CHANNEL_CREATE", handlers.ChannelCreateHandler(client))
 	client.On("CHANNEL_UPDATE", handlers.ChannelUpdateHandler(client))
@@ -65,12 +142,72 @@ func main() {
 	client.On("GUILD_MEMBER_REMOVE", handlers.MemberRemoveHandler(client))
 	client.On("GUILD_MEMBER_UPDATE", handlers.MemberUpdateHandler(client))
 
-	client.On("MESSAGE_CREATE", handlers.MessageCreateHandler(client, commandsManager, commandsContextMaker))
+	client.On("MESSAGE_CREATE", handlers.MessageCreateHandler(client, commandsManager, commandsContextMaker, argumentsParser, prefixResolver, commandErrorHandler))
+
+	client.On("MESSAGE_REACTION_ADD", handlers.MessageReactionAddHandler(reactionsManager))
+	client.On("MESSAGE_REACTION_REMOVE", handlers.MessageReactionRemoveHandler(reactionsManager))
+	client.On("MESSAGE_REACTION_REMOVE_ALL", handlers.MessageReactionRemoveAllHandler(reactionsManager))
+	client.On("MESSAGE_REACTION_REMOVE_EMOJI", handlers.MessageReactionRemoveEmojiHandler(reactionsManager))
+
+	client.On("INTERACTION_CREATE", handlers.InteractionCreateHandler(interactionsRouter, interactionsrouter.NewGatewayResponder(apiRequester)))
+
+	var interactionsServer *http.Server
+	if addr := config.Conf.Discord.InteractionsAddr; addr != "" {
+		endpoint, err := interactionsendpoint.NewEndpoint(&interactionsendpoint.EndpointOptions{
+			PublicKey: config.Conf.Discord.PublicKey,
+			Router:    interactionsRouter,
+			API:       apiRequester,
+		})
+		if err != nil {
+			log.Fatalf("Failed to create interactions endpoint: %v", err)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/interactions", endpoint)
+		interactionsServer = &http.Server{
+			Addr:              addr,
+			Handler:           http.MaxBytesHandler(mux, interactionsMaxBodySize),
+			ReadHeaderTimeout: interactionsReadHeaderTimeout,
+			ReadTimeout:       interactionsReadTimeout,
+			WriteTimeout:      interactionsWriteTimeout,
+			IdleTimeout:       interactionsIdleTimeout,
+		}
+		go func() {
+			log.Printf("Listening for interactions on %s", addr)
+			if err := interactionsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("Failed to run interactions endpoint: %v", err)
+			}
+		}()
+	}
 
 	if err := client.Start(); err != nil {
 		log.Fatalf("Failed to start discord client: %v", err)
 	}
 	defer client.Stop()
 
-	select {}
+	<-ctx.Done()
+	log.Printf("Shutting down")
+	if interactionsServer != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := interactionsServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Failed to shut the interactions endpoint down: %v", err)
+		}
+	}
+}
+
+// newGuildSettingsRepository opens the backend chosen in the config, behind a cache.
+func newGuildSettingsRepository() (interfaces.GuildSettingsRepository, error) {
+	conf := config.Conf.Discord.Settings
+	switch conf.Backend {
+	case "memory":
+		// nothing to cache, it's already in memory
+		return guildsettings.NewMemoryGuildSettingsRepository(), nil
+	case "file":
+		backend, err := guildsettings.NewFileGuildSettingsRepository(conf.Dir)
+		if err != nil {
+			return nil, err
+		}
+		return guildsettings.NewCachedGuildSettingsRepository(backend, conf.CacheSize, conf.CacheTTL), nil
+	}
+	return nil, fmt.Errorf("unknown settings backend %q, use memory or file", conf.Backend)
 }
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/marouane-souiri/vocalize/internal/config"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+
+	"github.com/marouane-souiri/vocalize/internal/implementation/ratelimiter"
+	"github.com/marouane-souiri/vocalize/internal/implementation/ratelimitproxy"
+	"github.com/marouane-souiri/vocalize/internal/implementation/requester"
+)
+
+func main() {
+	if err := config.Load(); err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	proxy, err := ratelimitproxy.NewProxy(&ratelimitproxy.ProxyOptions{
+		Upstream: requester.APIBaseURL,
+		NewLimiter: func() interfaces.RateLimiter {
+			return ratelimiter.NewRateLimiter()
+		},
+		Secret: config.Conf.Discord.RateLimitProxy.Secret,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create rate limit proxy: %v", err)
+	}
+
+	addr := config.Conf.Discord.RateLimitProxy.Addr
+	log.Printf("[RateLimitProxy] Listening on %s", addr)
+	if err := http.ListenAndServe(addr, proxy); err != nil {
+		log.Fatalf("Failed to run rate limit proxy: %v", err)
+	}
+}
@@ -0,0 +1,273 @@
+package commands
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/implementation/commandsmanager"
+	"github.com/marouane-souiri/vocalize/internal/implementation/middlewares"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+const (
+	helpColor = 0x5865F2
+	// lines of the command list per page
+	helpPageSize = 15
+	// category of the commands that don't declare one
+	helpOtherCategory = "Other"
+)
+
+// HelpCommand lists the commands the user can run, or describes one of them.
+type HelpCommand struct {
+	commandsmanager.BaseCommandImpl
+	manager  interfaces.CommandsManager
+	ownerIDs []string
+}
+
+// NewHelpCommand routes the page buttons of the help through router,
+// ownerIDs are the users that see owner only commands.
+func NewHelpCommand(manager interfaces.CommandsManager, router interfaces.InteractionsRouter, ownerIDs []string) interfaces.BaseCommand {
+	c := &HelpCommand{manager: manager, ownerIDs: ownerIDs}
+	c.Name = "help"
+	c.Aliases = []string{"h", "commands"}
+	c.Description = "List the commands, or show how to use one"
+	c.Category = "General"
+	c.Arguments = []domain.Argument{
+		{Name: "command", Description: "The command to describe, e.g. voice set", Type: domain.ArgumentType_REST, Optional: true},
+	}
+	c.ApplicationCommand = &domain.ApplicationCommand{}
+	c.Autocomplete = map[string]interfaces.AutocompleteProvider{
+		"command": c.suggestCommands,
+	}
+
+	// the page and who asked are in the custom ID, the pages keep working after a restart
+	router.HandleComponent("help:page:{user_id}:{page}:{prefix}", c.turnPage)
+	return c
+}
+
+func (cmd *HelpCommand) Run(c interfaces.Client, ctx interfaces.CommandContext) error {
+	if name := ctx.GetArgs().String("command"); name != "" {
+		return cmd.describe(c, ctx, name)
+	}
+	return ctx.Reply(ctx.Context(), cmd.page(c, ctx, ctx.GetPrefix(), 0))
+}
+
+// describe answers with the usage, aliases, subcommands and requirements of a command.
+func (cmd *HelpCommand) describe(c interfaces.Client, ctx interfaces.CommandContext, name string) error {
+	command, path, input, ok := cmd.manager.ResolveCommand(name)
+	// words left over aren't subcommands, don't describe the default subcommand instead
+	if !ok || strings.TrimSpace(input) != "" || !cmd.canRun(c, command, ctx) {
+		return &domain.CommandDeniedError{Reason: fmt.Sprintf("There's no command named `%s`.", name)}
+	}
+	if top, ok := cmd.manager.GetCommand(path[0]); ok {
+		// the path can start with an alias
+		path[0] = top.GetName()
+	}
+	invoked := ctx.GetPrefix() + strings.Join(path, " ")
+
+	embed := domain.Embed{
+		Title:       domain.Usage(invoked, command.GetArguments()),
+		Description: command.GetDescription(),
+		Color:       helpColor,
+	}
+	if aliases := command.GetAliases(); len(aliases) > 0 {
+		embed.Fields = append(embed.Fields, domain.EmbedField{Name: "Aliases", Value: "`" + strings.Join(aliases, "`, `") + "`", Inline: true})
+	}
+	if subcommands := cmd.runnable(c, ctx, command.GetSubcommands()); len(subcommands) > 0 {
+		lines := make([]string, len(subcommands))
+		for i, sub := range subcommands {
+			lines[i] = fmt.Sprintf("`%s %s` %s", invoked, sub.GetName(), sub.GetDescription())
+		}
+		if def, ok := command.GetDefaultSubcommand(); ok {
+			lines = append(lines, fmt.Sprintf("Runs `%s` when none is given.", def.GetName()))
+		}
+		embed.Fields = append(embed.Fields, domain.EmbedField{Name: "Subcommands", Value: strings.Join(lines, "\n")})
+	}
+	if arguments := command.GetArguments(); len(arguments) > 0 {
+		lines := make([]string, len(arguments))
+		for i, argument := range arguments {
+			description := argument.Description
+			if description == "" {
+				description = argument.Type.String()
+			}
+			lines[i] = fmt.Sprintf("`%s` %s", argument.Placeholder(), description)
+		}
+		embed.Fields = append(embed.Fields, domain.EmbedField{Name: "Arguments", Value: strings.Join(lines, "\n")})
+	}
+	if cooldown := command.GetLimits().Cooldown; cooldown != nil && cooldown.Uses > 0 {
+		embed.Fields = append(embed.Fields, domain.EmbedField{
+			Name:   "Cooldown",
+			Value:  fmt.Sprintf("%d per %s per %s", cooldown.Uses, cooldown.Window, cooldown.Scope),
+			Inline: true,
+		})
+	}
+	// a subcommand can only be run by those who can run its group
+	requirements := middlewares.PathRequirements(command)
+	if names := requirements.UserPermissions.Names(); len(names) > 0 {
+		embed.Fields = append(embed.Fields, domain.EmbedField{Name: "Permissions", Value: strings.Join(names, ", "), Inline: true})
+	}
+	if names := requirements.BotPermissions.Names(); len(names) > 0 {
+		embed.Fields = append(embed.Fields, domain.EmbedField{Name: "Bot permissions", Value: strings.Join(names, ", "), Inline: true})
+	}
+	if flags := requirementFlags(requirements); len(flags) > 0 {
+		embed.Fields = append(embed.Fields, domain.EmbedField{Name: "Only", Value: strings.Join(flags, ", "), Inline: true})
+	}
+	if top, ok := cmd.manager.GetCommand(path[0]); ok && top.GetApplicationCommand() != nil && ctx.GetPrefix() != "/" {
+		embed.Footer = domain.EmbedFooter{Text: "Also available as /" + strings.Join(path, " ")}
+	}
+
+	return ctx.ReplyEmbed(ctx.Context(), embed)
+}
+
+func requirementFlags(requirements domain.CommandRequirements) []string {
+	var flags []string
+	if requirements.OwnerOnly {
+		flags = append(flags, "bot owners")
+	}
+	if requirements.GuildOnly {
+		flags = append(flags, "in servers")
+	}
+	if requirements.DMOnly {
+		flags = append(flags, "in DMs")
+	}
+	if requirements.NSFWOnly {
+		flags = append(flags, "in age-restricted channels")
+	}
+	return flags
+}
+
+// page renders a page of the command list, by category, with the buttons to the other pages.
+func (cmd *HelpCommand) page(c interfaces.Client, ctx interfaces.CommandContext, prefix string, page int) *domain.InteractionResponseData {
+	lines := cmd.listLines(c, ctx, prefix)
+	pages := max(1, (len(lines)+helpPageSize-1)/helpPageSize)
+	page = min(max(page, 0), pages-1)
+
+	embed := domain.Embed{
+		Title:       "Commands",
+		Description: strings.Join(lines[page*helpPageSize:min(len(lines), (page+1)*helpPageSize)], "\n"),
+		Color:       helpColor,
+		Footer:      domain.EmbedFooter{Text: fmt.Sprintf("%shelp <command> for more · Page %d/%d", prefix, page+1, pages)},
+	}
+	data := &domain.InteractionResponseData{Embeds: []domain.Embed{embed}}
+	if pages > 1 {
+		button := func(label string, to int, disabled bool) domain.Component {
+			return domain.Component{
+				Type:     domain.ComponentType_BUTTON,
+				Style:    domain.ButtonStyle_SECONDARY,
+				Label:    label,
+				CustomID: domain.CustomID("help", "page", ctx.GetAuthor().ID, strconv.Itoa(to), base64.RawURLEncoding.EncodeToString([]byte(prefix))),
+				Disabled: disabled,
+			}
+		}
+		data.Components = []domain.Component{domain.NewActionRow(
+			button("Previous", page-1, page == 0),
+			button("Next", page+1, page == pages-1),
+		)}
+	}
+	return data
+}
+
+// listLines lists the commands the user can run, under the name of their category.
+func (cmd *HelpCommand) listLines(c interfaces.Client, ctx interfaces.CommandContext, prefix string) []string {
+	byCategory := make(map[string][]interfaces.BaseCommand)
+	for _, command := range cmd.runnable(c, ctx, cmd.manager.GetCommands()) {
+		category := command.GetCategory()
+		if category == "" {
+			category = helpOtherCategory
+		}
+		byCategory[category] = append(byCategory[category], command)
+	}
+
+	categories := make([]string, 0, len(byCategory))
+	for category := range byCategory {
+		categories = append(categories, category)
+	}
+	slices.SortFunc(categories, func(a, b string) int {
+		// uncategorized commands come last
+		if (a == helpOtherCategory) != (b == helpOtherCategory) {
+			if a == helpOtherCategory {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a, b)
+	})
+
+	var lines []string
+	for _, category := range categories {
+		lines = append(lines, "**"+category+"**")
+		for _, command := range byCategory[category] {
+			lines = append(lines, fmt.Sprintf("`%s` %s", domain.Usage(prefix+command.GetName(), command.GetArguments()), command.GetDescription()))
+		}
+	}
+	return lines
+}
+
+// runnable keeps the commands whose requirements the user meets, guards aren't run.
+func (cmd *HelpCommand) runnable(c interfaces.Client, ctx interfaces.CommandContext, commands []interfaces.BaseCommand) []interfaces.BaseCommand {
+	var kept []interfaces.BaseCommand
+	for _, command := range commands {
+		if cmd.canRun(c, command, ctx) {
+			kept = append(kept, command)
+		}
+	}
+	return kept
+}
+
+func (cmd *HelpCommand) canRun(c interfaces.Client, command interfaces.BaseCommand, ctx interfaces.CommandContext) bool {
+	err := middlewares.CheckRequirements(c, command, ctx, cmd.ownerIDs)
+	if err != nil && !isDenied(err) {
+		log.Printf("[Help] Error checking the requirements of %s: %v", command.GetName(), err)
+	}
+	return err == nil
+}
+
+func isDenied(err error) bool {
+	var deniedErr *domain.CommandDeniedError
+	return errors.As(err, &deniedErr)
+}
+
+func (cmd *HelpCommand) turnPage(c interfaces.Client, ctx interfaces.ComponentContext) error {
+	if ctx.GetAuthor().ID != ctx.GetParam("user_id") {
+		ctx.SetEphemeral(true)
+		return ctx.Reply(ctx.Context(), &domain.InteractionResponseData{Content: "Only the one who asked can turn these pages, use help yourself."})
+	}
+	page, err := strconv.Atoi(ctx.GetParam("page"))
+	if err != nil {
+		return fmt.Errorf("[Help] invalid page %q: %w", ctx.GetParam("page"), err)
+	}
+	prefix, err := base64.RawURLEncoding.DecodeString(ctx.GetParam("prefix"))
+	if err != nil {
+		return fmt.Errorf("[Help] invalid prefix %q: %w", ctx.GetParam("prefix"), err)
+	}
+	return ctx.Update(ctx.Context(), cmd.page(c, ctx, string(prefix), page))
+}
+
+// suggestCommands completes the names of the commands and subcommands.
+// Requirements can't be checked while typing, only owner only commands are left out for the others.
+func (cmd *HelpCommand) suggestCommands(c interfaces.Client, ctx interfaces.AutocompleteContext) ([]domain.ApplicationCommandOptionChoice, error) {
+	typed := strings.ToLower(strings.TrimSpace(fmt.Sprint(ctx.GetFocused().Value)))
+	owner := slices.Contains(cmd.ownerIDs, ctx.GetAuthor().ID)
+	var choices []domain.ApplicationCommandOptionChoice
+	var walk func(prefix string, commands []interfaces.BaseCommand)
+	walk = func(prefix string, commands []interfaces.BaseCommand) {
+		for _, command := range commands {
+			if command.GetRequirements().OwnerOnly && !owner {
+				continue
+			}
+			name := prefix + command.GetName()
+			if strings.Contains(name, typed) {
+				choices = append(choices, domain.ApplicationCommandOptionChoice{Name: name, Value: name})
+			}
+			walk(name+" ", command.GetSubcommands())
+		}
+	}
+	walk("", cmd.manager.GetCommands())
+	return choices, nil
+}
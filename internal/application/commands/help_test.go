@@ -0,0 +1,210 @@
+package commands
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/implementation/commandsmanager"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+const (
+	guildID = "100000000000000001"
+	userID  = "300000000000000003"
+	ownerID = "500000000000000005"
+)
+
+type fakeRouter struct {
+	interfaces.InteractionsRouter
+	components map[string]interfaces.ComponentHandler
+}
+
+func (f *fakeRouter) HandleComponent(pattern string, handler interfaces.ComponentHandler) {
+	f.components[pattern] = handler
+}
+
+// fakeContext records the answers of a command, or of a click on one of its components.
+type fakeContext struct {
+	interfaces.ComponentContext
+	guildID   string
+	author    *domain.User
+	args      *domain.Args
+	params    map[string]string
+	replies   []*domain.InteractionResponseData
+	embeds    []domain.Embed
+	updates   []*domain.InteractionResponseData
+	ephemeral bool
+}
+
+func newContext(guild, author string) *fakeContext {
+	return &fakeContext{guildID: guild, author: &domain.User{ID: author}, args: domain.NewArgs()}
+}
+
+func (f *fakeContext) GetGuildID() string                  { return f.guildID }
+func (f *fakeContext) GetChannelID() string                { return "200000000000000002" }
+func (f *fakeContext) GetAuthor() *domain.User             { return f.author }
+func (f *fakeContext) GetMember() *domain.Member           { return nil }
+func (f *fakeContext) GetInteraction() *domain.Interaction { return nil }
+func (f *fakeContext) GetArgs() *domain.Args               { return f.args }
+func (f *fakeContext) GetPrefix() string                   { return "." }
+func (f *fakeContext) GetParam(name string) string         { return f.params[name] }
+func (f *fakeContext) SetEphemeral(ephemeral bool)         { f.ephemeral = ephemeral }
+func (f *fakeContext) Context() context.Context            { return context.Background() }
+
+func (f *fakeContext) Reply(ctx context.Context, data *domain.InteractionResponseData) error {
+	f.replies = append(f.replies, data)
+	return nil
+}
+
+func (f *fakeContext) ReplyEmbed(ctx context.Context, embed domain.Embed) error {
+	f.embeds = append(f.embeds, embed)
+	return nil
+}
+
+func (f *fakeContext) Update(ctx context.Context, data *domain.InteractionResponseData) error {
+	f.updates = append(f.updates, data)
+	return nil
+}
+
+type fakeCommand struct {
+	commandsmanager.BaseCommandImpl
+}
+
+func (f *fakeCommand) Run(c interfaces.Client, ctx interfaces.CommandContext) error { return nil }
+
+func newFakeCommand(name, category string, requirements domain.CommandRequirements) *fakeCommand {
+	command := &fakeCommand{}
+	command.Name = name
+	command.Description = "The " + name + " command"
+	command.Category = category
+	command.Requirements = requirements
+	return command
+}
+
+// newTestHelp registers the help with count music commands and an owner only admin group,
+// whose reload subcommand has no requirements of its own.
+func newTestHelp(count int) (*HelpCommand, *fakeRouter) {
+	manager := commandsmanager.NewCommandsManager(&commandsmanager.CommandsManagerOptions{})
+	router := &fakeRouter{components: make(map[string]interfaces.ComponentHandler)}
+	help := NewHelpCommand(manager, router, []string{ownerID}).(*HelpCommand)
+	manager.AddCommand(help)
+	for i := range count {
+		manager.AddCommand(newFakeCommand(fmt.Sprintf("music%02d", i), "Music", domain.CommandRequirements{}))
+	}
+	admin := commandsmanager.NewGroupCommand("admin", "Manage the bot", newFakeCommand("reload", "", domain.CommandRequirements{}))
+	admin.Requirements = domain.CommandRequirements{OwnerOnly: true}
+	manager.AddCommand(admin)
+	return help, router
+}
+
+func footer(data *domain.InteractionResponseData) string {
+	return data.Embeds[0].Footer.Text
+}
+
+func TestHelp_Pages(t *testing.T) {
+	help, router := newTestHelp(20)
+	ctx := newContext("", userID)
+
+	if err := help.Run(nil, ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// 2 category lines, help and the 20 music commands
+	first := ctx.replies[0]
+	if !strings.HasSuffix(footer(first), "Page 1/2") || strings.Count(first.Embeds[0].Description, "\n") != helpPageSize-1 {
+		t.Fatalf("Expected a full first page out of 2, got %q: %s", footer(first), first.Embeds[0].Description)
+	}
+	buttons := first.Components[0].Components
+	if !buttons[0].Disabled || buttons[1].Disabled {
+		t.Fatalf("Expected only Next to be enabled on the first page, got %+v", buttons)
+	}
+	prefix := base64.RawURLEncoding.EncodeToString([]byte("."))
+	if buttons[1].CustomID != domain.CustomID("help", "page", userID, "1", prefix) {
+		t.Fatalf("Expected Next to go to the second page, got %q", buttons[1].CustomID)
+	}
+
+	turnPage := router.components["help:page:{user_id}:{page}:{prefix}"]
+	for page, want := range map[string]string{"1": "Page 2/2", "7": "Page 2/2", "-1": "Page 1/2"} {
+		click := newContext("", userID)
+		click.params = map[string]string{"user_id": userID, "page": page, "prefix": prefix}
+		if err := turnPage(nil, click); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(click.updates) != 1 || !strings.HasSuffix(footer(click.updates[0]), want) {
+			t.Fatalf("page %s: expected the message to show %s, got %+v", page, want, click.updates)
+		}
+	}
+
+	click := newContext("", userID)
+	click.params = map[string]string{"user_id": userID, "page": "next", "prefix": prefix}
+	if err := turnPage(nil, click); err == nil {
+		t.Fatalf("Expected an invalid page to be reported")
+	}
+}
+
+func TestHelp_OnlyTheAskerTurnsPages(t *testing.T) {
+	help, router := newTestHelp(20)
+	turnPage := router.components["help:page:{user_id}:{page}:{prefix}"]
+
+	click := newContext("", "700000000000000007")
+	click.params = map[string]string{"user_id": userID, "page": "1", "prefix": base64.RawURLEncoding.EncodeToString([]byte("."))}
+	if err := turnPage(nil, click); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(click.updates) != 0 || len(click.replies) != 1 || !click.ephemeral {
+		t.Fatalf("Expected an ephemeral refusal without turning the page, got %+v", click)
+	}
+
+	// a single page has no buttons
+	help, _ = newTestHelp(0)
+	ctx := newContext("", userID)
+	if err := help.Run(nil, ctx); err != nil || len(ctx.replies[0].Components) != 0 {
+		t.Fatalf("Expected no buttons for a single page, got %+v, %v", ctx.replies, err)
+	}
+}
+
+func TestHelp_HidesWhatTheUserCantRun(t *testing.T) {
+	help, _ := newTestHelp(0)
+
+	ctx := newContext("", userID)
+	if err := help.Run(nil, ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if list := ctx.replies[0].Embeds[0].Description; strings.Contains(list, "admin") {
+		t.Fatalf("Expected the owner only group to be hidden, got %s", list)
+	}
+
+	// the subcommand is restricted by its group
+	ctx.args.Add("command", "admin reload")
+	if err := help.Run(nil, ctx); err == nil || len(ctx.embeds) != 0 {
+		t.Fatalf("Expected the subcommand of the owner only group to be hidden, got %+v", ctx.embeds)
+	}
+
+	owner := newContext("", ownerID)
+	owner.args.Add("command", "admin reload")
+	if err := help.Run(nil, owner); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var only string
+	for _, field := range owner.embeds[0].Fields {
+		if field.Name == "Only" {
+			only = field.Value
+		}
+	}
+	if only != "bot owners" {
+		t.Fatalf("Expected the requirements of the group to be shown, got %+v", owner.embeds[0].Fields)
+	}
+
+	guildOnly, _ := newTestHelp(0)
+	guildOnly.manager.AddCommand(newFakeCommand("join", "Music", domain.CommandRequirements{GuildOnly: true}))
+	dm := newContext("", userID)
+	guild := newContext(guildID, userID)
+	guildOnly.Run(nil, dm)
+	guildOnly.Run(nil, guild)
+	if strings.Contains(dm.replies[0].Embeds[0].Description, "join") || !strings.Contains(guild.replies[0].Embeds[0].Description, "join") {
+		t.Fatalf("Expected server only commands in servers only, got %q and %q", dm.replies[0].Embeds[0].Description, guild.replies[0].Embeds[0].Description)
+	}
+}
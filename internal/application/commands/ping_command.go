@@ -14,12 +14,13 @@ func NewPingCommand() interfaces.BaseCommand {
 	c := &PingCommand{}
 	c.Name = "ping"
 	c.Description = "A ping command"
+	c.Category = "General"
+	c.ApplicationCommand = &domain.ApplicationCommand{}
 	return c
 }
 
 func (cmd *PingCommand) Run(c interfaces.Client, ctx interfaces.CommandContext) error {
-	c.SendMessage(ctx.GetChannelID(), &domain.SendMessage{
+	return ctx.Reply(ctx.Context(), &domain.InteractionResponseData{
 		Content: "Pong !!",
 	})
-	return nil
 }
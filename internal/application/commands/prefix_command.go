@@ -0,0 +1,162 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/implementation/commandsmanager"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// NewPrefixCommand lets server managers change the prefixes of their server,
+// ".prefix" alone lists them.
+func NewPrefixCommand(settings interfaces.GuildSettingsRepository, prefixResolver interfaces.PrefixResolver) interfaces.BaseCommand {
+	manage := domain.CommandRequirements{GuildOnly: true, UserPermissions: domain.Permissions_MANAGE_GUILD}
+	prefixArgument := domain.Argument{Name: "prefix", Description: fmt.Sprintf("Up to %d characters, no spaces", domain.MaxPrefixLength), Type: domain.ArgumentType_STRING}
+
+	list := &PrefixListCommand{prefixResolver: prefixResolver}
+	list.Name = "list"
+	list.Aliases = []string{"ls"}
+	list.Description = "Show the prefixes of this server"
+	list.Requirements = domain.CommandRequirements{GuildOnly: true}
+
+	add := &PrefixAddCommand{settings: settings}
+	add.Name = "add"
+	add.Description = "Add a prefix"
+	add.Arguments = []domain.Argument{prefixArgument}
+	add.Requirements = manage
+
+	remove := &PrefixRemoveCommand{settings: settings}
+	remove.Name = "remove"
+	remove.Aliases = []string{"rm"}
+	remove.Description = "Remove a prefix"
+	remove.Arguments = []domain.Argument{prefixArgument}
+	remove.Requirements = manage
+
+	reset := &PrefixResetCommand{settings: settings, prefixResolver: prefixResolver}
+	reset.Name = "reset"
+	reset.Description = "Go back to the default prefix"
+	reset.Requirements = manage
+
+	c := commandsmanager.NewGroupCommand("prefix", "Manage the prefixes of this server", list, add, remove, reset)
+	c.Aliases = []string{"prefixes"}
+	c.Category = "Settings"
+	c.DefaultSubcommand = "list"
+	c.ApplicationCommand = &domain.ApplicationCommand{}
+	return c
+}
+
+type PrefixListCommand struct {
+	commandsmanager.BaseCommandImpl
+	prefixResolver interfaces.PrefixResolver
+}
+
+func (cmd *PrefixListCommand) Run(c interfaces.Client, ctx interfaces.CommandContext) error {
+	prefixes, err := cmd.prefixResolver.GetPrefixes(ctx.Context(), ctx.GetGuildID())
+	if err != nil {
+		return err
+	}
+	return ctx.Reply(ctx.Context(), &domain.InteractionResponseData{
+		Content: fmt.Sprintf("The prefixes of this server are %s, mentioning me works too.", formatPrefixes(prefixes)),
+	})
+}
+
+type PrefixAddCommand struct {
+	commandsmanager.BaseCommandImpl
+	settings interfaces.GuildSettingsRepository
+}
+
+func (cmd *PrefixAddCommand) Run(c interfaces.Client, ctx interfaces.CommandContext) error {
+	prefix := ctx.GetArgs().String("prefix")
+	if err := validatePrefix(prefix); err != nil {
+		return err
+	}
+
+	settings, err := cmd.settings.Update(ctx.Context(), ctx.GetGuildID(), func(settings *domain.GuildSettings) error {
+		if slices.Contains(settings.Prefixes, prefix) {
+			return &domain.UsageError{Reason: fmt.Sprintf("`%s` is already a prefix", prefix)}
+		}
+		if len(settings.Prefixes) >= domain.MaxGuildPrefixes {
+			return &domain.UsageError{Reason: fmt.Sprintf("a server can have %d prefixes at most, remove one first", domain.MaxGuildPrefixes)}
+		}
+		settings.Prefixes = append(settings.Prefixes, prefix)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return ctx.Reply(ctx.Context(), &domain.InteractionResponseData{
+		Content: fmt.Sprintf("Added `%s`, the prefixes are now %s.", prefix, formatPrefixes(settings.Prefixes)),
+	})
+}
+
+type PrefixRemoveCommand struct {
+	commandsmanager.BaseCommandImpl
+	settings interfaces.GuildSettingsRepository
+}
+
+func (cmd *PrefixRemoveCommand) Run(c interfaces.Client, ctx interfaces.CommandContext) error {
+	prefix := ctx.GetArgs().String("prefix")
+
+	settings, err := cmd.settings.Update(ctx.Context(), ctx.GetGuildID(), func(settings *domain.GuildSettings) error {
+		i := slices.Index(settings.Prefixes, prefix)
+		if i == -1 {
+			return &domain.UsageError{Reason: fmt.Sprintf("`%s` isn't a prefix of this server", prefix)}
+		}
+		settings.Prefixes = slices.Delete(settings.Prefixes, i, i+1)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	content := fmt.Sprintf("Removed `%s`, the prefixes are now %s.", prefix, formatPrefixes(settings.Prefixes))
+	if len(settings.Prefixes) == 0 {
+		content = fmt.Sprintf("Removed `%s`, the default prefix is back.", prefix)
+	}
+	return ctx.Reply(ctx.Context(), &domain.InteractionResponseData{Content: content})
+}
+
+type PrefixResetCommand struct {
+	commandsmanager.BaseCommandImpl
+	settings       interfaces.GuildSettingsRepository
+	prefixResolver interfaces.PrefixResolver
+}
+
+func (cmd *PrefixResetCommand) Run(c interfaces.Client, ctx interfaces.CommandContext) error {
+	_, err := cmd.settings.Update(ctx.Context(), ctx.GetGuildID(), func(settings *domain.GuildSettings) error {
+		settings.Prefixes = nil
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	prefixes, err := cmd.prefixResolver.GetPrefixes(ctx.Context(), ctx.GetGuildID())
+	if err != nil {
+		return err
+	}
+	return ctx.Reply(ctx.Context(), &domain.InteractionResponseData{
+		Content: fmt.Sprintf("The prefix is back to %s.", formatPrefixes(prefixes)),
+	})
+}
+
+func validatePrefix(prefix string) error {
+	switch {
+	case prefix == "":
+		return &domain.UsageError{Reason: "the prefix can't be empty"}
+	case len([]rune(prefix)) > domain.MaxPrefixLength:
+		return &domain.UsageError{Reason: fmt.Sprintf("the prefix can't be longer than %d characters", domain.MaxPrefixLength)}
+	case strings.IndexFunc(prefix, unicode.IsSpace) != -1:
+		return &domain.UsageError{Reason: "the prefix can't contain spaces"}
+	case strings.Contains(prefix, "`"):
+		return &domain.UsageError{Reason: "the prefix can't contain backticks"}
+	}
+	return nil
+}
+
+func formatPrefixes(prefixes []string) string {
+	return "`" + strings.Join(prefixes, "`, `") + "`"
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/implementation/guildsettings"
+	"github.com/marouane-souiri/vocalize/internal/implementation/prefixresolver"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// runPrefix runs ".prefix <sub> [prefix]" in the test guild and returns what was answered.
+func runPrefix(t *testing.T, prefix interfaces.BaseCommand, sub, arg string) (string, error) {
+	t.Helper()
+	for _, command := range prefix.GetSubcommands() {
+		if command.GetName() != sub {
+			continue
+		}
+		ctx := newContext(guildID, userID)
+		if arg != "" {
+			ctx.args.Add("prefix", arg)
+		}
+		if err := command.Run(nil, ctx); err != nil {
+			return "", err
+		}
+		return ctx.replies[0].Content, nil
+	}
+	t.Fatalf("No prefix subcommand named %s", sub)
+	return "", nil
+}
+
+func newTestPrefix() (interfaces.BaseCommand, interfaces.GuildSettingsRepository, interfaces.PrefixResolver) {
+	settings := guildsettings.NewMemoryGuildSettingsRepository()
+	resolver := prefixresolver.NewPrefixResolver(&prefixresolver.PrefixResolverOptions{Settings: settings, DefaultPrefix: "."})
+	return NewPrefixCommand(settings, resolver), settings, resolver
+}
+
+func TestPrefix_AddAndRemove(t *testing.T) {
+	prefix, _, resolver := newTestPrefix()
+
+	if content, err := runPrefix(t, prefix, "add", "!"); err != nil || !strings.Contains(content, "the prefixes are now `!`") {
+		t.Fatalf("Expected ! to be added, got %q, %v", content, err)
+	}
+	runPrefix(t, prefix, "add", "v!")
+	if prefixes, _ := resolver.GetPrefixes(context.Background(), guildID); strings.Join(prefixes, " ") != "! v!" {
+		t.Fatalf("Expected the added prefixes instead of the default one, got %v", prefixes)
+	}
+
+	var usageErr *domain.UsageError
+	if _, err := runPrefix(t, prefix, "add", "!"); !errors.As(err, &usageErr) || !strings.Contains(usageErr.Reason, "already a prefix") {
+		t.Fatalf("Expected a duplicate prefix to be refused, got %v", err)
+	}
+	if _, err := runPrefix(t, prefix, "remove", "?"); !errors.As(err, &usageErr) || !strings.Contains(usageErr.Reason, "isn't a prefix") {
+		t.Fatalf("Expected an unknown prefix not to be removed, got %v", err)
+	}
+
+	runPrefix(t, prefix, "remove", "!")
+	content, err := runPrefix(t, prefix, "remove", "v!")
+	if err != nil || !strings.Contains(content, "the default prefix is back") {
+		t.Fatalf("Expected the default prefix back after removing the last one, got %q, %v", content, err)
+	}
+	if prefixes, _ := resolver.GetPrefixes(context.Background(), guildID); len(prefixes) != 1 || prefixes[0] != "." {
+		t.Fatalf("Expected the default prefix, got %v", prefixes)
+	}
+}
+
+func TestPrefix_Limits(t *testing.T) {
+	prefix, settings, _ := newTestPrefix()
+
+	var usageErr *domain.UsageError
+	long := strings.Repeat("é", domain.MaxPrefixLength)
+	if _, err := runPrefix(t, prefix, "add", long); err != nil {
+		t.Fatalf("Expected a prefix of %d characters to be accepted, got %v", domain.MaxPrefixLength, err)
+	}
+	for _, invalid := range []string{long + "!", "v !", "`"} {
+		if _, err := runPrefix(t, prefix, "add", invalid); !errors.As(err, &usageErr) {
+			t.Fatalf("%q: expected the prefix to be refused, got %v", invalid, err)
+		}
+	}
+
+	for i := 1; i < domain.MaxGuildPrefixes; i++ {
+		if _, err := runPrefix(t, prefix, "add", fmt.Sprint(i)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if _, err := runPrefix(t, prefix, "add", "?"); !errors.As(err, &usageErr) || !strings.Contains(usageErr.Reason, "at most") {
+		t.Fatalf("Expected the prefixes past %d to be refused, got %v", domain.MaxGuildPrefixes, err)
+	}
+	if stored, _ := settings.Get(context.Background(), guildID); len(stored.Prefixes) != domain.MaxGuildPrefixes {
+		t.Fatalf("Expected %d prefixes to be stored, got %v", domain.MaxGuildPrefixes, stored.Prefixes)
+	}
+
+	content, err := runPrefix(t, prefix, "reset", "")
+	if err != nil || content != "The prefix is back to `.`." {
+		t.Fatalf("Expected the default prefix back, got %q, %v", content, err)
+	}
+	if stored, _ := settings.Get(context.Background(), guildID); len(stored.Prefixes) != 0 {
+		t.Fatalf("Expected the prefixes to be reset, got %v", stored.Prefixes)
+	}
+}
@@ -23,5 +23,11 @@ func GuildCreateHandler(c interfaces.Client) domain.ClientHandler {
 		}
 
 		c.SetGuild(&guildCreate.Guild)
+
+		for i := range guildCreate.Channels {
+			channel := &guildCreate.Channels[i]
+			channel.GuildID = guildCreate.ID
+			c.SetChannel(channel)
+		}
 	}
 }
@@ -1,15 +1,20 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"log"
+	"time"
 
 	"github.com/marouane-souiri/vocalize/internal/domain"
 	"github.com/marouane-souiri/vocalize/internal/interfaces"
 )
 
+// bound of the cleanup of a guild the bot left
+const guildCleanupTimeout = 10 * time.Second
+
 // https://discord.com/developers/docs/events/gateway-events#guild-delete
-func GuildDeleteHandler(c interfaces.Client) domain.ClientHandler {
+func GuildDeleteHandler(c interfaces.Client, settings interfaces.GuildSettingsRepository) domain.ClientHandler {
 	return func(event json.RawMessage) {
 		var guildDelete domain.GuildDeleteEvent
 
@@ -20,8 +25,15 @@ func GuildDeleteHandler(c interfaces.Client) domain.ClientHandler {
 
 		if guildDelete.Unavailable {
 			c.SetGuild(&domain.Guild{ID: guildDelete.ID, Unavailable: true})
-		} else {
-			c.DelGuild(guildDelete.ID)
+			return
+		}
+
+		// the bot was removed from the guild, an outage sets Unavailable instead
+		c.DelGuild(guildDelete.ID)
+		ctx, cancel := context.WithTimeout(context.Background(), guildCleanupTimeout)
+		defer cancel()
+		if err := settings.Delete(ctx, guildDelete.ID); err != nil {
+			log.Printf("[Handlers] Error deleting the settings of guild %s: %v", guildDelete.ID, err)
 		}
 	}
 }
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// https://discord.com/developers/docs/events/gateway-events#interaction-create
+func InteractionCreateHandler(ir interfaces.InteractionsRouter, responder interfaces.InteractionResponder) domain.ClientHandler {
+	return func(event json.RawMessage) {
+		var interactionCreate domain.InteractionCreateEvent
+
+		if err := json.Unmarshal(event, &interactionCreate); err != nil {
+			log.Printf("[Handlers] Error unmarshaling INTERACTION_CREATE event: %v", err)
+			return
+		}
+
+		ir.HandleInteraction(&interactionCreate.Interaction, responder)
+	}
+}
@@ -18,6 +18,10 @@ func MemberAddHandler(c interfaces.Client) domain.ClientHandler {
 			return
 		}
 
+		if guildMemberAdd.User != nil {
+			guildMemberAdd.ID = guildMemberAdd.User.ID
+		}
+
 		c.SetMember(&guildMemberAdd.Member)
 	}
 }
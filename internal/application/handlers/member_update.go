@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"log"
+	"time"
 
 	"github.com/marouane-souiri/vocalize/internal/domain"
 	"github.com/marouane-souiri/vocalize/internal/interfaces"
@@ -18,9 +20,13 @@ func MemberUpdateHandler(c interfaces.Client) domain.ClientHandler {
 			return
 		}
 
-		oldMember, err := c.GetMember(guildMemberUpdate.ID, guildMemberUpdate.GuildID)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		oldMember, err := c.GetMember(ctx, guildMemberUpdate.User.ID, guildMemberUpdate.GuildID)
 		if err != nil {
 			log.Printf("[Handlers] Error in GUILD_MEMBER_UPDATE event: %v", err)
+			return
 		}
 		newMember := *oldMember
 
@@ -32,6 +38,7 @@ func MemberUpdateHandler(c interfaces.Client) domain.ClientHandler {
 		newMember.Deaf = guildMemberUpdate.Deaf
 		newMember.JoinedAt = guildMemberUpdate.JoinedAt
 		newMember.Roles = guildMemberUpdate.Roles
+		newMember.User = &guildMemberUpdate.User
 
 		c.SetMember(&newMember)
 	}
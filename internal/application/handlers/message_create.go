@@ -1,16 +1,26 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/marouane-souiri/vocalize/internal/domain"
 	"github.com/marouane-souiri/vocalize/internal/interfaces"
 )
 
+const (
+	// bound of the cache and API lookups made to convert arguments
+	argumentsTimeout = 5 * time.Second
+	// same as slash commands, whose token expires after 15 minutes
+	commandTimeout = 15 * time.Minute
+)
+
 // https://discord.com/developers/docs/events/gateway-events#message-create
-func MessageCreateHandler(c interfaces.Client, commandsManager interfaces.CommandsManager, commandsCtxMaker interfaces.CommandsContextMaker) domain.ClientHandler {
+func MessageCreateHandler(c interfaces.Client, commandsManager interfaces.CommandsManager, commandsCtxMaker interfaces.CommandsContextMaker, argumentsParser interfaces.ArgumentsParser, prefixResolver interfaces.PrefixResolver, errorHandler interfaces.CommandErrorHandler) domain.ClientHandler {
 	return func(event json.RawMessage) {
 		var messageCreate domain.MessageCreateEvent
 
@@ -18,19 +28,108 @@ func MessageCreateHandler(c interfaces.Client, commandsManager interfaces.Comman
 			log.Printf("[Handlers] Error unmarshaling MESSAGE_CREATE event: %v", err)
 			return
 		}
+		// bots answering each other's suggestions and usage errors would loop
+		if messageCreate.Author.Bot {
+			return
+		}
 
-		prefix := "."
+		guildID := ""
+		if messageCreate.GuildID != nil {
+			guildID = *messageCreate.GuildID
+		}
 
-		if strings.HasPrefix(messageCreate.Content, prefix) {
-			cmdName := strings.TrimPrefix(messageCreate.Content, prefix)
-			log.Print("command name is: ", cmdName)
-			cmd, ok := commandsManager.GetCommand(cmdName)
-			if ok {
-				ctx := commandsCtxMaker.FromMessageEvent(messageCreate)
-				cmd.Run(c, ctx)
-			} else {
-				log.Print("Command not found")
-			}
+		parseCtx, cancelParse := context.WithTimeout(context.Background(), argumentsTimeout)
+		defer cancelParse()
+		match, err := prefixResolver.MatchPrefix(parseCtx, guildID, messageCreate.Content)
+		if err != nil {
+			log.Printf("[Handlers] Error matching the prefix of message %s: %v", messageCreate.ID, err)
+			return
+		}
+		if match == nil {
+			return
+		}
+		if match.Mention && strings.TrimSpace(match.Line) == "" {
+			// only the mention, tell how to call the bot here
+			sendPrefixes(parseCtx, c, prefixResolver, guildID, messageCreate.ChannelID)
+			return
+		}
+
+		prefix := match.Prefix
+		cmd, path, input, ok := commandsManager.ResolveCommand(match.Line)
+		if !ok {
+			suggestCommands(parseCtx, c, commandsManager, prefix, match.Line, messageCreate.ChannelID)
+			return
+		}
+		cmdName := strings.Join(path, " ")
+		failure := &interfaces.CommandFailure{
+			Command:   cmd,
+			Invoked:   prefix + cmdName,
+			UserID:    messageCreate.Author.ID,
+			GuildID:   guildID,
+			ChannelID: messageCreate.ChannelID,
+		}
+
+		args, err := argumentsParser.Parse(parseCtx, guildID, cmd.GetArguments(), input)
+		if err != nil {
+			failure.Err = err
+			handleCommandError(c, errorHandler, failure)
+			return
 		}
+
+		runCtx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		defer cancel()
+		ctx := commandsCtxMaker.FromMessageEvent(runCtx, messageCreate, args, prefix, cmdName, c.GetAPIRequester())
+		if err := commandsManager.RunCommand(c, cmd, ctx); err != nil {
+			failure.Err = err
+			handleCommandError(c, errorHandler, failure)
+		}
+	}
+}
+
+// suggestCommands answers unknown commands with the closest ones, if any.
+// Nothing is sent otherwise, the message might be for another bot sharing the prefix.
+func suggestCommands(ctx context.Context, c interfaces.Client, commandsManager interfaces.CommandsManager, prefix, line, channelID string) {
+	words := strings.Fields(line)
+	if len(words) == 0 {
+		return
+	}
+	name := words[0]
+	suggestions := commandsManager.SuggestCommands(name)
+	if len(suggestions) == 0 {
+		return
+	}
+	for i, suggestion := range suggestions {
+		suggestions[i] = "`" + prefix + suggestion + "`"
+	}
+	_, err := c.SendMessage(ctx, channelID, &domain.SendMessage{
+		Content: fmt.Sprintf("There's no command named `%s`, did you mean %s?", name, strings.Join(suggestions, " or ")),
+	})
+	if err != nil {
+		log.Printf("[Handlers] Error sending command suggestions: %v", err)
+	}
+}
+
+func sendPrefixes(ctx context.Context, c interfaces.Client, prefixResolver interfaces.PrefixResolver, guildID, channelID string) {
+	prefixes, err := prefixResolver.GetPrefixes(ctx, guildID)
+	if err != nil {
+		log.Printf("[Handlers] Error getting the prefixes of guild %s: %v", guildID, err)
+		return
+	}
+	_, err = c.SendMessage(ctx, channelID, &domain.SendMessage{
+		Content: fmt.Sprintf("My prefixes here are `%s`, try `%shelp`.", strings.Join(prefixes, "`, `"), prefixes[0]),
+	})
+	if err != nil {
+		log.Printf("[Handlers] Error sending the prefixes: %v", err)
+	}
+}
+
+// handleCommandError answers a failed command with what the error handler tells the user.
+func handleCommandError(c interfaces.Client, errorHandler interfaces.CommandErrorHandler, failure *interfaces.CommandFailure) {
+	content := errorHandler.Handle(failure)
+
+	ctx, cancel := context.WithTimeout(context.Background(), argumentsTimeout)
+	defer cancel()
+	if _, err := c.SendMessage(ctx, failure.ChannelID, &domain.SendMessage{Content: content}); err != nil {
+		log.Printf("[Handlers] Error answering %s: %v", failure.Invoked, err)
 	}
 }
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// https://discord.com/developers/docs/events/gateway-events#message-reaction-add
+func MessageReactionAddHandler(rm interfaces.ReactionsManager) domain.ClientHandler {
+	return func(event json.RawMessage) {
+		var messageReactionAdd domain.MessageReactionAddEvent
+
+		if err := json.Unmarshal(event, &messageReactionAdd); err != nil {
+			log.Printf("[Handlers] Error unmarshaling MESSAGE_REACTION_ADD event: %v", err)
+			return
+		}
+
+		rm.HandleReactionAdd(messageReactionAdd)
+	}
+}
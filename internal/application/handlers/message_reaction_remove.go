@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// https://discord.com/developers/docs/events/gateway-events#message-reaction-remove
+func MessageReactionRemoveHandler(rm interfaces.ReactionsManager) domain.ClientHandler {
+	return func(event json.RawMessage) {
+		var messageReactionRemove domain.MessageReactionRemoveEvent
+
+		if err := json.Unmarshal(event, &messageReactionRemove); err != nil {
+			log.Printf("[Handlers] Error unmarshaling MESSAGE_REACTION_REMOVE event: %v", err)
+			return
+		}
+
+		rm.HandleReactionRemove(messageReactionRemove)
+	}
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// https://discord.com/developers/docs/events/gateway-events#message-reaction-remove-all
+func MessageReactionRemoveAllHandler(rm interfaces.ReactionsManager) domain.ClientHandler {
+	return func(event json.RawMessage) {
+		var messageReactionRemoveAll domain.MessageReactionRemoveAllEvent
+
+		if err := json.Unmarshal(event, &messageReactionRemoveAll); err != nil {
+			log.Printf("[Handlers] Error unmarshaling MESSAGE_REACTION_REMOVE_ALL event: %v", err)
+			return
+		}
+
+		rm.HandleReactionRemoveAll(messageReactionRemoveAll)
+	}
+}
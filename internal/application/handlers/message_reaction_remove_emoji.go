@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// https://discord.com/developers/docs/events/gateway-events#message-reaction-remove-emoji
+func MessageReactionRemoveEmojiHandler(rm interfaces.ReactionsManager) domain.ClientHandler {
+	return func(event json.RawMessage) {
+		var messageReactionRemoveEmoji domain.MessageReactionRemoveEmojiEvent
+
+		if err := json.Unmarshal(event, &messageReactionRemoveEmoji); err != nil {
+			log.Printf("[Handlers] Error unmarshaling MESSAGE_REACTION_REMOVE_EMOJI event: %v", err)
+			return
+		}
+
+		rm.HandleReactionRemoveEmoji(messageReactionRemoveEmoji)
+	}
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// https://discord.com/developers/docs/events/gateway-events#ready
+//
+// Syncs the slash commands once the application ID is known.
+// guildID registers them in a single guild, they show up instantly there unlike global ones,
+// leave it empty to register them globally.
+func ReadyHandler(c interfaces.Client, commandsManager interfaces.CommandsManager, guildID string) domain.ClientHandler {
+	return func(event json.RawMessage) {
+		var ready domain.ReadyEvent
+
+		if err := json.Unmarshal(event, &ready); err != nil {
+			log.Printf("[Handlers] Error unmarshaling READY event: %v", err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		sync, err := commandsManager.SyncApplicationCommands(ctx, c.GetAPIRequester(), ready.Application.ID, guildID)
+		if err != nil {
+			log.Printf("[Handlers] Error syncing application commands: %v", err)
+			return
+		}
+		log.Printf("[Handlers] Application commands synced: %d created, %d updated, %d deleted, %d unchanged",
+			len(sync.Created), len(sync.Updated), len(sync.Deleted), len(sync.Unchanged))
+	}
+}
@@ -1,10 +1,48 @@
 package config
 
-import "github.com/caarlos0/env/v11"
+import (
+	"time"
+
+	"github.com/caarlos0/env/v11"
+)
 
 type Config struct {
 	Discord struct {
 		Token string `env:"TOKEN"`
+		// how many times a failed REST request is retried
+		MaxRetries int `env:"MAX_RETRIES" envDefault:"3"`
+		// when set, slash commands are registered in this guild only, they update instantly there
+		CommandsGuildID string `env:"COMMANDS_GUILD_ID"`
+		// hex encoded public key of the application, used to verify HTTP interactions
+		PublicKey string `env:"PUBLIC_KEY"`
+		// when set, interactions are also received over HTTP on this address, at /interactions
+		InteractionsAddr string `env:"INTERACTIONS_ADDR"`
+		// prefix of the commands in DMs and in servers that didn't set theirs
+		Prefix string `env:"PREFIX" envDefault:"."`
+		// ".PLAY" and "V!play" find play too
+		CaseInsensitiveCommands bool `env:"CASE_INSENSITIVE_COMMANDS"`
+		// comma separated IDs of the users allowed to run owner only commands
+		OwnerIDs []string `env:"OWNER_IDS" envSeparator:","`
+		// comma separated IDs of the roles whose members aren't subject to command cooldowns
+		CooldownBypassRoleIDs []string `env:"COOLDOWN_BYPASS_ROLE_IDS" envSeparator:","`
+		// how many cooldowns are kept in memory, the least recently used are dropped past that
+		CooldownMaxEntries int `env:"COOLDOWN_MAX_ENTRIES" envDefault:"100000"`
+		Settings           struct {
+			// "memory" forgets the settings on restart, "file" keeps them as JSON files in Dir
+			Backend string `env:"BACKEND" envDefault:"memory"`
+			Dir     string `env:"DIR" envDefault:"data/guilds"`
+			// how many guilds have their settings cached, and for how long
+			CacheSize int           `env:"CACHE_SIZE" envDefault:"1000"`
+			CacheTTL  time.Duration `env:"CACHE_TTL" envDefault:"10m"`
+		} `envPrefix:"SETTINGS_"`
+		RateLimitProxy struct {
+			// when set, REST requests go through the rate limit proxy at this URL
+			URL string `env:"URL"`
+			// address the rate limit proxy listens on, only reachable from this machine by default
+			Addr string `env:"ADDR" envDefault:"127.0.0.1:8081"`
+			// shared by the proxy and the processes behind it, the proxy refuses to start without it
+			Secret string `env:"SECRET"`
+		} `envPrefix:"RATELIMIT_PROXY_"`
 	} `envPrefix:"DISCORD_"`
 }
 
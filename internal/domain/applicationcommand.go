@@ -0,0 +1,86 @@
+package domain
+
+// https://discord.com/developers/docs/interactions/application-commands#application-command-object-application-command-types
+type ApplicationCommandType int
+
+const (
+	ApplicationCommandType_CHAT_INPUT ApplicationCommandType = iota + 1
+	ApplicationCommandType_USER
+	ApplicationCommandType_MESSAGE
+)
+
+// https://discord.com/developers/docs/interactions/application-commands#application-command-object-application-command-option-type
+type ApplicationCommandOptionType int
+
+const (
+	ApplicationCommandOptionType_SUB_COMMAND ApplicationCommandOptionType = iota + 1
+	ApplicationCommandOptionType_SUB_COMMAND_GROUP
+	ApplicationCommandOptionType_STRING
+	ApplicationCommandOptionType_INTEGER
+	ApplicationCommandOptionType_BOOLEAN
+	ApplicationCommandOptionType_USER
+	ApplicationCommandOptionType_CHANNEL
+	ApplicationCommandOptionType_ROLE
+	ApplicationCommandOptionType_MENTIONABLE
+	ApplicationCommandOptionType_NUMBER
+	ApplicationCommandOptionType_ATTACHMENT
+)
+
+// https://discord.com/developers/docs/interactions/application-commands#application-command-object-application-command-option-choice-structure
+type ApplicationCommandOptionChoice struct {
+	Name              string            `json:"name"`
+	NameLocalizations map[string]string `json:"name_localizations,omitempty"`
+	// string, integer or number, matching the type of the option
+	Value any `json:"value"`
+}
+
+// https://discord.com/developers/docs/interactions/application-commands#application-command-object-application-command-option-structure
+type ApplicationCommandOption struct {
+	Type                     ApplicationCommandOptionType     `json:"type"`
+	Name                     string                           `json:"name"`
+	NameLocalizations        map[string]string                `json:"name_localizations,omitempty"`
+	Description              string                           `json:"description"`
+	DescriptionLocalizations map[string]string                `json:"description_localizations,omitempty"`
+	Required                 bool                             `json:"required,omitempty"`
+	Choices                  []ApplicationCommandOptionChoice `json:"choices,omitempty"`
+	// only for SUB_COMMAND and SUB_COMMAND_GROUP
+	Options      []ApplicationCommandOption `json:"options,omitempty"`
+	ChannelTypes []ChannelType              `json:"channel_types,omitempty"`
+	// only for INTEGER and NUMBER
+	MinValue *float64 `json:"min_value,omitempty"`
+	MaxValue *float64 `json:"max_value,omitempty"`
+	// only for STRING
+	MinLength    *int `json:"min_length,omitempty"`
+	MaxLength    *int `json:"max_length,omitempty"`
+	Autocomplete bool `json:"autocomplete,omitempty"`
+}
+
+// https://discord.com/developers/docs/interactions/application-commands#application-command-object
+//
+// The same struct is used to declare a command and to read it back from discord,
+// ID, ApplicationID, GuildID and Version are only set on the registered ones.
+type ApplicationCommand struct {
+	ID                       string                     `json:"id,omitempty"`
+	Type                     ApplicationCommandType     `json:"type,omitempty"`
+	ApplicationID            string                     `json:"application_id,omitempty"`
+	GuildID                  string                     `json:"guild_id,omitempty"`
+	Name                     string                     `json:"name"`
+	NameLocalizations        map[string]string          `json:"name_localizations,omitempty"`
+	Description              string                     `json:"description"`
+	DescriptionLocalizations map[string]string          `json:"description_localizations,omitempty"`
+	Options                  []ApplicationCommandOption `json:"options,omitempty"`
+	// nil lets everyone use the command, Permissions(0) restricts it to administrators
+	DefaultMemberPermissions *Permissions `json:"default_member_permissions,omitempty"`
+	// whether the command can be used in DMs, only for global commands, defaults to true
+	DMPermission *bool  `json:"dm_permission,omitempty"`
+	NSFW         bool   `json:"nsfw,omitempty"`
+	Version      string `json:"version,omitempty"`
+}
+
+// ApplicationCommandsSync lists the command names touched by a sync, per outcome.
+type ApplicationCommandsSync struct {
+	Created   []string
+	Updated   []string
+	Deleted   []string
+	Unchanged []string
+}
@@ -0,0 +1,345 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ArgumentType is what an argument of a prefix command is converted to.
+type ArgumentType int
+
+const (
+	ArgumentType_STRING ArgumentType = iota
+	// int64
+	ArgumentType_INT
+	// float64
+	ArgumentType_FLOAT
+	// yes/no, on/off, true/false, 1/0
+	ArgumentType_BOOL
+	// "1h30m", "1:30" or seconds
+	ArgumentType_DURATION
+	// *User, a mention or an ID, the user doesn't have to be in the guild
+	ArgumentType_USER
+	// *Member, a mention or an ID of a member of the guild
+	ArgumentType_MEMBER
+	// *Channel, a mention or an ID of a channel of the guild
+	ArgumentType_CHANNEL
+	// *Role, a mention, an ID or the name of a role of the guild
+	ArgumentType_ROLE
+	// the rest of the line as typed, quotes included, it must be the last argument
+	ArgumentType_REST
+)
+
+var argumentTypeNames = map[ArgumentType]string{
+	ArgumentType_STRING:   "text",
+	ArgumentType_INT:      "whole number",
+	ArgumentType_FLOAT:    "number",
+	ArgumentType_BOOL:     "yes or no",
+	ArgumentType_DURATION: "duration",
+	ArgumentType_USER:     "user",
+	ArgumentType_MEMBER:   "member",
+	ArgumentType_CHANNEL:  "channel",
+	ArgumentType_ROLE:     "role",
+	ArgumentType_REST:     "text",
+}
+
+func (t ArgumentType) String() string {
+	if name, ok := argumentTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("ArgumentType(%d)", t)
+}
+
+// Argument declares a positional argument of a prefix command.
+// Optional arguments must come after the required ones, a variadic argument must be the last.
+type Argument struct {
+	Name        string
+	Description string
+	Type        ArgumentType
+	Optional    bool
+	// takes every remaining token, at least one unless Optional
+	Variadic bool
+}
+
+// Placeholder is how the argument is shown in usages, e.g. "<query>", "[volume]" or "<users...>".
+func (a Argument) Placeholder() string {
+	name := a.Name
+	if a.Variadic || a.Type == ArgumentType_REST {
+		name += "..."
+	}
+	if a.Optional {
+		return "[" + name + "]"
+	}
+	return "<" + name + ">"
+}
+
+// ApplicationCommandOption maps the argument onto a slash command option, so one schema serves both.
+// Types without a slash equivalent are typed as text and converted like prefix arguments.
+func (a Argument) ApplicationCommandOption() ApplicationCommandOption {
+	option := ApplicationCommandOption{
+		Type:        ApplicationCommandOptionType_STRING,
+		Name:        a.Name,
+		Description: a.Description,
+		Required:    !a.Optional,
+	}
+	if option.Description == "" {
+		option.Description = a.Type.String()
+	}
+	if a.Variadic {
+		return option
+	}
+	switch a.Type {
+	case ArgumentType_INT:
+		option.Type = ApplicationCommandOptionType_INTEGER
+	case ArgumentType_FLOAT:
+		option.Type = ApplicationCommandOptionType_NUMBER
+	case ArgumentType_BOOL:
+		option.Type = ApplicationCommandOptionType_BOOLEAN
+	case ArgumentType_USER, ArgumentType_MEMBER:
+		option.Type = ApplicationCommandOptionType_USER
+	case ArgumentType_CHANNEL:
+		option.Type = ApplicationCommandOptionType_CHANNEL
+	case ArgumentType_ROLE:
+		option.Type = ApplicationCommandOptionType_ROLE
+	}
+	return option
+}
+
+// ConvertedFromText tells if the slash option of the argument is text that still needs converting.
+func (a Argument) ConvertedFromText() bool {
+	return a.Variadic || a.Type == ArgumentType_DURATION
+}
+
+// Usage shows how to call a command, e.g. Usage(".play", arguments) is ".play <query...>".
+func Usage(command string, arguments []Argument) string {
+	usage := command
+	for _, argument := range arguments {
+		usage += " " + argument.Placeholder()
+	}
+	return usage
+}
+
+// UsageError is returned when a prefix command is called with invalid arguments,
+// the message handler answers it with the usage of the command.
+// Commands can return one too, for checks the schema can't express.
+type UsageError struct {
+	// nil when the error isn't about a single argument
+	Argument *Argument
+	Reason   string
+	// filled by the message handler, see Usage
+	Usage string
+}
+
+func (e *UsageError) Error() string {
+	if e.Argument != nil {
+		return fmt.Sprintf("%s: %s", e.Argument.Placeholder(), e.Reason)
+	}
+	return e.Reason
+}
+
+// Args holds the converted arguments of a prefix command, by name.
+// The getters return the zero value for arguments that weren't given, see Has.
+type Args struct {
+	values map[string][]any
+}
+
+func NewArgs() *Args {
+	return &Args{values: make(map[string][]any)}
+}
+
+// Add appends a value to an argument, variadic ones have several.
+func (a *Args) Add(name string, value any) {
+	a.values[name] = append(a.values[name], value)
+}
+
+// Set replaces the values of an argument.
+func (a *Args) Set(name string, values ...any) {
+	a.values[name] = values
+}
+
+func (a *Args) Has(name string) bool {
+	return len(a.values[name]) > 0
+}
+
+// All returns every value of a variadic argument.
+func (a *Args) All(name string) []any {
+	return a.values[name]
+}
+
+func (a *Args) Get(name string) any {
+	if values := a.values[name]; len(values) > 0 {
+		return values[0]
+	}
+	return nil
+}
+
+func (a *Args) String(name string) string {
+	value, _ := a.Get(name).(string)
+	return value
+}
+
+// Strings returns every value of a variadic string argument.
+func (a *Args) Strings(name string) []string {
+	values := make([]string, 0, len(a.values[name]))
+	for _, value := range a.values[name] {
+		if s, ok := value.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+func (a *Args) Int(name string) int64 {
+	value, _ := a.Get(name).(int64)
+	return value
+}
+
+func (a *Args) Float(name string) float64 {
+	value, _ := a.Get(name).(float64)
+	return value
+}
+
+func (a *Args) Bool(name string) bool {
+	value, _ := a.Get(name).(bool)
+	return value
+}
+
+func (a *Args) Duration(name string) time.Duration {
+	value, _ := a.Get(name).(time.Duration)
+	return value
+}
+
+// User also returns the user of member arguments.
+func (a *Args) User(name string) *User {
+	switch value := a.Get(name).(type) {
+	case *User:
+		return value
+	case *Member:
+		return value.User
+	}
+	return nil
+}
+
+func (a *Args) Member(name string) *Member {
+	value, _ := a.Get(name).(*Member)
+	return value
+}
+
+func (a *Args) Channel(name string) *Channel {
+	value, _ := a.Get(name).(*Channel)
+	return value
+}
+
+func (a *Args) Role(name string) *Role {
+	value, _ := a.Get(name).(*Role)
+	return value
+}
+
+// Token is a word of a command line, or a quoted string with its quotes removed.
+type Token struct {
+	Value string
+	// byte offset of the token in the line, where the rest of the line starts
+	Start int
+}
+
+// ParseDuration reads durations written "1h30m", "1:30" / "1:02:03" (like a timestamp) or as seconds.
+func ParseDuration(value string) (time.Duration, bool) {
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if strings.Contains(value, ":") {
+		parts := strings.Split(value, ":")
+		if len(parts) > 3 {
+			return 0, false
+		}
+		var total time.Duration
+		for i, part := range parts {
+			n, err := strconv.ParseUint(part, 10, 32)
+			// every part after the first one is 0 to 59
+			if err != nil || (i > 0 && (len(part) != 2 || n > 59)) {
+				return 0, false
+			}
+			total = total*60 + time.Duration(n)
+		}
+		return total * time.Second, true
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+var ErrUnclosedQuote = errors.New("a quote isn't closed")
+
+// Tokenize splits a command line on spaces.
+// Double or single quotes at the start of a token group words until the closing quote,
+// a backslash escapes the next character, e.g. `"hello world" it\'s` is [hello world, it's].
+func Tokenize(line string) ([]Token, error) {
+	tokens, _, err := TokenizeN(line, -1)
+	return tokens, err
+}
+
+// TokenizeN stops after n tokens and returns what's left of the line as typed, n < 0 tokenizes all of it.
+// The rest isn't tokenized, free text like that of ArgumentType_REST can have quotes that aren't closed.
+func TokenizeN(line string, n int) (tokens []Token, rest string, err error) {
+	if n == 0 {
+		return nil, line, nil
+	}
+	var current strings.Builder
+	inToken, escaped := false, false
+	var quote rune
+	start := 0
+
+	for i, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			if !inToken {
+				inToken, start = true, i
+			}
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case unicode.IsSpace(r):
+			if inToken {
+				tokens = append(tokens, Token{Value: current.String(), Start: start})
+				current.Reset()
+				inToken = false
+				if len(tokens) == n {
+					return tokens, line[i:], nil
+				}
+			}
+		case !inToken && (r == '"' || r == '\''):
+			inToken, start = true, i
+			quote = r
+		default:
+			if !inToken {
+				inToken, start = true, i
+			}
+			current.WriteRune(r)
+		}
+	}
+
+	if quote != 0 {
+		return nil, "", ErrUnclosedQuote
+	}
+	if escaped {
+		// a trailing backslash is kept as is
+		current.WriteRune('\\')
+	}
+	if inToken {
+		tokens = append(tokens, Token{Value: current.String(), Start: start})
+	}
+	return tokens, "", nil
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "io"
+
+type AttachmentFlags int
+
+const (
+	AttachmentFlags_IS_REMIX AttachmentFlags = 1 << 2
+)
+
+// https://discord.com/developers/docs/resources/message#attachment-object
+type Attachment struct {
+	ID           string          `json:"id"`
+	Filename     string          `json:"filename"`
+	Title        *string         `json:"title"`
+	Description  *string         `json:"description"`
+	ContentType  *string         `json:"content_type"`
+	Size         int             `json:"size"`
+	URL          string          `json:"url"`
+	ProxyURL     string          `json:"proxy_url"`
+	Height       *int            `json:"height"`
+	Width        *int            `json:"width"`
+	Ephemeral    bool            `json:"ephemeral"`
+	DurationSecs *float64        `json:"duration_secs"`
+	Waveform     *string         `json:"waveform"`
+	Flags        AttachmentFlags `json:"flags"`
+}
+
+// PartialAttachment is the attachment metadata sent alongside uploaded files.
+// For a new upload the ID is the index of the file in the request ("0", "1", ...).
+type PartialAttachment struct {
+	ID          string `json:"id"`
+	Filename    string `json:"filename,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// File is a file to upload with a message.
+// Reader is streamed into the request body as is, it's never buffered whole in memory.
+// If it's an io.Closer it's closed once the request is done.
+// Failed uploads are only retried when Reader is an io.Seeker (e.g. *os.File, *bytes.Reader).
+type File struct {
+	Name        string
+	Description string
+	// defaults to application/octet-stream
+	ContentType string
+	Reader      io.Reader
+}
@@ -19,12 +19,49 @@ const (
 )
 
 type Channel struct {
-	ID   string      `json:"id"`
-	Type ChannelType `json:"type"`
+	ID                   string                `json:"id"`
+	Type                 ChannelType           `json:"type"`
+	GuildID              string                `json:"guild_id,omitempty"`
+	Position             int                   `json:"position,omitempty"`
+	PermissionOverwrites []PermissionOverwrite `json:"permission_overwrites,omitempty"`
+	Name                 *string               `json:"name,omitempty"`
+	Topic                *string               `json:"topic,omitempty"`
+	NSFW                 bool                  `json:"nsfw,omitempty"`
+	ParentID             *string               `json:"parent_id,omitempty"`
 	// the real channel object
 	Data any
 }
 
+// https://discord.com/developers/docs/resources/guild#create-guild-channel-json-params
+type CreateChannel struct {
+	Name                 string                `json:"name"`
+	Type                 ChannelType           `json:"type"`
+	Topic                string                `json:"topic,omitempty"`
+	Bitrate              int                   `json:"bitrate,omitempty"`
+	UserLimit            int                   `json:"user_limit,omitempty"`
+	RateLimitPerUser     int                   `json:"rate_limit_per_user,omitempty"`
+	Position             int                   `json:"position,omitempty"`
+	PermissionOverwrites []PermissionOverwrite `json:"permission_overwrites,omitempty"`
+	ParentID             string                `json:"parent_id,omitempty"`
+	NSFW                 bool                  `json:"nsfw,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/channel#modify-channel-json-params-guild-channel
+//
+// nil fields are left untouched by discord.
+type ModifyChannel struct {
+	Name                 *string                `json:"name,omitempty"`
+	Type                 *ChannelType           `json:"type,omitempty"`
+	Position             *int                   `json:"position,omitempty"`
+	Topic                *string                `json:"topic,omitempty"`
+	NSFW                 *bool                  `json:"nsfw,omitempty"`
+	RateLimitPerUser     *int                   `json:"rate_limit_per_user,omitempty"`
+	Bitrate              *int                   `json:"bitrate,omitempty"`
+	UserLimit            *int                   `json:"user_limit,omitempty"`
+	PermissionOverwrites *[]PermissionOverwrite `json:"permission_overwrites,omitempty"`
+	ParentID             *string                `json:"parent_id,omitempty"`
+}
+
 type GuildTextChannel struct {
 }
 
@@ -7,7 +7,9 @@ type ClientHandler func(event json.RawMessage)
 type Intents uint64
 
 const (
-	Intents_GUILDS          = 1 << 0
-	Intents_GUILD_MESSAGES  = 1 << 9
-	Intents_MESSAGE_CONTENT = 1 << 15
+	Intents_GUILDS                   = 1 << 0
+	Intents_GUILD_MESSAGES           = 1 << 9
+	Intents_GUILD_MESSAGE_REACTIONS  = 1 << 10
+	Intents_DIRECT_MESSAGE_REACTIONS = 1 << 13
+	Intents_MESSAGE_CONTENT          = 1 << 15
 )
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// https://discord.com/developers/docs/interactions/message-components#component-object-component-types
+type ComponentType int
+
+const (
+	ComponentType_ACTION_ROW ComponentType = iota + 1
+	ComponentType_BUTTON
+	ComponentType_STRING_SELECT
+	ComponentType_TEXT_INPUT
+	ComponentType_USER_SELECT
+	ComponentType_ROLE_SELECT
+	ComponentType_MENTIONABLE_SELECT
+	ComponentType_CHANNEL_SELECT
+)
+
+// ComponentStyle is the style of a button (ButtonStyle) or of a text input (TextInputStyle),
+// discord sends both as "style".
+type ComponentStyle int
+
+// https://discord.com/developers/docs/interactions/message-components#button-object-button-styles
+type ButtonStyle = ComponentStyle
+
+const (
+	ButtonStyle_PRIMARY ButtonStyle = iota + 1
+	ButtonStyle_SECONDARY
+	ButtonStyle_SUCCESS
+	ButtonStyle_DANGER
+	// opens URL, it has no custom ID and never sends an interaction
+	ButtonStyle_LINK
+)
+
+// https://discord.com/developers/docs/interactions/message-components#component-object
+//
+// One struct for every component type, only the fields of Type are sent.
+// Messages hold up to 5 action rows, each with up to 5 buttons or a single select menu.
+type Component struct {
+	Type ComponentType `json:"type"`
+	// identifies the component in interactions, up to 100 characters, see CustomID
+	CustomID string `json:"custom_id,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+
+	// action rows
+	Components []Component `json:"components,omitempty"`
+
+	// buttons and text inputs
+	Style ComponentStyle `json:"style,omitempty"`
+	Label string         `json:"label,omitempty"`
+	Emoji *Emoji         `json:"emoji,omitempty"`
+	URL   string         `json:"url,omitempty"`
+
+	// select menus
+	Options       []SelectOption       `json:"options,omitempty"`
+	ChannelTypes  []ChannelType        `json:"channel_types,omitempty"`
+	Placeholder   string               `json:"placeholder,omitempty"`
+	DefaultValues []SelectDefaultValue `json:"default_values,omitempty"`
+	MinValues     *int                 `json:"min_values,omitempty"`
+	MaxValues     *int                 `json:"max_values,omitempty"`
+
+	// text inputs, Placeholder is shared with select menus
+	Value string `json:"value,omitempty"`
+	// discord defaults to true
+	Required  *bool `json:"required,omitempty"`
+	MinLength *int  `json:"min_length,omitempty"`
+	MaxLength *int  `json:"max_length,omitempty"`
+}
+
+// https://discord.com/developers/docs/interactions/message-components#select-menu-object-select-option-structure
+type SelectOption struct {
+	Label       string `json:"label"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Emoji       *Emoji `json:"emoji,omitempty"`
+	Default     bool   `json:"default,omitempty"`
+}
+
+// https://discord.com/developers/docs/interactions/message-components#select-menu-object-select-default-value-structure
+type SelectDefaultValue struct {
+	ID string `json:"id"`
+	// "user", "role" or "channel"
+	Type string `json:"type"`
+}
+
+func NewActionRow(components ...Component) Component {
+	return Component{Type: ComponentType_ACTION_ROW, Components: components}
+}
+
+// CustomID joins parts with ":", the separator component patterns are matched on.
+// e.g. CustomID("player", "skip", guildID) matches "player:skip:{guild_id}".
+func CustomID(parts ...string) string {
+	return strings.Join(parts, ":")
+}
+
+// DisableComponents returns a copy of components with every interactive component disabled.
+func DisableComponents(components []Component) []Component {
+	disabled := make([]Component, len(components))
+	for i, component := range components {
+		disabled[i] = component
+		if len(component.Components) > 0 {
+			disabled[i].Components = DisableComponents(component.Components)
+		}
+		// link buttons don't interact with the bot, they stay usable
+		isLink := component.Type == ComponentType_BUTTON && component.Style == ButtonStyle_LINK
+		if component.Type != ComponentType_ACTION_ROW && !isLink {
+			disabled[i].Disabled = true
+		}
+	}
+	return disabled
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object-message-component-data-structure
+type MessageComponentInteractionData struct {
+	CustomID      string        `json:"custom_id"`
+	ComponentType ComponentType `json:"component_type"`
+	// the selected values of select menus, IDs for user, role, mentionable and channel ones
+	Values   []string      `json:"values"`
+	Resolved *ResolvedData `json:"resolved"`
+}
+
+// MessageComponentData decodes Data of a MESSAGE_COMPONENT interaction.
+func (i *Interaction) MessageComponentData() (*MessageComponentInteractionData, error) {
+	if i.Type != InteractionType_MESSAGE_COMPONENT {
+		return nil, fmt.Errorf("interaction %s of type %d isn't a message component", i.ID, i.Type)
+	}
+	var data MessageComponentInteractionData
+	if err := json.Unmarshal(i.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode message component data: %w", err)
+	}
+	return &data, nil
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// LimitScope is who shares a cooldown or a concurrency limit.
+type LimitScope int
+
+const (
+	LimitScope_USER LimitScope = iota
+	LimitScope_CHANNEL
+	// the channel in DMs
+	LimitScope_GUILD
+	LimitScope_GLOBAL
+)
+
+var limitScopeNames = map[LimitScope]string{
+	LimitScope_USER:    "user",
+	LimitScope_CHANNEL: "channel",
+	LimitScope_GUILD:   "server",
+	LimitScope_GLOBAL:  "everyone",
+}
+
+func (s LimitScope) String() string {
+	if name, ok := limitScopeNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("LimitScope(%d)", s)
+}
+
+// Cooldown allows Uses runs of a command per Window, e.g. 3 per minute per user.
+type Cooldown struct {
+	Uses   int
+	Window time.Duration
+	Scope  LimitScope
+}
+
+// CommandLimits bound how often and how many times at once a command runs, see the limits middleware.
+type CommandLimits struct {
+	// nil for no cooldown
+	Cooldown *Cooldown
+	// how many runs can be in progress at once per ConcurrencyScope, 0 for no limit
+	MaxConcurrency   int
+	ConcurrencyScope LimitScope
+}
@@ -0,0 +1,71 @@
+package domain
+
+// https://discord.com/developers/docs/resources/emoji#emoji-object
+type Emoji struct {
+	// nil for unicode emojis
+	ID *string `json:"id"`
+	// nil only for deleted custom emojis received in reaction events
+	Name          *string  `json:"name"`
+	Roles         []string `json:"roles,omitempty"`
+	User          *User    `json:"user,omitempty"`
+	RequireColons bool     `json:"require_colons,omitempty"`
+	Managed       bool     `json:"managed,omitempty"`
+	Animated      bool     `json:"animated,omitempty"`
+	Available     bool     `json:"available,omitempty"`
+}
+
+// APIName returns the emoji the way the reactions endpoints expect it:
+// the unicode character itself, or "name:id" for custom emojis.
+// The result still has to be URL encoded.
+func (e *Emoji) APIName() string {
+	var name string
+	if e.Name != nil {
+		name = *e.Name
+	}
+	if e.ID != nil {
+		return name + ":" + *e.ID
+	}
+	return name
+}
+
+type ReactionType int
+
+const (
+	ReactionType_NORMAL ReactionType = iota
+	ReactionType_BURST
+)
+
+// https://discord.com/developers/docs/resources/message#reaction-count-details-object
+type ReactionCountDetails struct {
+	Burst  int `json:"burst"`
+	Normal int `json:"normal"`
+}
+
+// https://discord.com/developers/docs/resources/message#reaction-object
+type Reaction struct {
+	Count        int                  `json:"count"`
+	CountDetails ReactionCountDetails `json:"count_details"`
+	Me           bool                 `json:"me"`
+	MeBurst      bool                 `json:"me_burst"`
+	Emoji        Emoji                `json:"emoji"`
+	BurstColors  []string             `json:"burst_colors"`
+}
+
+// https://discord.com/developers/docs/resources/message#get-reactions-query-string-params
+type ReactionsQuery struct {
+	Type  ReactionType
+	After string
+	// 1-100, discord defaults to 25
+	Limit int
+}
+
+// ReactionListener groups the callbacks run for reaction events on a watched message.
+// Any of them can be nil.
+type ReactionListener struct {
+	OnAdd         func(event MessageReactionAddEvent)
+	OnRemove      func(event MessageReactionRemoveEvent)
+	OnRemoveAll   func(event MessageReactionRemoveAllEvent)
+	OnRemoveEmoji func(event MessageReactionRemoveEmojiEvent)
+	// OnExpire runs once the listener is dropped after its timeout, not after Unwatch.
+	OnExpire func()
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// https://discord.com/developers/docs/topics/opcodes-and-status-codes#json-json-error-codes
+type APIErrorCode int
+
+const (
+	APIErrorCode_GENERAL                          APIErrorCode = 0
+	APIErrorCode_UNKNOWN_ACCOUNT                  APIErrorCode = 10001
+	APIErrorCode_UNKNOWN_APPLICATION              APIErrorCode = 10002
+	APIErrorCode_UNKNOWN_CHANNEL                  APIErrorCode = 10003
+	APIErrorCode_UNKNOWN_GUILD                    APIErrorCode = 10004
+	APIErrorCode_UNKNOWN_INTEGRATION              APIErrorCode = 10005
+	APIErrorCode_UNKNOWN_INVITE                   APIErrorCode = 10006
+	APIErrorCode_UNKNOWN_MEMBER                   APIErrorCode = 10007
+	APIErrorCode_UNKNOWN_MESSAGE                  APIErrorCode = 10008
+	APIErrorCode_UNKNOWN_OVERWRITE                APIErrorCode = 10009
+	APIErrorCode_UNKNOWN_ROLE                     APIErrorCode = 10011
+	APIErrorCode_UNKNOWN_TOKEN                    APIErrorCode = 10012
+	APIErrorCode_UNKNOWN_USER                     APIErrorCode = 10013
+	APIErrorCode_UNKNOWN_EMOJI                    APIErrorCode = 10014
+	APIErrorCode_UNKNOWN_WEBHOOK                  APIErrorCode = 10015
+	APIErrorCode_UNKNOWN_BAN                      APIErrorCode = 10026
+	APIErrorCode_UNKNOWN_INTERACTION              APIErrorCode = 10062
+	APIErrorCode_UNKNOWN_APPLICATION_COMMAND      APIErrorCode = 10063
+	APIErrorCode_MAXIMUM_REACTIONS                APIErrorCode = 30010
+	APIErrorCode_INTERACTION_ALREADY_ACKNOWLEDGED APIErrorCode = 40060
+	APIErrorCode_MISSING_ACCESS                   APIErrorCode = 50001
+	APIErrorCode_CANNOT_EXECUTE_ACTION_ON_DM      APIErrorCode = 50003
+	APIErrorCode_CANNOT_SEND_MESSAGES_TO_USER     APIErrorCode = 50007
+	APIErrorCode_MISSING_PERMISSIONS              APIErrorCode = 50013
+	APIErrorCode_INVALID_AUTHENTICATION_TOKEN     APIErrorCode = 50014
+	APIErrorCode_MESSAGE_TOO_OLD_TO_BULK_DELETE   APIErrorCode = 50034
+	APIErrorCode_INVALID_FORM_BODY                APIErrorCode = 50035
+	APIErrorCode_REACTION_BLOCKED                 APIErrorCode = 90001
+)
+
+// https://discord.com/developers/docs/reference#error-messages
+type APIFieldError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// APIError is returned by the requester for every non 2xx response except 429, see RateLimitError.
+type APIError struct {
+	Status  int
+	Code    APIErrorCode
+	Message string
+	// the nested errors of 400 responses flattened by path,
+	// e.g. "embeds.0.description" -> [{BASE_TYPE_MAX_LENGTH ...}]
+	Errors map[string][]APIFieldError
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "discord API error: %d %s", e.Status, http.StatusText(e.Status))
+	if e.Message != "" {
+		fmt.Fprintf(&b, " - %s (code %d)", e.Message, e.Code)
+	}
+
+	paths := make([]string, 0, len(e.Errors))
+	for path := range e.Errors {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for _, fieldErr := range e.Errors[path] {
+			fmt.Fprintf(&b, "; %s: %s", path, fieldErr.Message)
+		}
+	}
+	return b.String()
+}
+
+// RateLimitError is returned when discord answers 429 Too Many Requests.
+type RateLimitError struct {
+	Message    string
+	RetryAfter time.Duration
+	Global     bool
+	// "user", "global" or "shared", from the X-RateLimit-Scope header
+	Scope  string
+	Bucket string
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("discord rate limited: %s, retry after %.2f seconds", e.Message, e.RetryAfter.Seconds())
+}
+
+// HasAPIErrorCode reports whether err is an *APIError with the given code.
+func HasAPIErrorCode(err error, code APIErrorCode) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// IsNotFound reports whether err is a 404 or one of the "Unknown X" codes.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Status == http.StatusNotFound || (apiErr.Code >= 10001 && apiErr.Code < 20000)
+}
+
+func IsMissingPermissions(err error) bool {
+	return HasAPIErrorCode(err, APIErrorCode_MISSING_PERMISSIONS)
+}
+
+func IsMissingAccess(err error) bool {
+	return HasAPIErrorCode(err, APIErrorCode_MISSING_ACCESS)
+}
+
+func IsRateLimited(err error) bool {
+	var rateLimitErr *RateLimitError
+	return errors.As(err, &rateLimitErr)
+}
+
+// PanicError is returned by CommandsManager.RunCommand when a command panicked.
+type PanicError struct {
+	Value any
+	// stack of the goroutine that panicked
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
@@ -7,17 +7,20 @@ import (
 type ReadyEvent struct {
 	SessionID        string `json:"session_id"`
 	ResumeGatewayURL string `json:"resume_gateway_url"`
-	User             struct {
-		ID            string `json:"id"`
-		Username      string `json:"username"`
-		Discriminator string `json:"discriminator"`
-	} `json:"user"`
-	Guilds []UnavailableGuild `json:"guilds"`
+	// the bot user
+	User        User               `json:"user"`
+	Guilds      []UnavailableGuild `json:"guilds"`
+	Application struct {
+		ID    string `json:"id"`
+		Flags int    `json:"flags"`
+	} `json:"application"`
 }
 
 type GuildCreateEvent struct {
 	Guild
 	JoinedAt time.Time `json:"joined_at"`
+	// their GuildID isn't set
+	Channels []Channel `json:"channels"`
 }
 
 type GuildUpdateEvent struct {
@@ -56,8 +59,8 @@ type GuildMemberRemoveEvent struct {
 }
 
 type GuildMemberUpdateEvent struct {
-	ID       string      `json:"id"`
 	GuildID  string      `json:"guild_id"`
+	User     User        `json:"user"`
 	Nickname string      `json:"nick"`
 	Avatar   string      `json:"avatar"`
 	Banner   string      `json:"banner"`
@@ -67,3 +70,43 @@ type GuildMemberUpdateEvent struct {
 	Deaf     bool        `json:"deaf"`
 	Mute     bool        `json:"mute"`
 }
+
+type MessageReactionAddEvent struct {
+	UserID          string       `json:"user_id"`
+	ChannelID       string       `json:"channel_id"`
+	MessageID       string       `json:"message_id"`
+	GuildID         *string      `json:"guild_id"`
+	Member          *Member      `json:"member"`
+	Emoji           Emoji        `json:"emoji"`
+	MessageAuthorID *string      `json:"message_author_id"`
+	Burst           bool         `json:"burst"`
+	BurstColors     []string     `json:"burst_colors"`
+	Type            ReactionType `json:"type"`
+}
+
+type MessageReactionRemoveEvent struct {
+	UserID    string       `json:"user_id"`
+	ChannelID string       `json:"channel_id"`
+	MessageID string       `json:"message_id"`
+	GuildID   *string      `json:"guild_id"`
+	Emoji     Emoji        `json:"emoji"`
+	Burst     bool         `json:"burst"`
+	Type      ReactionType `json:"type"`
+}
+
+type MessageReactionRemoveAllEvent struct {
+	ChannelID string  `json:"channel_id"`
+	MessageID string  `json:"message_id"`
+	GuildID   *string `json:"guild_id"`
+}
+
+type MessageReactionRemoveEmojiEvent struct {
+	ChannelID string  `json:"channel_id"`
+	GuildID   *string `json:"guild_id"`
+	MessageID string  `json:"message_id"`
+	Emoji     Emoji   `json:"emoji"`
+}
+
+type InteractionCreateEvent struct {
+	Interaction
+}
@@ -75,4 +75,19 @@ type Guild struct {
 	NsfwLevel                   int                             `json:"nsfw_level"`
 	PremiumProgressBarEnabled   bool                            `json:"premium_progress_bar_enabled"`
 	SafetyAlertsChannelID       *string                         `json:"safety_alerts_channel_id"`
+	Roles                       []Role                          `json:"roles"`
+}
+
+// https://discord.com/developers/docs/resources/guild#ban-object
+type Ban struct {
+	Reason *string `json:"reason"`
+	User   User    `json:"user"`
+}
+
+// https://discord.com/developers/docs/resources/guild#get-guild-bans-query-string-params
+type BansQuery struct {
+	Before string
+	After  string
+	// 1-1000, discord defaults to 1000
+	Limit int
 }
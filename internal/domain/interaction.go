@@ -0,0 +1,263 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object-interaction-type
+type InteractionType int
+
+const (
+	InteractionType_PING InteractionType = iota + 1
+	InteractionType_APPLICATION_COMMAND
+	InteractionType_MESSAGE_COMPONENT
+	InteractionType_APPLICATION_COMMAND_AUTOCOMPLETE
+	InteractionType_MODAL_SUBMIT
+)
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object
+type Interaction struct {
+	ID            string          `json:"id"`
+	ApplicationID string          `json:"application_id"`
+	Type          InteractionType `json:"type"`
+	// depends on Type, see ApplicationCommandData
+	Data      json.RawMessage `json:"data"`
+	GuildID   *string         `json:"guild_id"`
+	ChannelID string          `json:"channel_id"`
+	// set in guilds
+	Member *Member `json:"member"`
+	// set in DMs
+	User    *User    `json:"user"`
+	Token   string   `json:"token"`
+	Version int      `json:"version"`
+	Message *Message `json:"message"`
+	// permissions of the bot in the channel
+	AppPermissions Permissions `json:"app_permissions"`
+	Locale         string      `json:"locale"`
+	GuildLocale    *string     `json:"guild_locale"`
+}
+
+// GetUser returns who triggered the interaction, wherever it happened.
+func (i *Interaction) GetUser() *User {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User
+	}
+	return i.User
+}
+
+// ApplicationCommandData decodes Data of an APPLICATION_COMMAND or APPLICATION_COMMAND_AUTOCOMPLETE interaction.
+func (i *Interaction) ApplicationCommandData() (*ApplicationCommandInteractionData, error) {
+	if i.Type != InteractionType_APPLICATION_COMMAND && i.Type != InteractionType_APPLICATION_COMMAND_AUTOCOMPLETE {
+		return nil, fmt.Errorf("interaction %s of type %d isn't an application command", i.ID, i.Type)
+	}
+	var data ApplicationCommandInteractionData
+	if err := json.Unmarshal(i.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode application command data: %w", err)
+	}
+	return &data, nil
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object-application-command-data-structure
+type ApplicationCommandInteractionData struct {
+	ID       string                                    `json:"id"`
+	Name     string                                    `json:"name"`
+	Type     ApplicationCommandType                    `json:"type"`
+	Resolved *ResolvedData                             `json:"resolved"`
+	Options  []ApplicationCommandInteractionDataOption `json:"options"`
+	GuildID  *string                                   `json:"guild_id"`
+	// the user or message a context menu command was used on
+	TargetID *string `json:"target_id"`
+}
+
+// Subcommands returns the subcommand group and subcommand that were used, if any, e.g. ["voice", "set"].
+func (d *ApplicationCommandInteractionData) Subcommands() []string {
+	var path []string
+	options := d.Options
+	for len(options) == 1 && options[0].isSubcommand() {
+		path = append(path, options[0].Name)
+		options = options[0].Options
+	}
+	return path
+}
+
+// LeafOptions returns the options filled by the user, the ones nested in the subcommand that was used.
+func (d *ApplicationCommandInteractionData) LeafOptions() []ApplicationCommandInteractionDataOption {
+	options := d.Options
+	for len(options) == 1 && options[0].isSubcommand() {
+		options = options[0].Options
+	}
+	return options
+}
+
+// Option finds a filled option by name, see LeafOptions.
+func (d *ApplicationCommandInteractionData) Option(name string) (*ApplicationCommandInteractionDataOption, bool) {
+	options := d.LeafOptions()
+	for i := range options {
+		if options[i].Name == name {
+			return &options[i], true
+		}
+	}
+	return nil, false
+}
+
+// Focused returns the option being typed in an autocomplete interaction.
+func (d *ApplicationCommandInteractionData) Focused() (*ApplicationCommandInteractionDataOption, bool) {
+	options := d.LeafOptions()
+	for i := range options {
+		if options[i].Focused {
+			return &options[i], true
+		}
+	}
+	return nil, false
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object-resolved-data-structure
+//
+// The objects referenced by the options, keyed by ID.
+type ResolvedData struct {
+	Users       map[string]User       `json:"users"`
+	Members     map[string]Member     `json:"members"`
+	Roles       map[string]Role       `json:"roles"`
+	Channels    map[string]Channel    `json:"channels"`
+	Messages    map[string]Message    `json:"messages"`
+	Attachments map[string]Attachment `json:"attachments"`
+}
+
+// Member returns the resolved member with its user filled in, discord sends them apart.
+func (r *ResolvedData) Member(ID string) (*Member, bool) {
+	if r == nil {
+		return nil, false
+	}
+	member, ok := r.Members[ID]
+	if !ok {
+		return nil, false
+	}
+	member.ID = ID
+	if user, ok := r.Users[ID]; ok {
+		member.User = &user
+	}
+	return &member, true
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object-application-command-interaction-data-option-structure
+type ApplicationCommandInteractionDataOption struct {
+	Name string                       `json:"name"`
+	Type ApplicationCommandOptionType `json:"type"`
+	// string, float64 or bool, IDs of users, channels, roles and attachments are strings
+	Value any `json:"value"`
+	// options of a subcommand or subcommand group
+	Options []ApplicationCommandInteractionDataOption `json:"options"`
+	// the option being autocompleted
+	Focused bool `json:"focused"`
+}
+
+func (o *ApplicationCommandInteractionDataOption) isSubcommand() bool {
+	return o.Type == ApplicationCommandOptionType_SUB_COMMAND || o.Type == ApplicationCommandOptionType_SUB_COMMAND_GROUP
+}
+
+func (o *ApplicationCommandInteractionDataOption) StringValue() string {
+	switch value := o.Value.(type) {
+	case string:
+		return value
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func (o *ApplicationCommandInteractionDataOption) IntValue() int64 {
+	switch value := o.Value.(type) {
+	case float64:
+		return int64(value)
+	case string:
+		// partial input of a focused option
+		n, _ := strconv.ParseInt(value, 10, 64)
+		return n
+	default:
+		return 0
+	}
+}
+
+func (o *ApplicationCommandInteractionDataOption) FloatValue() float64 {
+	switch value := o.Value.(type) {
+	case float64:
+		return value
+	case string:
+		n, _ := strconv.ParseFloat(value, 64)
+		return n
+	default:
+		return 0
+	}
+}
+
+func (o *ApplicationCommandInteractionDataOption) BoolValue() bool {
+	value, _ := o.Value.(bool)
+	return value
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-response-object-interaction-callback-type
+type InteractionCallbackType int
+
+const (
+	InteractionCallbackType_PONG                                    InteractionCallbackType = 1
+	InteractionCallbackType_CHANNEL_MESSAGE_WITH_SOURCE             InteractionCallbackType = 4
+	InteractionCallbackType_DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE    InteractionCallbackType = 5
+	InteractionCallbackType_DEFERRED_UPDATE_MESSAGE                 InteractionCallbackType = 6
+	InteractionCallbackType_UPDATE_MESSAGE                          InteractionCallbackType = 7
+	InteractionCallbackType_APPLICATION_COMMAND_AUTOCOMPLETE_RESULT InteractionCallbackType = 8
+	InteractionCallbackType_MODAL                                   InteractionCallbackType = 9
+)
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-response-object
+type InteractionResponse struct {
+	Type InteractionCallbackType  `json:"type"`
+	Data *InteractionResponseData `json:"data,omitempty"`
+}
+
+func (r *InteractionResponse) GetFiles() []*File {
+	if r.Data == nil {
+		return nil
+	}
+	return r.Data.Files
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-response-object-messages
+//
+// Also the body of follow up messages.
+type InteractionResponseData struct {
+	TTS         bool                `json:"tts,omitempty"`
+	Content     string              `json:"content,omitempty"`
+	Embeds      []Embed             `json:"embeds,omitempty"`
+	Components  []Component         `json:"components,omitempty"`
+	Flags       MessageFlags        `json:"flags,omitempty"`
+	Attachments []PartialAttachment `json:"attachments,omitempty"`
+	// uploaded as multipart/form-data, see File
+	Files []*File `json:"-"`
+
+	// modals only, see Modal
+	CustomID string `json:"custom_id,omitempty"`
+	Title    string `json:"title,omitempty"`
+
+	// autocomplete results only, up to 25, a pointer so an empty list is still sent
+	Choices *[]ApplicationCommandOptionChoice `json:"choices,omitempty"`
+}
+
+func (d *InteractionResponseData) GetFiles() []*File {
+	return d.Files
+}
+
+// ToEditMessage is the edit giving a deferred response (or any message) the content of d.
+func (d *InteractionResponseData) ToEditMessage() *EditMessage {
+	edit := &EditMessage{
+		Content: &d.Content,
+		Embeds:  &d.Embeds,
+		Files:   d.Files,
+	}
+	if d.Components != nil {
+		edit.Components = &d.Components
+	}
+	return edit
+}
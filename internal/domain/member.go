@@ -17,15 +17,32 @@ const (
 )
 
 type Member struct {
-	ID          string      `json:"id"`
-	GuildID     string      `json:"guild_id"`
-	Nickname    string      `json:"nick"`
-	Avatar      string      `json:"avatar"`
-	Banner      string      `json:"banner"`
-	Roles       []string    `json:"roles"`
-	JoinedAt    time.Time   `json:"joined_at"`
-	Flags       MemberFlags `json:"flags"`
-	Deaf        bool        `json:"deaf"`
-	Mute        bool        `json:"mute"`
-	Permissions *string     `json:"permissions"`
+	ID                         string      `json:"id"`
+	GuildID                    string      `json:"guild_id"`
+	User                       *User       `json:"user"`
+	Nickname                   string      `json:"nick"`
+	Avatar                     string      `json:"avatar"`
+	Banner                     string      `json:"banner"`
+	Roles                      []string    `json:"roles"`
+	JoinedAt                   time.Time   `json:"joined_at"`
+	Flags                      MemberFlags `json:"flags"`
+	Deaf                       bool        `json:"deaf"`
+	Mute                       bool        `json:"mute"`
+	Pending                    bool        `json:"pending"`
+	CommunicationDisabledUntil *time.Time  `json:"communication_disabled_until"`
+	// only in interactions, the permissions of the member in the channel, overwrites included
+	Permissions *Permissions `json:"permissions"`
+}
+
+// https://discord.com/developers/docs/resources/guild#modify-guild-member-json-params
+//
+// nil fields are left untouched by discord.
+// Timeouts are handled apart since clearing one needs an explicit null.
+type ModifyMember struct {
+	Nick  *string   `json:"nick,omitempty"`
+	Roles *[]string `json:"roles,omitempty"`
+	Mute  *bool     `json:"mute,omitempty"`
+	Deaf  *bool     `json:"deaf,omitempty"`
+	// voice channel to move the member to
+	ChannelID *string `json:"channel_id,omitempty"`
 }
@@ -4,18 +4,31 @@ import "time"
 
 type MessageType int
 
+// https://discord.com/developers/docs/resources/message#message-object-message-flags
+type MessageFlags int
+
+const (
+	MessageFlags_SUPPRESS_EMBEDS        MessageFlags = 1 << 2
+	MessageFlags_EPHEMERAL              MessageFlags = 1 << 6
+	MessageFlags_SUPPRESS_NOTIFICATIONS MessageFlags = 1 << 12
+)
+
 const (
 	MessageType_DEFAULT MessageType = iota
 )
 
 type Message struct {
-	ID              string      `json:"id"`
-	ChannelID       string      `json:"channel_id"`
-	Author          User        `json:"author"`
-	Content         string      `json:"content"`
-	Timestamp       time.Time   `json:"timestamp"`
-	EditedTimestamp *time.Time  `json:"edited_timestamp"`
-	Type            MessageType `json:"type"`
+	ID              string       `json:"id"`
+	ChannelID       string       `json:"channel_id"`
+	Author          User         `json:"author"`
+	Content         string       `json:"content"`
+	Timestamp       time.Time    `json:"timestamp"`
+	EditedTimestamp *time.Time   `json:"edited_timestamp"`
+	Type            MessageType  `json:"type"`
+	Flags           MessageFlags `json:"flags"`
+	Reactions       []Reaction   `json:"reactions"`
+	Attachments     []Attachment `json:"attachments"`
+	Components      []Component  `json:"components"`
 }
 
 type EmbedAuthor struct {
@@ -60,6 +73,54 @@ type Embed struct {
 }
 
 type SendMessage struct {
-	Content string  `json:"content,omitempty"`
-	Embeds  []Embed `json:"embeds,omitempty"`
+	Content     string              `json:"content,omitempty"`
+	Embeds      []Embed             `json:"embeds,omitempty"`
+	Components  []Component         `json:"components,omitempty"`
+	Attachments []PartialAttachment `json:"attachments,omitempty"`
+	// makes the message a reply
+	MessageReference *MessageReference `json:"message_reference,omitempty"`
+	// uploaded as multipart/form-data, see File
+	Files []*File `json:"-"`
+}
+
+// https://discord.com/developers/docs/resources/message#message-reference-structure
+type MessageReference struct {
+	MessageID string `json:"message_id,omitempty"`
+	ChannelID string `json:"channel_id,omitempty"`
+	GuildID   string `json:"guild_id,omitempty"`
+	// discord defaults to true, false sends the message even if the referenced one was deleted
+	FailIfNotExists *bool `json:"fail_if_not_exists,omitempty"`
+}
+
+func (m *SendMessage) GetFiles() []*File {
+	return m.Files
+}
+
+// https://discord.com/developers/docs/resources/message#edit-message-jsonjson-params
+//
+// nil fields are left untouched by discord, an empty value clears them.
+type EditMessage struct {
+	Content *string  `json:"content,omitempty"`
+	Embeds  *[]Embed `json:"embeds,omitempty"`
+	// an empty slice removes every component
+	Components *[]Component `json:"components,omitempty"`
+	// attachments to keep, the ones left out are removed
+	Attachments *[]PartialAttachment `json:"attachments,omitempty"`
+	// added to the message, see File
+	Files []*File `json:"-"`
+}
+
+func (m *EditMessage) GetFiles() []*File {
+	return m.Files
+}
+
+// https://discord.com/developers/docs/resources/message#get-channel-messages-query-string-params
+//
+// Before, After and Around are mutually exclusive.
+type ChannelMessagesQuery struct {
+	Before string
+	After  string
+	Around string
+	// 1-100, discord defaults to 50
+	Limit int
 }
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// https://discord.com/developers/docs/interactions/message-components#text-input-object-text-input-styles
+type TextInputStyle = ComponentStyle
+
+const (
+	// single line
+	TextInputStyle_SHORT TextInputStyle = iota + 1
+	// multi line
+	TextInputStyle_PARAGRAPH
+)
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-response-object-modal
+//
+// A form shown to the user, up to 5 text inputs.
+// Its custom ID is matched like the component ones when it's submitted.
+type Modal struct {
+	CustomID   string
+	Title      string
+	Components []Component
+}
+
+// ResponseData is the data of the MODAL interaction response showing m.
+func (m *Modal) ResponseData() *InteractionResponseData {
+	return &InteractionResponseData{
+		CustomID:   m.CustomID,
+		Title:      m.Title,
+		Components: m.Components,
+	}
+}
+
+// NewModal puts each input on its own action row, like discord requires.
+func NewModal(customID, title string, inputs ...Component) *Modal {
+	rows := make([]Component, len(inputs))
+	for i, input := range inputs {
+		rows[i] = NewActionRow(input)
+	}
+	return &Modal{CustomID: customID, Title: title, Components: rows}
+}
+
+// https://discord.com/developers/docs/interactions/message-components#text-input-object
+func NewTextInput(customID, label string, style TextInputStyle) Component {
+	return Component{
+		Type:     ComponentType_TEXT_INPUT,
+		CustomID: customID,
+		Label:    label,
+		Style:    style,
+	}
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#interaction-object-modal-submit-data-structure
+type ModalSubmitInteractionData struct {
+	CustomID string `json:"custom_id"`
+	// action rows holding the submitted text inputs
+	Components []Component `json:"components"`
+}
+
+// Values returns the submitted text of each input, keyed by the custom ID of the input.
+func (d *ModalSubmitInteractionData) Values() map[string]string {
+	values := make(map[string]string)
+	for _, row := range d.Components {
+		for _, input := range row.Components {
+			if input.Type == ComponentType_TEXT_INPUT {
+				values[input.CustomID] = input.Value
+			}
+		}
+	}
+	return values
+}
+
+// ModalSubmitData decodes Data of a MODAL_SUBMIT interaction.
+func (i *Interaction) ModalSubmitData() (*ModalSubmitInteractionData, error) {
+	if i.Type != InteractionType_MODAL_SUBMIT {
+		return nil, fmt.Errorf("interaction %s of type %d isn't a modal submit", i.ID, i.Type)
+	}
+	var data ModalSubmitInteractionData
+	if err := json.Unmarshal(i.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode modal submit data: %w", err)
+	}
+	return &data, nil
+}
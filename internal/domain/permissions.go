@@ -0,0 +1,231 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// https://discord.com/developers/docs/topics/permissions#permissions-bitwise-permission-flags
+//
+// Discord serializes permissions as a string, Permissions does the conversion.
+type Permissions uint64
+
+const (
+	Permissions_CREATE_INSTANT_INVITE               Permissions = 1 << 0
+	Permissions_KICK_MEMBERS                        Permissions = 1 << 1
+	Permissions_BAN_MEMBERS                         Permissions = 1 << 2
+	Permissions_ADMINISTRATOR                       Permissions = 1 << 3
+	Permissions_MANAGE_CHANNELS                     Permissions = 1 << 4
+	Permissions_MANAGE_GUILD                        Permissions = 1 << 5
+	Permissions_ADD_REACTIONS                       Permissions = 1 << 6
+	Permissions_VIEW_AUDIT_LOG                      Permissions = 1 << 7
+	Permissions_PRIORITY_SPEAKER                    Permissions = 1 << 8
+	Permissions_STREAM                              Permissions = 1 << 9
+	Permissions_VIEW_CHANNEL                        Permissions = 1 << 10
+	Permissions_SEND_MESSAGES                       Permissions = 1 << 11
+	Permissions_SEND_TTS_MESSAGES                   Permissions = 1 << 12
+	Permissions_MANAGE_MESSAGES                     Permissions = 1 << 13
+	Permissions_EMBED_LINKS                         Permissions = 1 << 14
+	Permissions_ATTACH_FILES                        Permissions = 1 << 15
+	Permissions_READ_MESSAGE_HISTORY                Permissions = 1 << 16
+	Permissions_MENTION_EVERYONE                    Permissions = 1 << 17
+	Permissions_USE_EXTERNAL_EMOJIS                 Permissions = 1 << 18
+	Permissions_VIEW_GUILD_INSIGHTS                 Permissions = 1 << 19
+	Permissions_CONNECT                             Permissions = 1 << 20
+	Permissions_SPEAK                               Permissions = 1 << 21
+	Permissions_MUTE_MEMBERS                        Permissions = 1 << 22
+	Permissions_DEAFEN_MEMBERS                      Permissions = 1 << 23
+	Permissions_MOVE_MEMBERS                        Permissions = 1 << 24
+	Permissions_USE_VAD                             Permissions = 1 << 25
+	Permissions_CHANGE_NICKNAME                     Permissions = 1 << 26
+	Permissions_MANAGE_NICKNAMES                    Permissions = 1 << 27
+	Permissions_MANAGE_ROLES                        Permissions = 1 << 28
+	Permissions_MANAGE_WEBHOOKS                     Permissions = 1 << 29
+	Permissions_MANAGE_GUILD_EXPRESSIONS            Permissions = 1 << 30
+	Permissions_USE_APPLICATION_COMMANDS            Permissions = 1 << 31
+	Permissions_REQUEST_TO_SPEAK                    Permissions = 1 << 32
+	Permissions_MANAGE_EVENTS                       Permissions = 1 << 33
+	Permissions_MANAGE_THREADS                      Permissions = 1 << 34
+	Permissions_CREATE_PUBLIC_THREADS               Permissions = 1 << 35
+	Permissions_CREATE_PRIVATE_THREADS              Permissions = 1 << 36
+	Permissions_USE_EXTERNAL_STICKERS               Permissions = 1 << 37
+	Permissions_SEND_MESSAGES_IN_THREADS            Permissions = 1 << 38
+	Permissions_USE_EMBEDDED_ACTIVITIES             Permissions = 1 << 39
+	Permissions_MODERATE_MEMBERS                    Permissions = 1 << 40
+	Permissions_VIEW_CREATOR_MONETIZATION_ANALYTICS Permissions = 1 << 41
+	Permissions_USE_SOUNDBOARD                      Permissions = 1 << 42
+	Permissions_CREATE_GUILD_EXPRESSIONS            Permissions = 1 << 43
+	Permissions_CREATE_EVENTS                       Permissions = 1 << 44
+	Permissions_USE_EXTERNAL_SOUNDS                 Permissions = 1 << 45
+	Permissions_SEND_VOICE_MESSAGES                 Permissions = 1 << 46
+	Permissions_SEND_POLLS                          Permissions = 1 << 49
+	Permissions_USE_EXTERNAL_APPS                   Permissions = 1 << 50
+)
+
+// every permission, what owners and administrators have
+const Permissions_ALL = ^Permissions(0)
+
+// what a timed out member keeps in the channels
+const timedOutPermissions = Permissions_VIEW_CHANNEL | Permissions_READ_MESSAGE_HISTORY
+
+var permissionNames = []struct {
+	permission Permissions
+	name       string
+}{
+	{Permissions_CREATE_INSTANT_INVITE, "Create Invite"},
+	{Permissions_KICK_MEMBERS, "Kick Members"},
+	{Permissions_BAN_MEMBERS, "Ban Members"},
+	{Permissions_ADMINISTRATOR, "Administrator"},
+	{Permissions_MANAGE_CHANNELS, "Manage Channels"},
+	{Permissions_MANAGE_GUILD, "Manage Server"},
+	{Permissions_ADD_REACTIONS, "Add Reactions"},
+	{Permissions_VIEW_AUDIT_LOG, "View Audit Log"},
+	{Permissions_PRIORITY_SPEAKER, "Priority Speaker"},
+	{Permissions_STREAM, "Video"},
+	{Permissions_VIEW_CHANNEL, "View Channel"},
+	{Permissions_SEND_MESSAGES, "Send Messages"},
+	{Permissions_SEND_TTS_MESSAGES, "Send Text-to-Speech Messages"},
+	{Permissions_MANAGE_MESSAGES, "Manage Messages"},
+	{Permissions_EMBED_LINKS, "Embed Links"},
+	{Permissions_ATTACH_FILES, "Attach Files"},
+	{Permissions_READ_MESSAGE_HISTORY, "Read Message History"},
+	{Permissions_MENTION_EVERYONE, "Mention @everyone"},
+	{Permissions_USE_EXTERNAL_EMOJIS, "Use External Emojis"},
+	{Permissions_VIEW_GUILD_INSIGHTS, "View Server Insights"},
+	{Permissions_CONNECT, "Connect"},
+	{Permissions_SPEAK, "Speak"},
+	{Permissions_MUTE_MEMBERS, "Mute Members"},
+	{Permissions_DEAFEN_MEMBERS, "Deafen Members"},
+	{Permissions_MOVE_MEMBERS, "Move Members"},
+	{Permissions_USE_VAD, "Use Voice Activity"},
+	{Permissions_CHANGE_NICKNAME, "Change Nickname"},
+	{Permissions_MANAGE_NICKNAMES, "Manage Nicknames"},
+	{Permissions_MANAGE_ROLES, "Manage Roles"},
+	{Permissions_MANAGE_WEBHOOKS, "Manage Webhooks"},
+	{Permissions_MANAGE_GUILD_EXPRESSIONS, "Manage Expressions"},
+	{Permissions_USE_APPLICATION_COMMANDS, "Use Application Commands"},
+	{Permissions_REQUEST_TO_SPEAK, "Request to Speak"},
+	{Permissions_MANAGE_EVENTS, "Manage Events"},
+	{Permissions_MANAGE_THREADS, "Manage Threads"},
+	{Permissions_CREATE_PUBLIC_THREADS, "Create Public Threads"},
+	{Permissions_CREATE_PRIVATE_THREADS, "Create Private Threads"},
+	{Permissions_USE_EXTERNAL_STICKERS, "Use External Stickers"},
+	{Permissions_SEND_MESSAGES_IN_THREADS, "Send Messages in Threads"},
+	{Permissions_USE_EMBEDDED_ACTIVITIES, "Use Activities"},
+	{Permissions_MODERATE_MEMBERS, "Timeout Members"},
+	{Permissions_VIEW_CREATOR_MONETIZATION_ANALYTICS, "View Creator Monetization Analytics"},
+	{Permissions_USE_SOUNDBOARD, "Use Soundboard"},
+	{Permissions_CREATE_GUILD_EXPRESSIONS, "Create Expressions"},
+	{Permissions_CREATE_EVENTS, "Create Events"},
+	{Permissions_USE_EXTERNAL_SOUNDS, "Use External Sounds"},
+	{Permissions_SEND_VOICE_MESSAGES, "Send Voice Messages"},
+	{Permissions_SEND_POLLS, "Create Polls"},
+	{Permissions_USE_EXTERNAL_APPS, "Use External Apps"},
+}
+
+// Names returns the names of the permissions set, as the discord client shows them.
+func (p Permissions) Names() []string {
+	var names []string
+	for _, permission := range permissionNames {
+		if p.Has(permission.permission) {
+			names = append(names, permission.name)
+		}
+	}
+	return names
+}
+
+// Missing returns the permissions of required that p doesn't have.
+func (p Permissions) Missing(required Permissions) Permissions {
+	return required &^ p
+}
+
+// Has reports whether all the bits of perms are set.
+func (p Permissions) Has(perms Permissions) bool {
+	return p&perms == perms
+}
+
+func (p Permissions) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatUint(uint64(p), 10))
+}
+
+func (p *Permissions) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*p = Permissions(v)
+	return nil
+}
+
+type PermissionOverwriteType int
+
+const (
+	PermissionOverwriteType_ROLE PermissionOverwriteType = iota
+	PermissionOverwriteType_MEMBER
+)
+
+// https://discord.com/developers/docs/resources/channel#overwrite-object
+type PermissionOverwrite struct {
+	ID    string                  `json:"id"`
+	Type  PermissionOverwriteType `json:"type"`
+	Allow Permissions             `json:"allow"`
+	Deny  Permissions             `json:"deny"`
+}
+
+// https://discord.com/developers/docs/topics/permissions#permission-overwrites
+//
+// ComputePermissions returns the permissions of member in channel, from the roles of guild
+// and the overwrites of channel. A nil channel gives the guild wide permissions.
+// Threads have no overwrites, pass their parent channel instead.
+func ComputePermissions(guild *Guild, channel *Channel, member *Member, now time.Time) Permissions {
+	if member.ID == guild.OwnerID {
+		return Permissions_ALL
+	}
+
+	var permissions Permissions
+	for _, role := range guild.Roles {
+		// the @everyone role has the ID of the guild
+		if role.ID == guild.ID || slices.Contains(member.Roles, role.ID) {
+			permissions |= role.Permissions
+		}
+	}
+	if permissions.Has(Permissions_ADMINISTRATOR) {
+		return Permissions_ALL
+	}
+
+	if channel != nil {
+		var everyone, own *PermissionOverwrite
+		var rolesAllow, rolesDeny Permissions
+		for i := range channel.PermissionOverwrites {
+			overwrite := &channel.PermissionOverwrites[i]
+			switch {
+			case overwrite.Type == PermissionOverwriteType_ROLE && overwrite.ID == guild.ID:
+				everyone = overwrite
+			case overwrite.Type == PermissionOverwriteType_ROLE && slices.Contains(member.Roles, overwrite.ID):
+				rolesAllow |= overwrite.Allow
+				rolesDeny |= overwrite.Deny
+			case overwrite.Type == PermissionOverwriteType_MEMBER && overwrite.ID == member.ID:
+				own = overwrite
+			}
+		}
+		// @everyone first, then every role of the member at once, then the member itself
+		if everyone != nil {
+			permissions = permissions&^everyone.Deny | everyone.Allow
+		}
+		permissions = permissions&^rolesDeny | rolesAllow
+		if own != nil {
+			permissions = permissions&^own.Deny | own.Allow
+		}
+	}
+
+	if member.CommunicationDisabledUntil != nil && member.CommunicationDisabledUntil.After(now) {
+		permissions &= timedOutPermissions
+	}
+	return permissions
+}
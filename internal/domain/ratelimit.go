@@ -1,10 +1,155 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitProxySecretHeader carries the secret shared by the rate limit proxy and the processes behind it.
+const RateLimitProxySecretHeader = "X-Proxy-Secret"
 
 type RateLimit struct {
+	// requests allowed per window, 0 when unknown
+	Limit      int
 	Remaining  int
 	ResetAfter time.Duration
 	ResetAt    time.Time
 	Global     bool
+	// length of a whole window, the longest ResetAfter seen for the bucket.
+	// Set by the rate limiter, ResetAfter is only what's left of the window when the response was sent.
+	Window time.Duration
+}
+
+// https://discord.com/developers/docs/topics/rate-limits#header-format
+//
+// ParseRateLimitHeaders reads the limit discord sent with a response and its bucket hash.
+// It returns nil when the response carries no rate limit headers.
+func ParseRateLimitHeaders(headers http.Header) (*RateLimit, string) {
+	resetAfterStr := headers.Get("X-RateLimit-Reset-After")
+	if resetAfterStr == "" {
+		return nil, ""
+	}
+	resetAfter, err := strconv.ParseFloat(resetAfterStr, 64)
+	if err != nil {
+		return nil, ""
+	}
+
+	limit, _ := strconv.Atoi(headers.Get("X-RateLimit-Limit"))
+	remaining, _ := strconv.Atoi(headers.Get("X-RateLimit-Remaining"))
+
+	return &RateLimit{
+		Limit:      limit,
+		Remaining:  remaining,
+		ResetAfter: time.Duration(resetAfter * float64(time.Second)),
+		Global:     headers.Get("X-RateLimit-Global") == "true",
+	}, headers.Get("X-RateLimit-Bucket")
+}
+
+// SleepContext waits for d, or returns the error of ctx if it's done first.
+func SleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RateLimitResponse is the body of a 429 Too Many Requests.
+type RateLimitResponse struct {
+	Message    string  `json:"message"`
+	RetryAfter float64 `json:"retry_after"`
+	Global     bool    `json:"global"`
+}
+
+// RateLimitQueueMetrics describes how long requests queued for a bucket before being sent.
+type RateLimitQueueMetrics struct {
+	Requests  int64
+	TotalWait time.Duration
+	MaxWait   time.Duration
+}
+
+// https://discord.com/developers/docs/topics/rate-limits
+//
+// Route identifies what discord rate limits: a method, a route template and the major parameter.
+// Two requests to the same template with different major params never share a limit.
+type Route struct {
+	Method string
+	// the endpoint with every ID replaced, e.g. "/channels/{channel_id}/messages/{id}"
+	Template string
+	// the channel ID, guild ID or webhook ID/token the endpoint is scoped to, if any
+	MajorParam string
+}
+
+// Key identifies the route template, it's what discord maps to a bucket.
+func (r Route) Key() string {
+	return r.Method + " " + r.Template
+}
+
+// majorParams are the top level resources discord scopes rate limits by.
+var majorParams = map[string]string{
+	"channels": "{channel_id}",
+	"guilds":   "{guild_id}",
+	"webhooks": "{webhook_id}",
+}
+
+// NewRoute builds the rate limit route of a request to endpoint.
+// The query string is ignored.
+func NewRoute(method, endpoint string) Route {
+	if i := strings.IndexByte(endpoint, '?'); i >= 0 {
+		endpoint = endpoint[:i]
+	}
+
+	segments := strings.Split(strings.Trim(endpoint, "/"), "/")
+	template := make([]string, len(segments))
+	route := Route{Method: method}
+
+	for i, segment := range segments {
+		template[i] = segment
+		if i == 0 {
+			continue
+		}
+		previous := segments[i-1]
+
+		switch {
+		case i == 1 && majorParams[previous] != "":
+			template[i] = majorParams[previous]
+			route.MajorParam = segment
+		case i == 2 && (segments[0] == "webhooks" || segments[0] == "interactions") && !isSnowflake(segment):
+			// webhook and interaction tokens, they are part of the major param
+			template[i] = "{token}"
+			if segments[0] == "webhooks" {
+				route.MajorParam += "/" + segment
+			}
+		case previous == "reactions":
+			template[i] = "{emoji}"
+		case i >= 2 && segments[i-2] == "reactions" && segment != "@me":
+			template[i] = "{user_id}"
+		case isSnowflake(segment):
+			template[i] = "{id}"
+		}
+	}
+
+	route.Template = "/" + strings.Join(template, "/")
+	return route
+}
+
+func isSnowflake(segment string) bool {
+	if segment == "" {
+		return false
+	}
+	for _, c := range segment {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
 }
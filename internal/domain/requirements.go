@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// CommandRequirements are checked before a command runs, see the requirements middleware.
+type CommandRequirements struct {
+	// permissions the user needs in the channel
+	UserPermissions Permissions
+	// permissions the bot needs in the channel to do its job, e.g. CONNECT and SPEAK
+	BotPermissions Permissions
+	// only the owners of the bot, set in the config
+	OwnerOnly bool
+	GuildOnly bool
+	DMOnly    bool
+	// only in age-restricted channels, DMs are allowed
+	NSFWOnly bool
+}
+
+// CommandDeniedError is returned when a command can't be run by this user or here,
+// the reason is shown to the user as is.
+type CommandDeniedError struct {
+	Reason string
+	// when the command can be used again, 0 when waiting won't help
+	RetryAfter time.Duration
+}
+
+func (e *CommandDeniedError) Error() string {
+	return e.Reason
+}
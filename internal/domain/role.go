@@ -0,0 +1,48 @@
+package domain
+
+type RoleFlags int
+
+const (
+	RoleFlags_IN_PROMPT RoleFlags = 1 << 0
+)
+
+// https://discord.com/developers/docs/topics/permissions#role-object-role-tags-structure
+type RoleTags struct {
+	BotID                 *string `json:"bot_id"`
+	IntegrationID         *string `json:"integration_id"`
+	SubscriptionListingID *string `json:"subscription_listing_id"`
+}
+
+// https://discord.com/developers/docs/topics/permissions#role-object
+type Role struct {
+	ID           string      `json:"id"`
+	Name         string      `json:"name"`
+	Color        int         `json:"color"`
+	Hoist        bool        `json:"hoist"`
+	Icon         *string     `json:"icon"`
+	UnicodeEmoji *string     `json:"unicode_emoji"`
+	Position     int         `json:"position"`
+	Permissions  Permissions `json:"permissions"`
+	Managed      bool        `json:"managed"`
+	Mentionable  bool        `json:"mentionable"`
+	Tags         *RoleTags   `json:"tags"`
+	Flags        RoleFlags   `json:"flags"`
+}
+
+// https://discord.com/developers/docs/resources/guild#create-guild-role-json-params
+//
+// Used to create and modify roles, nil fields are left to discord defaults / untouched.
+type RoleParams struct {
+	Name         *string      `json:"name,omitempty"`
+	Permissions  *Permissions `json:"permissions,omitempty"`
+	Color        *int         `json:"color,omitempty"`
+	Hoist        *bool        `json:"hoist,omitempty"`
+	UnicodeEmoji *string      `json:"unicode_emoji,omitempty"`
+	Mentionable  *bool        `json:"mentionable,omitempty"`
+}
+
+// https://discord.com/developers/docs/resources/guild#modify-guild-role-positions-json-params
+type RolePosition struct {
+	ID       string `json:"id"`
+	Position *int   `json:"position,omitempty"`
+}
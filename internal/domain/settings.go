@@ -0,0 +1,63 @@
+package domain
+
+import "maps"
+
+const (
+	// how many prefixes a guild can have, the mention of the bot isn't counted
+	MaxGuildPrefixes = 5
+	MaxPrefixLength  = 10
+
+	// percentage of the volume the bot speaks and plays at
+	DefaultVolume = 100
+	MaxVolume     = 200
+
+	// GuildSettingsVersion is the version of the stored settings schema,
+	// bump it with a migration when the shape of GuildSettings changes.
+	GuildSettingsVersion = 1
+)
+
+// GuildSettings is what a guild configured, see NewGuildSettings for the defaults.
+type GuildSettings struct {
+	// schema version the settings were stored with
+	Version int    `json:"version"`
+	GuildID string `json:"guild_id"`
+	// empty for the default prefix
+	Prefixes []string `json:"prefixes"`
+	// text channel ID -> voice channel ID its messages are read aloud in
+	TTSChannels map[string]string `json:"tts_channels"`
+	// voice used when members didn't pick one, empty for the default of the TTS engine
+	DefaultVoice string `json:"default_voice"`
+	// 0 to MaxVolume
+	Volume int `json:"volume"`
+	// members with one of these roles can control the player, anyone can when empty
+	DJRoleIDs []string `json:"dj_role_ids"`
+}
+
+// NewGuildSettings returns the settings of a guild that didn't configure anything.
+func NewGuildSettings(guildID string) *GuildSettings {
+	return &GuildSettings{
+		Version: GuildSettingsVersion,
+		GuildID: guildID,
+		Volume:  DefaultVolume,
+	}
+}
+
+// Clone returns a copy that doesn't share its slices and maps, for stores handing out settings.
+func (s *GuildSettings) Clone() *GuildSettings {
+	clone := *s
+	clone.Prefixes = append([]string(nil), s.Prefixes...)
+	clone.TTSChannels = maps.Clone(s.TTSChannels)
+	clone.DJRoleIDs = append([]string(nil), s.DJRoleIDs...)
+	return &clone
+}
+
+// PrefixMatch is how a message calls a command.
+type PrefixMatch struct {
+	// the prefix as typed, or the first prefix of the guild when the bot was mentioned,
+	// usages are shown with it
+	Prefix string
+	// what comes after the prefix
+	Line string
+	// the bot was mentioned instead of using a prefix
+	Mention bool
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+// https://discord.com/developers/docs/reference#snowflakes
+const DiscordEpoch = 1420070400000
+
+// SnowflakeTime returns the creation time encoded in a Discord snowflake ID.
+func SnowflakeTime(ID string) (time.Time, error) {
+	snowflake, err := strconv.ParseUint(ID, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(int64(snowflake>>22) + DiscordEpoch), nil
+}
+
+// IsSnowflake reports whether ID is made of digits only, like the IDs discord gives.
+func IsSnowflake(ID string) bool {
+	_, err := strconv.ParseUint(ID, 10, 64)
+	return err == nil
+}
@@ -0,0 +1,164 @@
+package argumentsparser
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// invalidArgument is the reason a value was rejected, it becomes a usage error.
+// Any other error (the API being down, ...) isn't the user's fault.
+type invalidArgument string
+
+func (e invalidArgument) Error() string {
+	return string(e)
+}
+
+type converter func(ctx context.Context, c interfaces.Client, guildID, value string) (any, error)
+
+var converters = map[domain.ArgumentType]converter{
+	domain.ArgumentType_STRING:   convertString,
+	domain.ArgumentType_INT:      convertInt,
+	domain.ArgumentType_FLOAT:    convertFloat,
+	domain.ArgumentType_BOOL:     convertBool,
+	domain.ArgumentType_DURATION: convertDuration,
+	domain.ArgumentType_USER:     convertUser,
+	domain.ArgumentType_MEMBER:   convertMember,
+	domain.ArgumentType_CHANNEL:  convertChannel,
+	domain.ArgumentType_ROLE:     convertRole,
+}
+
+// https://discord.com/developers/docs/reference#message-formatting
+var (
+	userMentionPattern    = regexp.MustCompile(`^<@!?(\d{17,20})>$`)
+	channelMentionPattern = regexp.MustCompile(`^<#(\d{17,20})>$`)
+	roleMentionPattern    = regexp.MustCompile(`^<@&(\d{17,20})>$`)
+	snowflakePattern      = regexp.MustCompile(`^\d{17,20}$`)
+)
+
+// mentionedID returns the ID of a mention matching pattern, or the value itself when it's an ID.
+func mentionedID(pattern *regexp.Regexp, value string) (string, bool) {
+	if match := pattern.FindStringSubmatch(value); match != nil {
+		return match[1], true
+	}
+	if snowflakePattern.MatchString(value) {
+		return value, true
+	}
+	return "", false
+}
+
+func convertString(ctx context.Context, c interfaces.Client, guildID, value string) (any, error) {
+	return value, nil
+}
+
+func convertInt(ctx context.Context, c interfaces.Client, guildID, value string) (any, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, invalidArgument(fmt.Sprintf("%q isn't a whole number", value))
+	}
+	return n, nil
+}
+
+func convertFloat(ctx context.Context, c interfaces.Client, guildID, value string) (any, error) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, invalidArgument(fmt.Sprintf("%q isn't a number", value))
+	}
+	return n, nil
+}
+
+func convertBool(ctx context.Context, c interfaces.Client, guildID, value string) (any, error) {
+	switch strings.ToLower(value) {
+	case "yes", "y", "on", "true", "1", "enable":
+		return true, nil
+	case "no", "n", "off", "false", "0", "disable":
+		return false, nil
+	}
+	return nil, invalidArgument(fmt.Sprintf("%q isn't yes or no", value))
+}
+
+func convertDuration(ctx context.Context, c interfaces.Client, guildID, value string) (any, error) {
+	d, ok := domain.ParseDuration(value)
+	if !ok {
+		return nil, invalidArgument(fmt.Sprintf("%q isn't a duration, try 90s, 1m30s or 1:30", value))
+	}
+	return d, nil
+}
+
+func convertUser(ctx context.Context, c interfaces.Client, guildID, value string) (any, error) {
+	ID, ok := mentionedID(userMentionPattern, value)
+	if !ok {
+		return nil, invalidArgument(fmt.Sprintf("%q isn't a user mention or ID", value))
+	}
+	// members are cached, other users are fetched
+	if guildID != "" {
+		if member, err := c.GetMember(ctx, ID, guildID); err == nil && member.User != nil {
+			return member.User, nil
+		}
+	}
+	user, err := c.GetAPIRequester().GetUser(ctx, ID)
+	if domain.IsNotFound(err) {
+		return nil, invalidArgument(fmt.Sprintf("user %s doesn't exist", ID))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func convertMember(ctx context.Context, c interfaces.Client, guildID, value string) (any, error) {
+	if guildID == "" {
+		return nil, invalidArgument("members can only be given in a server")
+	}
+	ID, ok := mentionedID(userMentionPattern, value)
+	if !ok {
+		return nil, invalidArgument(fmt.Sprintf("%q isn't a member mention or ID", value))
+	}
+	member, err := c.GetMember(ctx, ID, guildID)
+	if domain.IsNotFound(err) {
+		return nil, invalidArgument(fmt.Sprintf("<@%s> isn't a member of this server", ID))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+func convertChannel(ctx context.Context, c interfaces.Client, guildID, value string) (any, error) {
+	ID, ok := mentionedID(channelMentionPattern, value)
+	if !ok {
+		return nil, invalidArgument(fmt.Sprintf("%q isn't a channel mention or ID", value))
+	}
+	channel, err := c.GetChannel(ctx, ID)
+	if domain.IsNotFound(err) || domain.IsMissingAccess(err) || (err == nil && channel.GuildID != guildID) {
+		return nil, invalidArgument(fmt.Sprintf("channel %s isn't in this server", ID))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+func convertRole(ctx context.Context, c interfaces.Client, guildID, value string) (any, error) {
+	if guildID == "" {
+		return nil, invalidArgument("roles can only be given in a server")
+	}
+	guild, err := c.GetGuild(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	ID, byID := mentionedID(roleMentionPattern, value)
+	for i := range guild.Roles {
+		role := &guild.Roles[i]
+		if (byID && role.ID == ID) || (!byID && strings.EqualFold(role.Name, value)) {
+			return role, nil
+		}
+	}
+	return nil, invalidArgument(fmt.Sprintf("role %q doesn't exist in this server", value))
+}
@@ -0,0 +1,110 @@
+package argumentsparser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+type ArgumentsParserImpl struct {
+	client interfaces.Client
+}
+
+func NewArgumentsParser(c interfaces.Client) interfaces.ArgumentsParser {
+	return &ArgumentsParserImpl{client: c}
+}
+
+func (p *ArgumentsParserImpl) Parse(ctx context.Context, guildID string, arguments []domain.Argument, input string) (*domain.Args, error) {
+	if err := validateArguments(arguments); err != nil {
+		return nil, err
+	}
+
+	// the text of a trailing REST argument is kept as typed, it isn't tokenized
+	n := -1
+	if len(arguments) > 0 && arguments[len(arguments)-1].Type == domain.ArgumentType_REST {
+		n = len(arguments) - 1
+	}
+	tokens, rest, err := domain.TokenizeN(input, n)
+	if err != nil {
+		return nil, &domain.UsageError{Reason: err.Error()}
+	}
+
+	args := domain.NewArgs()
+	next := 0
+	for i := range arguments {
+		argument := &arguments[i]
+
+		if argument.Type == domain.ArgumentType_REST {
+			if text := strings.TrimSpace(rest); text != "" {
+				args.Add(argument.Name, text)
+			} else if !argument.Optional {
+				return nil, &domain.UsageError{Argument: argument, Reason: "missing " + argument.Type.String()}
+			}
+			continue
+		}
+
+		if next >= len(tokens) {
+			if !argument.Optional {
+				return nil, &domain.UsageError{Argument: argument, Reason: "missing " + argument.Type.String()}
+			}
+			continue
+		}
+
+		count := 1
+		if argument.Variadic {
+			count = len(tokens) - next
+		}
+		for _, token := range tokens[next : next+count] {
+			value, err := p.convert(ctx, guildID, argument, token.Value)
+			if err != nil {
+				return nil, err
+			}
+			args.Add(argument.Name, value)
+		}
+		next += count
+	}
+
+	if next < len(tokens) {
+		return nil, &domain.UsageError{Reason: fmt.Sprintf("too many arguments, %q wasn't expected", tokens[next].Value)}
+	}
+	return args, nil
+}
+
+func (p *ArgumentsParserImpl) convert(ctx context.Context, guildID string, argument *domain.Argument, value string) (any, error) {
+	converted, err := converters[argument.Type](ctx, p.client, guildID, value)
+	if err == nil {
+		return converted, nil
+	}
+	var reason invalidArgument
+	if errors.As(err, &reason) {
+		return nil, &domain.UsageError{Argument: argument, Reason: string(reason)}
+	}
+	return nil, fmt.Errorf("[ArgumentsParser] failed to convert %s: %w", argument.Placeholder(), err)
+}
+
+func validateArguments(arguments []domain.Argument) error {
+	optional := false
+	names := make(map[string]bool, len(arguments))
+	for i, argument := range arguments {
+		if names[argument.Name] {
+			return fmt.Errorf("[ArgumentsParser] argument %q is declared twice", argument.Name)
+		}
+		names[argument.Name] = true
+		if _, ok := converters[argument.Type]; !ok && argument.Type != domain.ArgumentType_REST {
+			return fmt.Errorf("[ArgumentsParser] argument %q has an unknown type %d", argument.Name, argument.Type)
+		}
+		if !argument.Optional && optional {
+			return fmt.Errorf("[ArgumentsParser] required argument %q comes after an optional one", argument.Name)
+		}
+		optional = optional || argument.Optional
+		last := i == len(arguments)-1
+		if (argument.Variadic || argument.Type == domain.ArgumentType_REST) && !last {
+			return fmt.Errorf("[ArgumentsParser] argument %q takes the rest of the line, it must be the last", argument.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package argumentsparser
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+const (
+	guildID  = "100000000000000001"
+	memberID = "200000000000000002"
+	roleID   = "300000000000000003"
+)
+
+type fakeClient struct {
+	interfaces.Client
+}
+
+func (f *fakeClient) GetMember(ctx context.Context, ID, guild string) (*domain.Member, error) {
+	if ID != memberID || guild != guildID {
+		return nil, &domain.APIError{Status: 404, Code: domain.APIErrorCode_UNKNOWN_MEMBER}
+	}
+	return &domain.Member{ID: memberID, GuildID: guildID, User: &domain.User{ID: memberID, Username: "someone"}}, nil
+}
+
+func (f *fakeClient) GetGuild(ctx context.Context, ID string) (*domain.Guild, error) {
+	return &domain.Guild{ID: ID, Roles: []domain.Role{{ID: roleID, Name: "DJ"}}}, nil
+}
+
+func TestTokenize(t *testing.T) {
+	tokens, err := domain.Tokenize(`  play "never gonna" give\ you 'up now' it's \"x\"`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"play", "never gonna", "give you", "up now", "it's", `"x"`}
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %+v", len(expected), tokens)
+	}
+	for i, token := range tokens {
+		if token.Value != expected[i] {
+			t.Fatalf("Expected token %d to be %q, got %q", i, expected[i], token.Value)
+		}
+	}
+
+	if _, err := domain.Tokenize(`"unclosed`); !errors.Is(err, domain.ErrUnclosedQuote) {
+		t.Fatalf("Expected an unclosed quote error, got %v", err)
+	}
+}
+
+func TestParse_ConvertsArguments(t *testing.T) {
+	parser := NewArgumentsParser(&fakeClient{})
+	arguments := []domain.Argument{
+		{Name: "target", Type: domain.ArgumentType_MEMBER},
+		{Name: "role", Type: domain.ArgumentType_ROLE},
+		{Name: "for", Type: domain.ArgumentType_DURATION},
+		{Name: "silent", Type: domain.ArgumentType_BOOL, Optional: true},
+		{Name: "reason", Type: domain.ArgumentType_REST, Optional: true},
+	}
+
+	args, err := parser.Parse(context.Background(), guildID, arguments, ` <@!`+memberID+`> dj 1:30 yes being "too" loud `)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if member := args.Member("target"); member == nil || member.ID != memberID {
+		t.Fatalf("Expected the mentioned member, got %+v", member)
+	}
+	if role := args.Role("role"); role == nil || role.ID != roleID {
+		t.Fatalf("Expected the role found by name, got %+v", role)
+	}
+	if d := args.Duration("for"); d != 90*time.Second {
+		t.Fatalf("Expected 1m30s, got %s", d)
+	}
+	if !args.Bool("silent") {
+		t.Fatalf("Expected silent to be true")
+	}
+	if reason := args.String("reason"); reason != `being "too" loud` {
+		t.Fatalf("Expected the rest of the line as typed, got %q", reason)
+	}
+}
+
+func TestParse_RestIsntTokenized(t *testing.T) {
+	parser := NewArgumentsParser(&fakeClient{})
+	arguments := []domain.Argument{
+		{Name: "for", Type: domain.ArgumentType_DURATION},
+		{Name: "text", Type: domain.ArgumentType_REST},
+	}
+
+	tests := map[string]string{
+		`10s don't "quote`:     `don't "quote`,
+		`"1:30"   it's "fine"`: `it's "fine"`,
+		` 5 \ trailing slash\`: `\ trailing slash\`,
+	}
+	for input, text := range tests {
+		args, err := parser.Parse(context.Background(), guildID, arguments, input)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", input, err)
+		}
+		if args.String("text") != text {
+			t.Fatalf("%q: expected the text as typed %q, got %q", input, text, args.String("text"))
+		}
+	}
+
+	var usageErr *domain.UsageError
+	if _, err := parser.Parse(context.Background(), guildID, arguments, `10s   `); !errors.As(err, &usageErr) || usageErr.Argument.Name != "text" {
+		t.Fatalf("Expected the text to be missing, got %v", err)
+	}
+	// the arguments before the text are still tokenized strictly
+	if _, err := parser.Parse(context.Background(), guildID, arguments, `"10s don't`); !errors.As(err, &usageErr) {
+		t.Fatalf("Expected an unclosed quote before the text to be refused, got %v", err)
+	}
+}
+
+func TestParse_Variadic(t *testing.T) {
+	parser := NewArgumentsParser(&fakeClient{})
+	arguments := []domain.Argument{
+		{Name: "volume", Type: domain.ArgumentType_INT},
+		{Name: "tags", Type: domain.ArgumentType_STRING, Optional: true, Variadic: true},
+	}
+
+	args, err := parser.Parse(context.Background(), guildID, arguments, `50 rock "hip hop"`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if args.Int("volume") != 50 {
+		t.Fatalf("Expected volume 50, got %d", args.Int("volume"))
+	}
+	if tags := args.Strings("tags"); len(tags) != 2 || tags[1] != "hip hop" {
+		t.Fatalf("Expected 2 tags, got %q", tags)
+	}
+
+	args, err = parser.Parse(context.Background(), guildID, arguments, `50`)
+	if err != nil || args.Has("tags") {
+		t.Fatalf("Expected no tags, got %v, %v", args, err)
+	}
+}
+
+func TestParse_UsageErrors(t *testing.T) {
+	parser := NewArgumentsParser(&fakeClient{})
+	arguments := []domain.Argument{
+		{Name: "volume", Type: domain.ArgumentType_INT},
+		{Name: "target", Type: domain.ArgumentType_MEMBER, Optional: true},
+	}
+
+	tests := map[string]string{
+		"missing":       ``,
+		"invalid":       `loud`,
+		"unknown":       `5 <@999999999999999999>`,
+		"too many":      `5 <@` + memberID + `> extra`,
+		"unclosed":      `"5`,
+		"not a mention": `5 someone`,
+	}
+	for name, input := range tests {
+		_, err := parser.Parse(context.Background(), guildID, arguments, input)
+		var usageErr *domain.UsageError
+		if !errors.As(err, &usageErr) {
+			t.Fatalf("%s: expected a usage error, got %v", name, err)
+		}
+	}
+
+	usage := domain.Usage(".volume", arguments)
+	if usage != ".volume <volume> [target]" {
+		t.Fatalf("Unexpected usage %q", usage)
+	}
+}
+
+func TestParse_InvalidSchema(t *testing.T) {
+	parser := NewArgumentsParser(&fakeClient{})
+	arguments := []domain.Argument{
+		{Name: "query", Type: domain.ArgumentType_REST},
+		{Name: "volume", Type: domain.ArgumentType_INT},
+	}
+
+	_, err := parser.Parse(context.Background(), guildID, arguments, `a b`)
+	var usageErr *domain.UsageError
+	if err == nil || errors.As(err, &usageErr) {
+		t.Fatalf("Expected a schema error, got %v", err)
+	}
+}
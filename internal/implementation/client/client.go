@@ -3,7 +3,6 @@ package client
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -53,8 +52,14 @@ type clientImpl struct {
 	cm      interfaces.DiscordCacheManager
 	ar      interfaces.APIRequester
 
+	guildsFetch   *fetchGroup[*domain.Guild]
+	channelsFetch *fetchGroup[*domain.Channel]
+	membersFetch  *fetchGroup[*domain.Member]
+
 	authenticated bool
-	authMu        sync.Mutex
+	// the bot user, set on READY
+	selfUser *domain.User
+	authMu   sync.Mutex
 
 	sessionID         string
 	resumeGatewayURL  string
@@ -91,6 +96,9 @@ func NewClient(options *CLientOptions) (interfaces.Client, error) {
 		wp:            options.Wp,
 		cm:            options.Cm,
 		ar:            options.Ar,
+		guildsFetch:   newFetchGroup[*domain.Guild](),
+		channelsFetch: newFetchGroup[*domain.Channel](),
+		membersFetch:  newFetchGroup[*domain.Member](),
 		eventHandlers: make(map[string][]*clientHandler),
 		shutdown:      make(chan struct{}),
 		authenticated: false,
@@ -98,20 +106,27 @@ func NewClient(options *CLientOptions) (interfaces.Client, error) {
 }
 
 func (c *clientImpl) SetGuild(guild *domain.Guild) {
+	c.guildsFetch.Forget(guild.ID)
 	c.cm.SetGuild(guild)
 }
 
 func (c *clientImpl) DelGuild(ID string) {
+	c.guildsFetch.Forget(ID)
 	c.cm.DelGuild(ID)
 }
 
-func (c *clientImpl) GetGuild(ID string) (*domain.Guild, error) {
+func (c *clientImpl) GetGuild(ctx context.Context, ID string) (*domain.Guild, error) {
 	guild, exist := c.cm.GetGuild(ID)
-	if !exist {
-		// TODO: ask discord api for the guild object
-		return nil, errors.New("Guild not found")
+	if exist {
+		return guild, nil
 	}
-	return guild, nil
+	return c.guildsFetch.Do(ctx, ID, func(ctx context.Context) (*domain.Guild, error) {
+		guild, err := c.ar.GetGuild(ctx, ID)
+		if err != nil {
+			return nil, fetchError("guild", ID, err)
+		}
+		return guild, nil
+	}, c.cm.SetGuild)
 }
 
 func (c *clientImpl) GetGuilds() map[string]*domain.Guild {
@@ -119,40 +134,136 @@ func (c *clientImpl) GetGuilds() map[string]*domain.Guild {
 }
 
 func (c *clientImpl) SetChannel(channel *domain.Channel) {
+	c.channelsFetch.Forget(channel.ID)
 	c.cm.SetChannel(channel)
 }
 
 func (c *clientImpl) DelChannel(ID string) {
+	c.channelsFetch.Forget(ID)
 	c.cm.DelChannel(ID)
 }
 
-func (c *clientImpl) GetChannel(ID string) (*domain.Channel, error) {
+func (c *clientImpl) GetChannel(ctx context.Context, ID string) (*domain.Channel, error) {
 	channel, exist := c.cm.GetChannel(ID)
-	if !exist {
-		// TODO: ask discord api for the Channel object
-		return nil, errors.New("Channel not found")
+	if exist {
+		return channel, nil
 	}
-	return channel, nil
+	return c.channelsFetch.Do(ctx, ID, func(ctx context.Context) (*domain.Channel, error) {
+		channel, err := c.ar.GetChannel(ctx, ID)
+		if err != nil {
+			return nil, fetchError("channel", ID, err)
+		}
+		return channel, nil
+	}, c.cm.SetChannel)
+}
+
+func (c *clientImpl) GetGuildChannels(guildID string) []*domain.Channel {
+	return c.cm.GetGuildChannels(guildID)
 }
 
 func (c *clientImpl) SetMember(member *domain.Member) {
+	c.membersFetch.Forget(memberFetchKey(member.ID, member.GuildID))
 	c.cm.SetMember(member)
 }
 
 func (c *clientImpl) DelMember(memberID, guildID string) {
+	c.membersFetch.Forget(memberFetchKey(memberID, guildID))
 	c.cm.DelMember(memberID, guildID)
 }
 
-func (c *clientImpl) GetMember(memberID, guildID string) (*domain.Member, error) {
+func (c *clientImpl) GetMember(ctx context.Context, memberID, guildID string) (*domain.Member, error) {
 	member, exist := c.cm.GetMember(memberID, guildID)
-	if !exist {
-		// TODO: ask discord api for the Member object
-		return nil, errors.New("Member not found")
+	if exist {
+		return member, nil
+	}
+	return c.membersFetch.Do(ctx, memberFetchKey(memberID, guildID), func(ctx context.Context) (*domain.Member, error) {
+		member, err := c.ar.GetGuildMember(ctx, guildID, memberID)
+		if err != nil {
+			return nil, fetchError("member", memberID, err)
+		}
+		return member, nil
+	}, c.cm.SetMember)
+}
+
+// fetchError says "not found" only when discord said so, not for network errors or 5xx.
+func fetchError(what, ID string, err error) error {
+	if domain.IsNotFound(err) {
+		return fmt.Errorf("%s %s not found: %w", what, ID, err)
 	}
-	return member, nil
+	return fmt.Errorf("fetch %s %s: %w", what, ID, err)
+}
+
+// memberFetchKey keys the fetches of a member, snowflakes vary in length so the IDs are separated.
+func memberFetchKey(memberID, guildID string) string {
+	return guildID + ":" + memberID
+}
+
+func (c *clientImpl) GetSelfUser() *domain.User {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	return c.selfUser
+}
+
+func (c *clientImpl) GetAPIRequester() interfaces.APIRequester {
+	return c.ar
+}
+
+func (c *clientImpl) SendMessage(ctx context.Context, channelID string, message *domain.SendMessage) (*domain.Message, error) {
+	return c.ar.SendMessage(ctx, channelID, message)
+}
+
+func (c *clientImpl) EditMessage(ctx context.Context, channelID, messageID string, message *domain.EditMessage) (*domain.Message, error) {
+	return c.ar.EditMessage(ctx, channelID, messageID, message)
+}
+
+func (c *clientImpl) DeleteMessage(ctx context.Context, channelID, messageID string) error {
+	return c.ar.DeleteMessage(ctx, channelID, messageID)
+}
+
+func (c *clientImpl) BulkDeleteMessages(ctx context.Context, channelID string, messageIDs []string) error {
+	return c.ar.BulkDeleteMessages(ctx, channelID, messageIDs)
+}
+
+func (c *clientImpl) GetMessage(ctx context.Context, channelID, messageID string) (*domain.Message, error) {
+	return c.ar.GetMessage(ctx, channelID, messageID)
+}
+
+func (c *clientImpl) GetChannelMessages(ctx context.Context, channelID string, query *domain.ChannelMessagesQuery) ([]domain.Message, error) {
+	return c.ar.GetChannelMessages(ctx, channelID, query)
+}
+
+func (c *clientImpl) PinMessage(ctx context.Context, channelID, messageID string) error {
+	return c.ar.PinMessage(ctx, channelID, messageID)
+}
+
+func (c *clientImpl) UnpinMessage(ctx context.Context, channelID, messageID string) error {
+	return c.ar.UnpinMessage(ctx, channelID, messageID)
+}
+
+func (c *clientImpl) CrosspostMessage(ctx context.Context, channelID, messageID string) (*domain.Message, error) {
+	return c.ar.CrosspostMessage(ctx, channelID, messageID)
+}
+
+func (c *clientImpl) CreateReaction(ctx context.Context, channelID, messageID, emoji string) error {
+	return c.ar.CreateReaction(ctx, channelID, messageID, emoji)
+}
+
+func (c *clientImpl) DeleteOwnReaction(ctx context.Context, channelID, messageID, emoji string) error {
+	return c.ar.DeleteOwnReaction(ctx, channelID, messageID, emoji)
+}
+
+func (c *clientImpl) DeleteUserReaction(ctx context.Context, channelID, messageID, emoji, userID string) error {
+	return c.ar.DeleteUserReaction(ctx, channelID, messageID, emoji, userID)
+}
+
+func (c *clientImpl) GetReactions(ctx context.Context, channelID, messageID, emoji string, query *domain.ReactionsQuery) ([]domain.User, error) {
+	return c.ar.GetReactions(ctx, channelID, messageID, emoji, query)
+}
+
+func (c *clientImpl) DeleteAllReactions(ctx context.Context, channelID, messageID string) error {
+	return c.ar.DeleteAllReactions(ctx, channelID, messageID)
 }
 
-func (c *clientImpl) SendMessage(channelID string, message *domain.SendMessage) error {
-	c.ar.SendMessage(channelID, message)
-	return nil
+func (c *clientImpl) DeleteAllReactionsForEmoji(ctx context.Context, channelID, messageID, emoji string) error {
+	return c.ar.DeleteAllReactionsForEmoji(ctx, channelID, messageID, emoji)
 }
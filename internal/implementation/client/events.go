@@ -101,6 +101,7 @@ func (c *clientImpl) handleReady(data json.RawMessage) {
 
 	c.authMu.Lock()
 	c.authenticated = true
+	c.selfUser = &ready.User
 	c.authMu.Unlock()
 
 	c.sessionID = ready.SessionID
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+const (
+	// how long a "not found" is remembered before discord is asked again
+	negativeCacheTTL = 15 * time.Second
+	// misses are swept once there are this many of them, so unique bad IDs can't grow it forever
+	negativeCacheSweepSize = 1000
+	// upper bound of a shared fetch, it can't rely on the deadline of whoever started it
+	fetchTimeout = 10 * time.Second
+)
+
+type fetchCall[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+	// the key was forgotten while fetching, what was fetched is older than what the gateway said
+	stale bool
+}
+
+// fetchGroup deduplicates concurrent fetches of the same key (singleflight)
+// and remembers "not found" answers for negativeCacheTTL.
+// Other failures (network, 5xx, rate limits) aren't remembered, the next caller retries.
+type fetchGroup[T any] struct {
+	mu     sync.Mutex
+	calls  map[string]*fetchCall[T]
+	misses map[string]fetchMiss
+}
+
+type fetchMiss struct {
+	err       error
+	expiresAt time.Time
+}
+
+func newFetchGroup[T any]() *fetchGroup[T] {
+	return &fetchGroup[T]{
+		calls:  make(map[string]*fetchCall[T]),
+		misses: make(map[string]fetchMiss),
+	}
+}
+
+// Do runs fetch once for all the callers asking for key at the same time, then store with what was fetched.
+// store is skipped when key was forgotten during the fetch, so it can't undo a newer update or delete.
+// The fetch runs detached from ctx cancellation, a caller giving up doesn't cancel it for the others.
+func (g *fetchGroup[T]) Do(ctx context.Context, key string, fetch func(ctx context.Context) (T, error), store func(T)) (T, error) {
+	var zero T
+
+	g.mu.Lock()
+	if miss, ok := g.misses[key]; ok {
+		if time.Now().Before(miss.expiresAt) {
+			g.mu.Unlock()
+			return zero, miss.err
+		}
+		delete(g.misses, key)
+	}
+
+	call, ok := g.calls[key]
+	if !ok {
+		call = &fetchCall[T]{done: make(chan struct{})}
+		g.calls[key] = call
+		go g.run(context.WithoutCancel(ctx), key, call, fetch, store)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+func (g *fetchGroup[T]) run(ctx context.Context, key string, call *fetchCall[T], fetch func(ctx context.Context) (T, error), store func(T)) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	call.val, call.err = fetch(ctx)
+	cancel()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	if call.err == nil && !call.stale {
+		store(call.val)
+	}
+	if call.err != nil && domain.IsNotFound(call.err) && !call.stale {
+		if len(g.misses) >= negativeCacheSweepSize {
+			g.sweep()
+		}
+		g.misses[key] = fetchMiss{err: call.err, expiresAt: time.Now().Add(negativeCacheTTL)}
+	}
+	g.mu.Unlock()
+
+	close(call.done)
+}
+
+// Forget drops a remembered miss and keeps a fetch in flight from being stored,
+// used when the object is updated or deleted through the gateway.
+// Call it before changing the cache, a fetch finishing in between would store over it otherwise.
+func (g *fetchGroup[T]) Forget(key string) {
+	g.mu.Lock()
+	delete(g.misses, key)
+	if call, ok := g.calls[key]; ok {
+		call.stale = true
+	}
+	g.mu.Unlock()
+}
+
+func (g *fetchGroup[T]) sweep() {
+	now := time.Now()
+	for key, miss := range g.misses {
+		if now.After(miss.expiresAt) {
+			delete(g.misses, key)
+		}
+	}
+}
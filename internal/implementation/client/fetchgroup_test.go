@@ -0,0 +1,222 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/implementation/discordcache"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+const fetchedID = "100000000000000001"
+
+func TestFetchGroup_DeduplicatesConcurrentCallers(t *testing.T) {
+	group := newFetchGroup[string]()
+	release := make(chan struct{})
+	var fetches, stores atomic.Int32
+	fetch := func(ctx context.Context) (string, error) {
+		fetches.Add(1)
+		<-release
+		return "guild", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = group.Do(context.Background(), fetchedID, fetch, func(string) { stores.Add(1) })
+		}()
+	}
+	// let the callers join the fetch before it ends
+	for deadline := time.Now().Add(time.Second); fetches.Load() == 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if fetches.Load() != 1 || stores.Load() != 1 {
+		t.Fatalf("Expected 1 fetch stored once, got %d fetches and %d stores", fetches.Load(), stores.Load())
+	}
+	for _, result := range results {
+		if result != "guild" {
+			t.Fatalf("Expected every caller to get the fetched value, got %q", result)
+		}
+	}
+}
+
+func TestFetchGroup_RemembersNotFound(t *testing.T) {
+	group := newFetchGroup[string]()
+	var fetches int
+	fetch := func(ctx context.Context) (string, error) {
+		fetches++
+		return "", &domain.APIError{Status: 404, Code: domain.APIErrorCode_UNKNOWN_GUILD}
+	}
+	store := func(string) { t.Fatalf("Expected a miss not to be stored") }
+
+	for range 3 {
+		if _, err := group.Do(context.Background(), fetchedID, fetch, store); !domain.IsNotFound(err) {
+			t.Fatalf("Expected a not found error, got %v", err)
+		}
+	}
+	if fetches != 1 {
+		t.Fatalf("Expected the miss to be remembered, got %d fetches", fetches)
+	}
+
+	group.Forget(fetchedID)
+	group.Do(context.Background(), fetchedID, fetch, store)
+	if fetches != 2 {
+		t.Fatalf("Expected a forgotten miss to be fetched again, got %d fetches", fetches)
+	}
+
+	// expired misses are fetched again
+	group.misses[fetchedID] = fetchMiss{err: errors.New("not found"), expiresAt: time.Now().Add(-time.Second)}
+	group.Do(context.Background(), fetchedID, fetch, store)
+	if fetches != 3 {
+		t.Fatalf("Expected an expired miss to be fetched again, got %d fetches", fetches)
+	}
+}
+
+func TestFetchGroup_DoesntRememberOtherFailures(t *testing.T) {
+	group := newFetchGroup[string]()
+	var fetches int
+	fetch := func(ctx context.Context) (string, error) {
+		fetches++
+		return "", &domain.APIError{Status: 502}
+	}
+
+	for range 2 {
+		if _, err := group.Do(context.Background(), fetchedID, fetch, func(string) {}); err == nil || domain.IsNotFound(err) {
+			t.Fatalf("Expected the 5xx error, got %v", err)
+		}
+	}
+	if fetches != 2 || len(group.misses) != 0 {
+		t.Fatalf("Expected 5xx errors to be retried, got %d fetches and %d misses", fetches, len(group.misses))
+	}
+}
+
+func TestFetchGroup_CancelledCaller(t *testing.T) {
+	group := newFetchGroup[string]()
+	release := make(chan struct{})
+	fetched := make(chan error, 2)
+	fetch := func(ctx context.Context) (string, error) {
+		<-release
+		// the fetch outlives the caller that started it, bounded by fetchTimeout
+		deadline, ok := ctx.Deadline()
+		if !ok || time.Until(deadline) > fetchTimeout {
+			fetched <- errors.New("expected the fetch to be bounded by fetchTimeout")
+		}
+		fetched <- ctx.Err()
+		return "guild", nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := group.Do(ctx, fetchedID, fetch, func(string) {})
+		done <- err
+	}()
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the cancelled caller to give up, got %v", err)
+	}
+
+	waiting := make(chan string)
+	go func() {
+		value, _ := group.Do(context.Background(), fetchedID, fetch, func(string) {})
+		waiting <- value
+	}()
+	close(release)
+	if err := <-fetched; err != nil {
+		t.Fatalf("Expected the fetch to go on, got %v", err)
+	}
+	if value := <-waiting; value != "guild" {
+		t.Fatalf("Expected the other caller to get the fetched value, got %q", value)
+	}
+}
+
+func TestFetchGroup_ForgetDuringFetchSkipsStore(t *testing.T) {
+	group := newFetchGroup[string]()
+	started, release := make(chan struct{}), make(chan struct{})
+	fetch := func(ctx context.Context) (string, error) {
+		close(started)
+		<-release
+		return "guild", nil
+	}
+
+	done := make(chan string)
+	go func() {
+		value, _ := group.Do(context.Background(), fetchedID, fetch, func(string) { t.Errorf("Expected the stale fetch not to be stored") })
+		done <- value
+	}()
+	<-started
+	group.Forget(fetchedID)
+	close(release)
+	if value := <-done; value != "guild" {
+		t.Fatalf("Expected the caller to still get the fetched value, got %q", value)
+	}
+}
+
+// blockingAPI answers guild fetches once release is closed.
+type blockingAPI struct {
+	interfaces.APIRequester
+	started, release chan struct{}
+	err              error
+}
+
+func (a *blockingAPI) GetGuild(ctx context.Context, guildID string) (*domain.Guild, error) {
+	close(a.started)
+	<-a.release
+	if a.err != nil {
+		return nil, a.err
+	}
+	return &domain.Guild{ID: guildID}, nil
+}
+
+func TestClient_DeleteDuringFetchIsKept(t *testing.T) {
+	api := &blockingAPI{started: make(chan struct{}), release: make(chan struct{})}
+	c := &clientImpl{cm: discordcache.NewDiscordCacheManager(), ar: api, guildsFetch: newFetchGroup[*domain.Guild]()}
+
+	done := make(chan error)
+	go func() {
+		_, err := c.GetGuild(context.Background(), fetchedID)
+		done <- err
+	}()
+	<-api.started
+	c.DelGuild(fetchedID)
+	close(api.release)
+	if err := <-done; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := c.cm.GetGuild(fetchedID); ok {
+		t.Fatalf("Expected the guild deleted during the fetch not to be cached")
+	}
+}
+
+func TestClient_FetchErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&domain.APIError{Status: 404, Code: domain.APIErrorCode_UNKNOWN_GUILD}, "guild " + fetchedID + " not found"},
+		{&domain.APIError{Status: 503}, "fetch guild " + fetchedID},
+	}
+	for _, test := range tests {
+		api := &blockingAPI{started: make(chan struct{}), release: make(chan struct{}), err: test.err}
+		close(api.release)
+		c := &clientImpl{cm: discordcache.NewDiscordCacheManager(), ar: api, guildsFetch: newFetchGroup[*domain.Guild]()}
+
+		_, err := c.GetGuild(context.Background(), fetchedID)
+		if err == nil || !strings.HasPrefix(err.Error(), test.want) || !errors.Is(err, test.err) {
+			t.Fatalf("Expected %q wrapping %v, got %v", test.want, test.err, err)
+		}
+	}
+}
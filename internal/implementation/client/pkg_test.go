@@ -10,9 +10,9 @@ import (
 	"testing"
 	"time"
 
-	"github.com/marouane-souiri/vocalize/internal/discord/cache"
-	"github.com/marouane-souiri/vocalize/internal/websocket"
-	"github.com/marouane-souiri/vocalize/internal/workerpool"
+	cache "github.com/marouane-souiri/vocalize/internal/implementation/discordcache"
+	"github.com/marouane-souiri/vocalize/internal/implementation/websocket"
+	"github.com/marouane-souiri/vocalize/internal/implementation/workerpool"
 )
 
 const testToken = "test_token"
@@ -0,0 +1,67 @@
+package commanderrors
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+type CommandErrorHandlerImpl struct {
+	// where failures are logged, log.Printf outside of tests
+	logf func(format string, v ...any)
+}
+
+func NewCommandErrorHandler() interfaces.CommandErrorHandler {
+	return &CommandErrorHandlerImpl{logf: log.Printf}
+}
+
+func (h *CommandErrorHandlerImpl) Handle(failure *interfaces.CommandFailure) string {
+	var usageErr *domain.UsageError
+	var deniedErr *domain.CommandDeniedError
+	switch {
+	case errors.As(failure.Err, &usageErr):
+		usage := usageErr.Usage
+		if usage == "" && failure.Command != nil {
+			usage = domain.Usage(failure.Invoked, failure.Command.GetArguments())
+		}
+		if usage == "" {
+			return usageErr.Error()
+		}
+		return fmt.Sprintf("%s\nUsage: `%s`", usageErr.Error(), usage)
+
+	case errors.As(failure.Err, &deniedErr):
+		return deniedErr.Reason
+
+	// the bot lost a permission since the requirements were checked, or the command didn't declare it
+	case domain.IsMissingPermissions(failure.Err), domain.IsMissingAccess(failure.Err):
+		h.logf("[CommandErrors] Missing permissions running %s in channel %s: %v", failure.Invoked, failure.ChannelID, failure.Err)
+		return "I don't have the permissions to do that here."
+	}
+
+	ID := newErrorID()
+	h.logf("[CommandErrors] Error %s running %s (user %s, guild %s, channel %s): %v",
+		ID, failure.Invoked, failure.UserID, failure.GuildID, failure.ChannelID, failure.Err)
+
+	var panicErr *domain.PanicError
+	if errors.As(failure.Err, &panicErr) {
+		h.logf("[CommandErrors] Stack of error %s:\n%s", ID, panicErr.Stack)
+	}
+	if errors.Is(failure.Err, context.DeadlineExceeded) {
+		return fmt.Sprintf("This took too long, please try again. (error ID `%s`)", ID)
+	}
+	return fmt.Sprintf("Something went wrong while running this command. (error ID `%s`)", ID)
+}
+
+// newErrorID returns a short random ID, enough to find one failure in the logs.
+func newErrorID() string {
+	b := make([]byte, 4)
+	// crypto/rand never fails on the supported platforms
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,72 @@
+package commanderrors
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+type fakeCommand struct {
+	interfaces.BaseCommand
+}
+
+func (f *fakeCommand) GetArguments() []domain.Argument {
+	return []domain.Argument{{Name: "level", Type: domain.ArgumentType_INT}}
+}
+
+func newTestHandler() (*CommandErrorHandlerImpl, *[]string) {
+	var logs []string
+	return &CommandErrorHandlerImpl{logf: func(format string, v ...any) {
+		logs = append(logs, fmt.Sprintf(format, v...))
+	}}, &logs
+}
+
+func TestHandle_UserErrors(t *testing.T) {
+	handler, logs := newTestHandler()
+
+	tests := map[error]string{
+		&domain.UsageError{Reason: "missing whole number"}:                                "missing whole number\nUsage: `.volume <level>`",
+		fmt.Errorf("wrapped: %w", &domain.CommandDeniedError{Reason: "Slow down!"}):       "Slow down!",
+		&domain.APIError{Status: 403, Code: domain.APIErrorCode_MISSING_PERMISSIONS}:      "I don't have the permissions to do that here.",
+		&domain.UsageError{Reason: "a subcommand is missing", Usage: ".voice <set|list>"}: "a subcommand is missing\nUsage: `.voice <set|list>`",
+	}
+	for err, expected := range tests {
+		content := handler.Handle(&interfaces.CommandFailure{Err: err, Command: &fakeCommand{}, Invoked: ".volume"})
+		if content != expected {
+			t.Fatalf("%v: expected %q, got %q", err, expected, content)
+		}
+	}
+	for _, line := range *logs {
+		if strings.Contains(line, "Error ") {
+			t.Fatalf("Expected user errors not to be logged as failures, got %q", line)
+		}
+	}
+}
+
+func TestHandle_InternalErrors(t *testing.T) {
+	handler, logs := newTestHandler()
+
+	content := handler.Handle(&interfaces.CommandFailure{
+		Err:     fmt.Errorf("query failed: %w", errors.New("password=hunter2")),
+		Invoked: ".play",
+		UserID:  "300000000000000003",
+	})
+	ID := regexp.MustCompile("error ID `([0-9a-f]{8})`").FindStringSubmatch(content)
+	if ID == nil || strings.Contains(content, "hunter2") {
+		t.Fatalf("Expected a sanitized message with an error ID, got %q", content)
+	}
+	if len(*logs) != 1 || !strings.Contains((*logs)[0], ID[1]) || !strings.Contains((*logs)[0], "hunter2") || !strings.Contains((*logs)[0], "300000000000000003") {
+		t.Fatalf("Expected the details to be logged with the ID, got %q", *logs)
+	}
+
+	*logs = nil
+	handler.Handle(&interfaces.CommandFailure{Err: &domain.PanicError{Value: "nil map", Stack: []byte("goroutine 1 [running]")}, Invoked: ".play"})
+	if len(*logs) != 2 || !strings.Contains((*logs)[1], "goroutine 1 [running]") {
+		t.Fatalf("Expected the stack of the panic to be logged, got %q", *logs)
+	}
+}
@@ -0,0 +1,59 @@
+package commandscontext
+
+import "github.com/marouane-souiri/vocalize/internal/domain"
+
+// optionsArgs converts the options of a slash command to the types prefix arguments have,
+// so commands read them the same way, see domain.Args.
+func optionsArgs(data *domain.ApplicationCommandInteractionData) *domain.Args {
+	args := domain.NewArgs()
+	for _, option := range data.LeafOptions() {
+		if value := optionValue(data.Resolved, &option); value != nil {
+			args.Add(option.Name, value)
+		}
+	}
+	return args
+}
+
+func optionValue(resolved *domain.ResolvedData, option *domain.ApplicationCommandInteractionDataOption) any {
+	switch option.Type {
+	case domain.ApplicationCommandOptionType_STRING:
+		return option.StringValue()
+	case domain.ApplicationCommandOptionType_INTEGER:
+		return option.IntValue()
+	case domain.ApplicationCommandOptionType_NUMBER:
+		return option.FloatValue()
+	case domain.ApplicationCommandOptionType_BOOLEAN:
+		return option.BoolValue()
+	}
+
+	ID := option.StringValue()
+	if resolved == nil {
+		return ID
+	}
+	switch option.Type {
+	case domain.ApplicationCommandOptionType_USER, domain.ApplicationCommandOptionType_MENTIONABLE:
+		// members in guilds, Args.User gives their user
+		if member, ok := resolved.Member(ID); ok {
+			return member
+		}
+		if user, ok := resolved.Users[ID]; ok {
+			return &user
+		}
+		if role, ok := resolved.Roles[ID]; ok {
+			return &role
+		}
+	case domain.ApplicationCommandOptionType_CHANNEL:
+		if channel, ok := resolved.Channels[ID]; ok {
+			return &channel
+		}
+	case domain.ApplicationCommandOptionType_ROLE:
+		if role, ok := resolved.Roles[ID]; ok {
+			return &role
+		}
+	case domain.ApplicationCommandOptionType_ATTACHMENT:
+		if attachment, ok := resolved.Attachments[ID]; ok {
+			return &attachment
+		}
+	}
+	return ID
+}
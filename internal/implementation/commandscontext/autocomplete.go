@@ -0,0 +1,54 @@
+package commandscontext
+
+import (
+	"context"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+type AutocompleteContextImpl struct {
+	ctx         context.Context
+	interaction *domain.Interaction
+	member      *domain.Member
+	data        *domain.ApplicationCommandInteractionData
+	focused     *domain.ApplicationCommandInteractionDataOption
+}
+
+func (ac *AutocompleteContextImpl) GetGuildID() string {
+	if ac.interaction.GuildID == nil {
+		return ""
+	}
+	return *ac.interaction.GuildID
+}
+
+func (ac *AutocompleteContextImpl) GetChannelID() string {
+	return ac.interaction.ChannelID
+}
+
+func (ac *AutocompleteContextImpl) GetAuthor() *domain.User {
+	return ac.interaction.GetUser()
+}
+
+func (ac *AutocompleteContextImpl) GetMember() *domain.Member {
+	return ac.member
+}
+
+func (ac *AutocompleteContextImpl) GetInteraction() *domain.Interaction {
+	return ac.interaction
+}
+
+func (ac *AutocompleteContextImpl) GetCommandData() *domain.ApplicationCommandInteractionData {
+	return ac.data
+}
+
+func (ac *AutocompleteContextImpl) GetFocused() *domain.ApplicationCommandInteractionDataOption {
+	return ac.focused
+}
+
+func (ac *AutocompleteContextImpl) GetOption(name string) (*domain.ApplicationCommandInteractionDataOption, bool) {
+	return ac.data.Option(name)
+}
+
+func (ac *AutocompleteContextImpl) Context() context.Context {
+	return ac.ctx
+}
@@ -0,0 +1,37 @@
+package commandscontext
+
+import (
+	"context"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+type ComponentContextImpl struct {
+	*interactionResponses
+	data   *domain.MessageComponentInteractionData
+	params map[string]string
+}
+
+func (cc *ComponentContextImpl) GetComponentData() *domain.MessageComponentInteractionData {
+	return cc.data
+}
+
+func (cc *ComponentContextImpl) GetParam(name string) string {
+	return cc.params[name]
+}
+
+func (cc *ComponentContextImpl) GetValues() []string {
+	return cc.data.Values
+}
+
+func (cc *ComponentContextImpl) Update(ctx context.Context, data *domain.InteractionResponseData) error {
+	return cc.update(ctx, data)
+}
+
+func (cc *ComponentContextImpl) DeferUpdate(ctx context.Context) error {
+	return cc.deferUpdate(ctx)
+}
+
+func (cc *ComponentContextImpl) ShowModal(ctx context.Context, modal *domain.Modal) error {
+	return cc.showModal(ctx, modal)
+}
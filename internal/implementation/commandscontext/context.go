@@ -1,8 +1,22 @@
 package commandscontext
 
+import (
+	"context"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// CommandContextImpl is the context of a command called with the prefix, in a guild or in DMs.
 type CommandContextImpl struct {
-	guildID   string
-	channelID string
+	ctx         context.Context
+	api         interfaces.APIRequester
+	message     *domain.Message
+	guildID     string
+	member      *domain.Member
+	args        *domain.Args
+	prefix      string
+	invokedName string
 }
 
 func (cc *CommandContextImpl) GetGuildID() string {
@@ -10,5 +24,63 @@ func (cc *CommandContextImpl) GetGuildID() string {
 }
 
 func (cc *CommandContextImpl) GetChannelID() string {
-	return cc.channelID
+	return cc.message.ChannelID
+}
+
+func (cc *CommandContextImpl) GetAuthor() *domain.User {
+	return &cc.message.Author
+}
+
+func (cc *CommandContextImpl) GetMember() *domain.Member {
+	return cc.member
+}
+
+func (cc *CommandContextImpl) GetMessage() *domain.Message {
+	return cc.message
+}
+
+func (cc *CommandContextImpl) GetArgs() *domain.Args {
+	return cc.args
+}
+
+func (cc *CommandContextImpl) GetPrefix() string {
+	return cc.prefix
+}
+
+func (cc *CommandContextImpl) GetInvokedName() string {
+	return cc.invokedName
+}
+
+func (cc *CommandContextImpl) Context() context.Context {
+	return cc.ctx
+}
+
+// Reply sends data as a reply to the message that called the command.
+func (cc *CommandContextImpl) Reply(ctx context.Context, data *domain.InteractionResponseData) error {
+	failIfNotExists := false
+	_, err := cc.api.SendMessage(ctx, cc.message.ChannelID, &domain.SendMessage{
+		Content:     data.Content,
+		Embeds:      data.Embeds,
+		Components:  data.Components,
+		Attachments: data.Attachments,
+		Files:       data.Files,
+		// the command message may have been deleted meanwhile, the reply is still sent
+		MessageReference: &domain.MessageReference{
+			MessageID:       cc.message.ID,
+			FailIfNotExists: &failIfNotExists,
+		},
+	})
+	return err
+}
+
+func (cc *CommandContextImpl) ReplyEmbed(ctx context.Context, embed domain.Embed) error {
+	return cc.Reply(ctx, &domain.InteractionResponseData{Embeds: []domain.Embed{embed}})
+}
+
+func (cc *CommandContextImpl) React(ctx context.Context, emoji string) error {
+	return cc.api.CreateReaction(ctx, cc.message.ChannelID, cc.message.ID, emoji)
+}
+
+func (cc *CommandContextImpl) Typing(ctx context.Context) error {
+	return cc.api.TriggerTypingIndicator(ctx, cc.message.ChannelID)
 }
@@ -1,6 +1,9 @@
 package commandscontext
 
 import (
+	"context"
+	"strings"
+
 	"github.com/marouane-souiri/vocalize/internal/domain"
 	"github.com/marouane-souiri/vocalize/internal/interfaces"
 )
@@ -11,9 +14,68 @@ func NewCommandsContextMaker() interfaces.CommandsContextMaker {
 	return &CommandsContextMakerImpl{}
 }
 
-func (ccm *CommandsContextMakerImpl) FromMessageEvent(event domain.MessageCreateEvent) interfaces.CommandContext {
-	return &CommandContextImpl{
-		guildID:   *event.GuildID,
-		channelID: event.ChannelID,
+func (ccm *CommandsContextMakerImpl) FromMessageEvent(ctx context.Context, event domain.MessageCreateEvent, args *domain.Args, prefix, invokedName string, api interfaces.APIRequester) interfaces.CommandContext {
+	cc := &CommandContextImpl{
+		ctx:         ctx,
+		api:         api,
+		message:     &event.Message,
+		args:        args,
+		prefix:      prefix,
+		invokedName: invokedName,
+	}
+	// DMs have neither
+	if event.GuildID != nil {
+		cc.guildID = *event.GuildID
+	}
+	if event.Member != nil {
+		// the member of a message has no user nor IDs, the author is the user
+		member := *event.Member
+		member.ID = event.Author.ID
+		member.GuildID = cc.guildID
+		member.User = &event.Message.Author
+		cc.member = &member
+	}
+	return cc
+}
+
+func (ccm *CommandsContextMakerImpl) FromInteraction(ctx context.Context, interaction *domain.Interaction, data *domain.ApplicationCommandInteractionData, api interfaces.APIRequester, responder interfaces.InteractionResponder) interfaces.InteractionContext {
+	responses := newInteractionResponses(ctx, interaction, api, responder)
+	responses.args = optionsArgs(data)
+	responses.prefix = "/"
+	responses.invokedName = strings.Join(append([]string{data.Name}, data.Subcommands()...), " ")
+	return &InteractionContextImpl{
+		interactionResponses: responses,
+		data:                 data,
+	}
+}
+
+func (ccm *CommandsContextMakerImpl) FromComponentInteraction(ctx context.Context, interaction *domain.Interaction, data *domain.MessageComponentInteractionData, params map[string]string, api interfaces.APIRequester, responder interfaces.InteractionResponder) interfaces.ComponentContext {
+	responses := newInteractionResponses(ctx, interaction, api, responder)
+	responses.invokedName = data.CustomID
+	return &ComponentContextImpl{
+		interactionResponses: responses,
+		data:                 data,
+		params:               params,
+	}
+}
+
+func (ccm *CommandsContextMakerImpl) FromModalInteraction(ctx context.Context, interaction *domain.Interaction, data *domain.ModalSubmitInteractionData, params map[string]string, api interfaces.APIRequester, responder interfaces.InteractionResponder) interfaces.ModalContext {
+	responses := newInteractionResponses(ctx, interaction, api, responder)
+	responses.invokedName = data.CustomID
+	return &ModalContextImpl{
+		interactionResponses: responses,
+		data:                 data,
+		params:               params,
+		values:               data.Values(),
+	}
+}
+
+func (ccm *CommandsContextMakerImpl) FromAutocompleteInteraction(ctx context.Context, interaction *domain.Interaction, data *domain.ApplicationCommandInteractionData, focused *domain.ApplicationCommandInteractionDataOption) interfaces.AutocompleteContext {
+	return &AutocompleteContextImpl{
+		ctx:         ctx,
+		interaction: interaction,
+		member:      interactionMember(interaction),
+		data:        data,
+		focused:     focused,
 	}
 }
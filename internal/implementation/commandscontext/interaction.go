@@ -0,0 +1,24 @@
+package commandscontext
+
+import (
+	"context"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+type InteractionContextImpl struct {
+	*interactionResponses
+	data *domain.ApplicationCommandInteractionData
+}
+
+func (ic *InteractionContextImpl) GetCommandData() *domain.ApplicationCommandInteractionData {
+	return ic.data
+}
+
+func (ic *InteractionContextImpl) GetOption(name string) (*domain.ApplicationCommandInteractionDataOption, bool) {
+	return ic.data.Option(name)
+}
+
+func (ic *InteractionContextImpl) ShowModal(ctx context.Context, modal *domain.Modal) error {
+	return ic.showModal(ctx, modal)
+}
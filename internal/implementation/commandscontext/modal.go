@@ -0,0 +1,38 @@
+package commandscontext
+
+import (
+	"context"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+type ModalContextImpl struct {
+	*interactionResponses
+	data   *domain.ModalSubmitInteractionData
+	params map[string]string
+	values map[string]string
+}
+
+func (mc *ModalContextImpl) GetModalData() *domain.ModalSubmitInteractionData {
+	return mc.data
+}
+
+func (mc *ModalContextImpl) GetParam(name string) string {
+	return mc.params[name]
+}
+
+func (mc *ModalContextImpl) GetValue(customID string) string {
+	return mc.values[customID]
+}
+
+func (mc *ModalContextImpl) GetValues() map[string]string {
+	return mc.values
+}
+
+func (mc *ModalContextImpl) Update(ctx context.Context, data *domain.InteractionResponseData) error {
+	return mc.update(ctx, data)
+}
+
+func (mc *ModalContextImpl) DeferUpdate(ctx context.Context) error {
+	return mc.deferUpdate(ctx)
+}
@@ -0,0 +1,312 @@
+package commandscontext
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+type responseState int
+
+const (
+	responseNone responseState = iota
+	// "thinking...", the reply will fill it
+	responseDeferredReply
+	// acknowledged a component without changing its message yet
+	responseDeferredUpdate
+	responseReplied
+	// the message of the component was updated
+	responseUpdated
+	// a modal was shown, its submit is a new interaction
+	responseModal
+)
+
+var (
+	errNotResponded = errors.New("[Interactions] the interaction needs an initial response first")
+	errModalShown   = errors.New("[Interactions] the interaction was answered with a modal, respond to its submit instead")
+)
+
+// interactionResponses keeps track of what was sent for an interaction,
+// the command, component and modal contexts share it.
+type interactionResponses struct {
+	ctx         context.Context
+	interaction *domain.Interaction
+	member      *domain.Member
+	api         interfaces.APIRequester
+	responder   interfaces.InteractionResponder
+	args        *domain.Args
+	prefix      string
+	invokedName string
+
+	// held while a response is sent, so a reply and the automatic defer can't both be the initial response
+	mu        sync.Mutex
+	state     responseState
+	ephemeral bool
+}
+
+func newInteractionResponses(ctx context.Context, interaction *domain.Interaction, api interfaces.APIRequester, responder interfaces.InteractionResponder) *interactionResponses {
+	return &interactionResponses{
+		ctx:         ctx,
+		interaction: interaction,
+		api:         api,
+		responder:   responder,
+		member:      interactionMember(interaction),
+		args:        domain.NewArgs(),
+	}
+}
+
+// interactionMember returns the member of the interaction with its IDs filled in,
+// discord sends it without them and the cache keys members by both. It's nil in DMs.
+func interactionMember(interaction *domain.Interaction) *domain.Member {
+	if interaction.Member == nil || interaction.GuildID == nil {
+		return nil
+	}
+	member := *interaction.Member
+	member.GuildID = *interaction.GuildID
+	if member.User != nil {
+		member.ID = member.User.ID
+	}
+	return &member
+}
+
+func (ir *interactionResponses) GetGuildID() string {
+	if ir.interaction.GuildID == nil {
+		return ""
+	}
+	return *ir.interaction.GuildID
+}
+
+func (ir *interactionResponses) GetChannelID() string {
+	return ir.interaction.ChannelID
+}
+
+func (ir *interactionResponses) GetInteraction() *domain.Interaction {
+	return ir.interaction
+}
+
+func (ir *interactionResponses) GetAuthor() *domain.User {
+	return ir.interaction.GetUser()
+}
+
+func (ir *interactionResponses) GetMember() *domain.Member {
+	return ir.member
+}
+
+func (ir *interactionResponses) GetMessage() *domain.Message {
+	return ir.interaction.Message
+}
+
+func (ir *interactionResponses) GetArgs() *domain.Args {
+	return ir.args
+}
+
+func (ir *interactionResponses) GetPrefix() string {
+	return ir.prefix
+}
+
+func (ir *interactionResponses) GetInvokedName() string {
+	return ir.invokedName
+}
+
+func (ir *interactionResponses) Context() context.Context {
+	return ir.ctx
+}
+
+func (ir *interactionResponses) SetEphemeral(ephemeral bool) {
+	ir.mu.Lock()
+	ir.ephemeral = ephemeral
+	ir.mu.Unlock()
+}
+
+func (ir *interactionResponses) Reply(ctx context.Context, data *domain.InteractionResponseData) error {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	data = ir.withFlags(data)
+	switch ir.state {
+	case responseModal:
+		return errModalShown
+	case responseNone:
+		if err := ir.respond(ctx, domain.InteractionCallbackType_CHANNEL_MESSAGE_WITH_SOURCE, data); err != nil {
+			return err
+		}
+	case responseDeferredReply:
+		// the deferred message already exists, the reply becomes its content
+		if _, err := ir.editOriginal(ctx, data.ToEditMessage()); err != nil {
+			return err
+		}
+	default:
+		if _, err := ir.api.CreateFollowupMessage(ctx, ir.interaction.ApplicationID, ir.interaction.Token, data); err != nil {
+			return err
+		}
+	}
+	if ir.state != responseUpdated {
+		ir.state = responseReplied
+	}
+	return nil
+}
+
+func (ir *interactionResponses) ReplyEmbed(ctx context.Context, embed domain.Embed) error {
+	return ir.Reply(ctx, &domain.InteractionResponseData{Embeds: []domain.Embed{embed}})
+}
+
+// React reacts to the message of a component, or to the original response otherwise.
+func (ir *interactionResponses) React(ctx context.Context, emoji string) error {
+	message := ir.interaction.Message
+	if message == nil {
+		if !ir.Responded() {
+			return errNotResponded
+		}
+		original, err := ir.api.GetOriginalInteractionResponse(ctx, ir.interaction.ApplicationID, ir.interaction.Token)
+		if err != nil {
+			return err
+		}
+		message = original
+	}
+	return ir.api.CreateReaction(ctx, ir.interaction.ChannelID, message.ID, emoji)
+}
+
+// Typing defers the reply, interactions show "thinking..." instead of typing.
+func (ir *interactionResponses) Typing(ctx context.Context) error {
+	return ir.DeferReply(ctx)
+}
+
+func (ir *interactionResponses) DeferReply(ctx context.Context) error {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if ir.state != responseNone {
+		return nil
+	}
+	// the flags of the deferred response decide if the final message is ephemeral
+	if err := ir.respond(ctx, domain.InteractionCallbackType_DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE, ir.withFlags(&domain.InteractionResponseData{})); err != nil {
+		return err
+	}
+	ir.state = responseDeferredReply
+	return nil
+}
+
+func (ir *interactionResponses) FollowUp(ctx context.Context, data *domain.InteractionResponseData) (*domain.Message, error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	switch ir.state {
+	case responseNone:
+		return nil, errNotResponded
+	case responseModal:
+		return nil, errModalShown
+	}
+	return ir.api.CreateFollowupMessage(ctx, ir.interaction.ApplicationID, ir.interaction.Token, ir.withFlags(data))
+}
+
+func (ir *interactionResponses) EditOriginal(ctx context.Context, message *domain.EditMessage) (*domain.Message, error) {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	switch ir.state {
+	case responseNone:
+		return nil, errNotResponded
+	case responseModal:
+		return nil, errModalShown
+	}
+	edited, err := ir.editOriginal(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	switch ir.state {
+	case responseDeferredReply:
+		ir.state = responseReplied
+	case responseDeferredUpdate:
+		ir.state = responseUpdated
+	}
+	return edited, nil
+}
+
+func (ir *interactionResponses) Responded() bool {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	return ir.state != responseNone
+}
+
+// update edits the message the interaction comes from, for components and modals opened by a component.
+func (ir *interactionResponses) update(ctx context.Context, data *domain.InteractionResponseData) error {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	switch ir.state {
+	case responseModal:
+		return errModalShown
+	case responseNone:
+		if err := ir.respond(ctx, domain.InteractionCallbackType_UPDATE_MESSAGE, data); err != nil {
+			return err
+		}
+	case responseDeferredUpdate, responseUpdated:
+		// the original response is the message of the component
+		if _, err := ir.editOriginal(ctx, data.ToEditMessage()); err != nil {
+			return err
+		}
+	default:
+		// the original response is the reply now, the message is edited directly
+		if ir.interaction.Message == nil {
+			return errors.New("[Interactions] the interaction has no message to update")
+		}
+		if _, err := ir.api.EditMessage(ctx, ir.interaction.ChannelID, ir.interaction.Message.ID, data.ToEditMessage()); err != nil {
+			return err
+		}
+		return nil
+	}
+	ir.state = responseUpdated
+	return nil
+}
+
+func (ir *interactionResponses) deferUpdate(ctx context.Context) error {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if ir.state != responseNone {
+		return nil
+	}
+	if err := ir.respond(ctx, domain.InteractionCallbackType_DEFERRED_UPDATE_MESSAGE, nil); err != nil {
+		return err
+	}
+	ir.state = responseDeferredUpdate
+	return nil
+}
+
+// showModal answers with a form, it can only be the initial response.
+func (ir *interactionResponses) showModal(ctx context.Context, modal *domain.Modal) error {
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+
+	if ir.state != responseNone {
+		return errors.New("[Interactions] a modal can only be the initial response")
+	}
+	if err := ir.respond(ctx, domain.InteractionCallbackType_MODAL, modal.ResponseData()); err != nil {
+		return err
+	}
+	ir.state = responseModal
+	return nil
+}
+
+// Must be called with mu held.
+func (ir *interactionResponses) respond(ctx context.Context, callbackType domain.InteractionCallbackType, data *domain.InteractionResponseData) error {
+	return ir.responder.Respond(ctx, ir.interaction, &domain.InteractionResponse{Type: callbackType, Data: data})
+}
+
+// Must be called with mu held.
+func (ir *interactionResponses) editOriginal(ctx context.Context, message *domain.EditMessage) (*domain.Message, error) {
+	return ir.api.EditOriginalInteractionResponse(ctx, ir.interaction.ApplicationID, ir.interaction.Token, message)
+}
+
+// withFlags returns a copy of data marked ephemeral if the context is.
+// Must be called with mu held.
+func (ir *interactionResponses) withFlags(data *domain.InteractionResponseData) *domain.InteractionResponseData {
+	flagged := *data
+	if ir.ephemeral {
+		flagged.Flags |= domain.MessageFlags_EPHEMERAL
+	}
+	return &flagged
+}
@@ -1,9 +1,39 @@
 package commandsmanager
 
+import (
+	"slices"
+	"strings"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
 type BaseCommandImpl struct {
 	Name        string
 	Aliases     []string
 	Description string
+	// groups the command with others in the help, e.g. "Music"
+	Category string
+	// arguments of the prefix command, in order
+	Arguments []domain.Argument
+	// commands nested under this one, e.g. set and list for voice, see GroupCommandImpl
+	Subcommands []interfaces.BaseCommand
+	// name of the subcommand run when none is given
+	DefaultSubcommand string
+	// slash command definition, leave it nil for prefix only commands.
+	// Name, Description and Type default to the ones of the command.
+	ApplicationCommand *domain.ApplicationCommand
+	// option name -> provider of its suggestions, the options are marked as autocomplete in the definition.
+	// Options of subcommands can be keyed by their path, e.g. "voice set channel", to tell apart options sharing a name.
+	Autocomplete map[string]interfaces.AutocompleteProvider
+	// permissions and flags checked before Run
+	Requirements domain.CommandRequirements
+	// custom checks run after the requirements
+	Guards []interfaces.CommandGuard
+	// cooldown and concurrency limit
+	Limits domain.CommandLimits
+
+	parent interfaces.BaseCommand
 }
 
 func (b *BaseCommandImpl) GetName() string {
@@ -17,3 +47,170 @@ func (b *BaseCommandImpl) GetAliases() []string {
 func (b *BaseCommandImpl) GetDescription() string {
 	return b.Description
 }
+
+func (b *BaseCommandImpl) GetCategory() string {
+	return b.Category
+}
+
+func (b *BaseCommandImpl) GetArguments() []domain.Argument {
+	return b.Arguments
+}
+
+func (b *BaseCommandImpl) GetRequirements() domain.CommandRequirements {
+	return b.Requirements
+}
+
+func (b *BaseCommandImpl) GetGuards() []interfaces.CommandGuard {
+	return b.Guards
+}
+
+func (b *BaseCommandImpl) GetLimits() domain.CommandLimits {
+	return b.Limits
+}
+
+func (b *BaseCommandImpl) GetSubcommands() []interfaces.BaseCommand {
+	return b.Subcommands
+}
+
+func (b *BaseCommandImpl) GetDefaultSubcommand() (interfaces.BaseCommand, bool) {
+	if b.DefaultSubcommand == "" {
+		return nil, false
+	}
+	return findSubcommand(b.Subcommands, b.DefaultSubcommand, false, false)
+}
+
+func (b *BaseCommandImpl) GetParent() interfaces.BaseCommand {
+	return b.parent
+}
+
+func (b *BaseCommandImpl) setParent(parent interfaces.BaseCommand) {
+	b.parent = parent
+}
+
+// linkSubcommands sets the parent of the subcommands of command, down the whole tree.
+// Commands not built on BaseCommandImpl keep returning their own parent.
+func linkSubcommands(command interfaces.BaseCommand) {
+	for _, sub := range command.GetSubcommands() {
+		if child, ok := sub.(interface{ setParent(interfaces.BaseCommand) }); ok {
+			child.setParent(command)
+		}
+		linkSubcommands(sub)
+	}
+}
+
+func (b *BaseCommandImpl) GetApplicationCommand() *domain.ApplicationCommand {
+	if b.ApplicationCommand == nil {
+		return nil
+	}
+	command := *b.ApplicationCommand
+	if command.Type == 0 {
+		command.Type = domain.ApplicationCommandType_CHAT_INPUT
+	}
+	if command.Name == "" {
+		command.Name = b.Name
+	}
+	if command.Description == "" && command.Type == domain.ApplicationCommandType_CHAT_INPUT {
+		command.Description = b.Description
+	}
+	// the definition has the last word, the options come from the subcommands or the arguments otherwise
+	if command.Options == nil && command.Type == domain.ApplicationCommandType_CHAT_INPUT {
+		if len(b.Subcommands) > 0 {
+			command.Options = subcommandOptions(b.Subcommands)
+		} else {
+			command.Options = argumentOptions(b.Arguments)
+		}
+	}
+	command.Options = b.markAutocomplete(command.Options, nil)
+	return &command
+}
+
+func (b *BaseCommandImpl) GetAutocompleteProvider(subcommands []string, option string) (interfaces.AutocompleteProvider, bool) {
+	if provider, ok := b.Autocomplete[strings.Join(append(subcommands[:len(subcommands):len(subcommands)], option), " ")]; ok {
+		return provider, true
+	}
+	// subcommands can declare the providers of their own options
+	if len(subcommands) > 0 {
+		if sub, ok := findSubcommand(b.Subcommands, subcommands[0], false, false); ok {
+			if provider, ok := sub.GetAutocompleteProvider(subcommands[1:], option); ok {
+				return provider, true
+			}
+		}
+	}
+	provider, ok := b.Autocomplete[option]
+	return provider, ok
+}
+
+// markAutocomplete returns a copy of options with Autocomplete set on the ones that have a provider.
+func (b *BaseCommandImpl) markAutocomplete(options []domain.ApplicationCommandOption, subcommands []string) []domain.ApplicationCommandOption {
+	if options == nil {
+		return nil
+	}
+	marked := make([]domain.ApplicationCommandOption, len(options))
+	for i, option := range options {
+		marked[i] = option
+		switch option.Type {
+		case domain.ApplicationCommandOptionType_SUB_COMMAND, domain.ApplicationCommandOptionType_SUB_COMMAND_GROUP:
+			path := append(subcommands[:len(subcommands):len(subcommands)], option.Name)
+			marked[i].Options = b.markAutocomplete(option.Options, path)
+		default:
+			if _, ok := b.GetAutocompleteProvider(subcommands, option.Name); ok {
+				marked[i].Autocomplete = true
+			}
+		}
+	}
+	return marked
+}
+
+// findSubcommand finds a subcommand by name, or by alias too for prefix invocations.
+// fold ignores the case, see CommandsManagerOptions.CaseInsensitive.
+func findSubcommand(subcommands []interfaces.BaseCommand, name string, aliases, fold bool) (interfaces.BaseCommand, bool) {
+	equal := func(a string) bool {
+		return a == name || (fold && strings.EqualFold(a, name))
+	}
+	for _, sub := range subcommands {
+		if equal(sub.GetName()) {
+			return sub, true
+		}
+	}
+	if aliases {
+		for _, sub := range subcommands {
+			if slices.ContainsFunc(sub.GetAliases(), equal) {
+				return sub, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// subcommandOptions maps a command tree onto slash subcommands, nested subcommands become subcommand groups.
+func subcommandOptions(subcommands []interfaces.BaseCommand) []domain.ApplicationCommandOption {
+	options := make([]domain.ApplicationCommandOption, 0, len(subcommands))
+	for _, sub := range subcommands {
+		option := domain.ApplicationCommandOption{
+			Type:        domain.ApplicationCommandOptionType_SUB_COMMAND,
+			Name:        sub.GetName(),
+			Description: sub.GetDescription(),
+		}
+		if nested := sub.GetSubcommands(); len(nested) > 0 {
+			option.Type = domain.ApplicationCommandOptionType_SUB_COMMAND_GROUP
+			option.Options = subcommandOptions(nested)
+		} else if definition := sub.GetApplicationCommand(); definition != nil {
+			option.Options = definition.Options
+		} else {
+			option.Options = argumentOptions(sub.GetArguments())
+		}
+		options = append(options, option)
+	}
+	return options
+}
+
+func argumentOptions(arguments []domain.Argument) []domain.ApplicationCommandOption {
+	if len(arguments) == 0 {
+		return nil
+	}
+	options := make([]domain.ApplicationCommandOption, len(arguments))
+	for i, argument := range arguments {
+		options[i] = argument.ApplicationCommandOption()
+	}
+	return options
+}
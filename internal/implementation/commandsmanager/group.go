@@ -0,0 +1,37 @@
+package commandsmanager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// GroupCommandImpl is a command that only holds subcommands, e.g. voice for ".voice set" and ".voice list".
+// Called without a subcommand it runs DefaultSubcommand, or answers with the list of subcommands.
+type GroupCommandImpl struct {
+	BaseCommandImpl
+}
+
+func NewGroupCommand(name, description string, subcommands ...interfaces.BaseCommand) *GroupCommandImpl {
+	g := &GroupCommandImpl{}
+	g.Name = name
+	g.Description = description
+	g.Subcommands = subcommands
+	return g
+}
+
+func (g *GroupCommandImpl) Run(c interfaces.Client, ctx interfaces.CommandContext) error {
+	if sub, ok := g.GetDefaultSubcommand(); ok {
+		return sub.Run(c, ctx)
+	}
+	names := make([]string, len(g.Subcommands))
+	for i, sub := range g.Subcommands {
+		names[i] = sub.GetName()
+	}
+	return &domain.UsageError{
+		Reason: "a subcommand is missing",
+		Usage:  fmt.Sprintf("%s%s <%s>", ctx.GetPrefix(), ctx.GetInvokedName(), strings.Join(names, "|")),
+	}
+}
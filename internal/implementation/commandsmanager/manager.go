@@ -1,37 +1,54 @@
 package commandsmanager
 
 import (
+	"runtime/debug"
+	"sort"
+	"strings"
 	"sync"
+	"unicode"
 
+	"github.com/marouane-souiri/vocalize/internal/domain"
 	"github.com/marouane-souiri/vocalize/internal/interfaces"
 )
 
 type CommandsManagerImpl struct {
-	commands map[string]interfaces.BaseCommand
-	mu       sync.RWMutex
+	commands        map[string]interfaces.BaseCommand
+	middlewares     []interfaces.CommandMiddleware
+	caseInsensitive bool
+	mu              sync.RWMutex
 }
 
-func NewCommandsManager() interfaces.CommandsManager {
+type CommandsManagerOptions struct {
+	// ".Play" finds play too, slash commands are lowercase anyway
+	CaseInsensitive bool
+}
+
+func NewCommandsManager(options *CommandsManagerOptions) interfaces.CommandsManager {
 	return &CommandsManagerImpl{
-		commands: make(map[string]interfaces.BaseCommand),
+		commands:        make(map[string]interfaces.BaseCommand),
+		caseInsensitive: options.CaseInsensitive,
 	}
 }
 
-func (c *CommandsManagerImpl) AddCommand(command interfaces.BaseCommand) {
-	c.mu.Lock()
-	c.commands[command.GetName()] = command
-	for _, alias := range command.GetAliases() {
-		c.commands[alias] = command
+// key is how a name or an alias is stored and looked up.
+func (c *CommandsManagerImpl) key(nameOrAlias string) string {
+	if c.caseInsensitive {
+		return strings.ToLower(nameOrAlias)
 	}
-	c.mu.Unlock()
+	return nameOrAlias
+}
+
+func (c *CommandsManagerImpl) AddCommand(command interfaces.BaseCommand) {
+	c.AddCommands(command)
 }
 
 func (c *CommandsManagerImpl) AddCommands(commands ...interfaces.BaseCommand) {
 	c.mu.Lock()
 	for _, command := range commands {
-		c.commands[command.GetName()] = command
+		linkSubcommands(command)
+		c.commands[c.key(command.GetName())] = command
 		for _, alias := range command.GetAliases() {
-			c.commands[alias] = command
+			c.commands[c.key(alias)] = command
 		}
 	}
 	c.mu.Unlock()
@@ -39,7 +56,99 @@ func (c *CommandsManagerImpl) AddCommands(commands ...interfaces.BaseCommand) {
 
 func (c *CommandsManagerImpl) GetCommand(NameOrAlias string) (interfaces.BaseCommand, bool) {
 	c.mu.RLock()
-	command, ok := c.commands[NameOrAlias]
+	command, ok := c.commands[c.key(NameOrAlias)]
 	c.mu.RUnlock()
 	return command, ok
 }
+
+func (c *CommandsManagerImpl) ResolveCommand(line string) (interfaces.BaseCommand, []string, string, bool) {
+	name, input := splitFirstWord(line)
+	command, ok := c.GetCommand(name)
+	if !ok {
+		return nil, nil, "", false
+	}
+	path := []string{name}
+
+	for len(command.GetSubcommands()) > 0 {
+		name, rest := splitFirstWord(input)
+		if sub, ok := findSubcommand(command.GetSubcommands(), name, true, c.caseInsensitive); ok {
+			command, input = sub, rest
+			path = append(path, name)
+			continue
+		}
+		// the words left are the arguments of the default subcommand, if there is one
+		sub, ok := command.GetDefaultSubcommand()
+		if !ok {
+			break
+		}
+		command = sub
+	}
+	return command, path, input, true
+}
+
+func (c *CommandsManagerImpl) GetCommandPath(name string, subcommands []string) (interfaces.BaseCommand, bool) {
+	command, ok := c.GetCommand(name)
+	if !ok {
+		return nil, false
+	}
+	for _, name := range subcommands {
+		if command, ok = findSubcommand(command.GetSubcommands(), name, false, false); !ok {
+			return nil, false
+		}
+	}
+	return command, true
+}
+
+func (c *CommandsManagerImpl) Use(middlewares ...interfaces.CommandMiddleware) {
+	c.mu.Lock()
+	c.middlewares = append(c.middlewares, middlewares...)
+	c.mu.Unlock()
+}
+
+func (c *CommandsManagerImpl) RunCommand(client interfaces.Client, command interfaces.BaseCommand, ctx interfaces.CommandContext) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &domain.PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	c.mu.RLock()
+	middlewares := c.middlewares
+	c.mu.RUnlock()
+
+	var run interfaces.CommandRunner = func(client interfaces.Client, command interfaces.BaseCommand, ctx interfaces.CommandContext) error {
+		return command.Run(client, ctx)
+	}
+	// wrapped from the last one, so the first one added runs first
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		run = middlewares[i](run)
+	}
+	return run(client, command, ctx)
+}
+
+// splitFirstWord splits the first word of line from the rest, the rest keeps its spacing for the arguments.
+func splitFirstWord(line string) (word, rest string) {
+	line = strings.TrimLeftFunc(line, unicode.IsSpace)
+	end := strings.IndexFunc(line, unicode.IsSpace)
+	if end == -1 {
+		return line, ""
+	}
+	return line[:end], line[end:]
+}
+
+func (c *CommandsManagerImpl) GetCommands() []interfaces.BaseCommand {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	commands := make([]interfaces.BaseCommand, 0, len(c.commands))
+	for nameOrAlias, command := range c.commands {
+		// aliases point to the same command
+		if nameOrAlias == c.key(command.GetName()) {
+			commands = append(commands, command)
+		}
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].GetName() < commands[j].GetName()
+	})
+	return commands
+}
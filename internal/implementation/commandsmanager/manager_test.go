@@ -0,0 +1,183 @@
+package commandsmanager
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// newTestTree declares ".voice set <level>", ".voice list" (the default, alias "ls")
+// and ".voice preset save <name> [for]".
+func newTestTree() interfaces.CommandsManager {
+	set := newTestCommand("set", "Set the volume", nil).(*testCommand)
+	set.Arguments = []domain.Argument{{Name: "level", Type: domain.ArgumentType_INT}}
+	list := newTestCommand("list", "List the channels", nil).(*testCommand)
+	list.Aliases = []string{"ls"}
+	save := newTestCommand("save", "Save a preset", nil).(*testCommand)
+	save.Arguments = []domain.Argument{
+		{Name: "name", Type: domain.ArgumentType_STRING},
+		{Name: "for", Type: domain.ArgumentType_DURATION, Optional: true},
+	}
+
+	voice := NewGroupCommand("voice", "Voice settings", set, list, NewGroupCommand("preset", "Presets", save))
+	voice.Aliases = []string{"v"}
+	voice.DefaultSubcommand = "list"
+	voice.ApplicationCommand = &domain.ApplicationCommand{}
+
+	manager := NewCommandsManager(&CommandsManagerOptions{})
+	manager.AddCommand(voice)
+	return manager
+}
+
+func TestResolveCommand_LongestPath(t *testing.T) {
+	manager := newTestTree()
+
+	tests := []struct {
+		line, name, path, input string
+	}{
+		{"voice set 50", "set", "voice set", " 50"},
+		{"v  ls", "list", "v ls", ""},
+		{"voice preset save \"my preset\"", "save", "voice preset save", " \"my preset\""},
+		// the words that aren't subcommands go to the default one
+		{"voice", "list", "voice", ""},
+		{"voice all", "list", "voice", " all"},
+	}
+	for _, test := range tests {
+		command, path, input, ok := manager.ResolveCommand(test.line)
+		if !ok {
+			t.Fatalf("%q: expected a command", test.line)
+		}
+		if command.GetName() != test.name || strings.Join(path, " ") != test.path || input != test.input {
+			t.Fatalf("%q: expected %s at %q with %q, got %s at %q with %q",
+				test.line, test.name, test.path, test.input, command.GetName(), path, input)
+		}
+	}
+
+	if _, _, _, ok := manager.ResolveCommand("unknown set"); ok {
+		t.Fatalf("Expected no command for unknown")
+	}
+	command, ok := manager.GetCommandPath("voice", []string{"preset", "save"})
+	if !ok || command.GetName() != "save" {
+		t.Fatalf("Expected voice preset save, got %v", command)
+	}
+	if preset := command.GetParent(); preset == nil || preset.GetName() != "preset" || preset.GetParent().GetName() != "voice" || preset.GetParent().GetParent() != nil {
+		t.Fatalf("Expected the parents to be linked up to voice, got %v", preset)
+	}
+	if _, ok := manager.GetCommandPath("voice", []string{"ls"}); ok {
+		t.Fatalf("Expected slash paths to ignore aliases")
+	}
+}
+
+func TestGroupCommand_MapsOntoSlashSubcommands(t *testing.T) {
+	manager := newTestTree()
+	voice, _ := manager.GetCommand("voice")
+
+	definition := voice.GetApplicationCommand()
+	if err := validateApplicationCommand(definition); err != nil {
+		t.Fatalf("Expected a valid definition, got %v", err)
+	}
+	if len(definition.Options) != 3 {
+		t.Fatalf("Expected 3 subcommands, got %+v", definition.Options)
+	}
+	set, preset := definition.Options[0], definition.Options[2]
+	if set.Type != domain.ApplicationCommandOptionType_SUB_COMMAND || len(set.Options) != 1 ||
+		set.Options[0].Type != domain.ApplicationCommandOptionType_INTEGER || !set.Options[0].Required {
+		t.Fatalf("Expected set to take a required integer, got %+v", set)
+	}
+	if preset.Type != domain.ApplicationCommandOptionType_SUB_COMMAND_GROUP || preset.Options[0].Name != "save" {
+		t.Fatalf("Expected preset to be a subcommand group, got %+v", preset)
+	}
+	duration := preset.Options[0].Options[1]
+	if duration.Type != domain.ApplicationCommandOptionType_STRING || duration.Required {
+		t.Fatalf("Expected the duration to be an optional text option, got %+v", duration)
+	}
+}
+
+func TestRunCommand_MiddlewaresInOrder(t *testing.T) {
+	manager := NewCommandsManager(&CommandsManagerOptions{})
+	var calls []string
+	middleware := func(name string) interfaces.CommandMiddleware {
+		return func(next interfaces.CommandRunner) interfaces.CommandRunner {
+			return func(c interfaces.Client, command interfaces.BaseCommand, ctx interfaces.CommandContext) error {
+				calls = append(calls, name)
+				return next(c, command, ctx)
+			}
+		}
+	}
+	manager.Use(middleware("first"), middleware("second"))
+	manager.Use(middleware("third"))
+
+	if err := manager.RunCommand(nil, newTestCommand("ping", "Ping", nil), nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(calls, " ") != "first second third" {
+		t.Fatalf("Expected the middlewares in the order they were added, got %v", calls)
+	}
+}
+
+func TestResolveCommand_CaseInsensitive(t *testing.T) {
+	set := newTestCommand("set", "Set the volume", nil)
+	voice := NewGroupCommand("voice", "Voice settings", set)
+	voice.Aliases = []string{"v"}
+
+	manager := NewCommandsManager(&CommandsManagerOptions{CaseInsensitive: true})
+	manager.AddCommand(voice)
+
+	command, path, _, ok := manager.ResolveCommand("V SET 50")
+	if !ok || command != set || strings.Join(path, " ") != "V SET" {
+		t.Fatalf("Expected set whatever the case, got %v at %q", command, path)
+	}
+	if commands := manager.GetCommands(); len(commands) != 1 {
+		t.Fatalf("Expected aliases not to be listed, got %d commands", len(commands))
+	}
+
+	manager = NewCommandsManager(&CommandsManagerOptions{})
+	manager.AddCommand(voice)
+	if _, _, _, ok := manager.ResolveCommand("Voice set"); ok {
+		t.Fatalf("Expected the case to matter by default")
+	}
+}
+
+func TestSuggestCommands(t *testing.T) {
+	manager := NewCommandsManager(&CommandsManagerOptions{})
+	play := newTestCommand("play", "Play a song", nil).(*testCommand)
+	play.Aliases = []string{"p"}
+	pause := newTestCommand("pause", "Pause the song", nil)
+	manager.AddCommands(play, pause, newTestCommand("volume", "Set the volume", nil))
+
+	tests := map[string]string{
+		"paly":    "play",
+		"pasue":   "pause",
+		"volum":   "volume",
+		"volumes": "volume",
+		"skip":    "",
+	}
+	for typed, expected := range tests {
+		if suggestions := strings.Join(manager.SuggestCommands(typed), " "); suggestions != expected {
+			t.Fatalf("%q: expected %q, got %q", typed, expected, suggestions)
+		}
+	}
+}
+
+type panickingCommand struct {
+	testCommand
+}
+
+func (cmd *panickingCommand) Run(c interfaces.Client, ctx interfaces.CommandContext) error {
+	var settings map[string]int
+	settings["volume"] = 1
+	return nil
+}
+
+func TestRunCommand_RecoversPanics(t *testing.T) {
+	manager := NewCommandsManager(&CommandsManagerOptions{})
+
+	err := manager.RunCommand(nil, &panickingCommand{}, nil)
+	var panicErr *domain.PanicError
+	if !errors.As(err, &panicErr) || !strings.Contains(string(panicErr.Stack), "panickingCommand") {
+		t.Fatalf("Expected the panic with its stack, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package commandsmanager
+
+import "sort"
+
+// how many suggestions are given at most
+const maxSuggestions = 3
+
+func (c *CommandsManagerImpl) SuggestCommands(name string) []string {
+	name = c.key(name)
+	// a typo every 3 characters, so short names don't match everything
+	maxDistance := min(max(1, len([]rune(name))/3), 3)
+
+	type suggestion struct {
+		name     string
+		distance int
+	}
+	// command name -> its closest name or alias
+	best := make(map[string]suggestion)
+
+	c.mu.RLock()
+	for nameOrAlias, command := range c.commands {
+		distance := editDistance(name, nameOrAlias)
+		if distance > maxDistance {
+			continue
+		}
+		if current, ok := best[command.GetName()]; !ok || distance < current.distance ||
+			(distance == current.distance && nameOrAlias < current.name) {
+			best[command.GetName()] = suggestion{name: nameOrAlias, distance: distance}
+		}
+	}
+	c.mu.RUnlock()
+
+	suggestions := make([]suggestion, 0, len(best))
+	for _, s := range best {
+		suggestions = append(suggestions, s)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+		return suggestions[i].name < suggestions[j].name
+	})
+
+	names := make([]string, 0, maxSuggestions)
+	for _, s := range suggestions[:min(len(suggestions), maxSuggestions)] {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// editDistance is the number of runes to insert, delete or replace, or of adjacent runes to swap,
+// to turn a into b (the optimal string alignment distance), "paly" is 1 away from "play".
+func editDistance(a, b string) int {
+	if a == b {
+		return 0
+	}
+	ra, rb := []rune(a), []rune(b)
+	// the last three rows of the distance matrix
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,255 @@
+package commandsmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+const (
+	maxChatInputCommands = 100
+	maxCommandOptions    = 25
+	maxOptionChoices     = 25
+	maxDescriptionLength = 100
+)
+
+// https://discord.com/developers/docs/interactions/application-commands#application-command-object-application-command-naming
+var commandNamePattern = regexp.MustCompile(`^[-_\p{L}\p{N}]{1,32}$`)
+
+func (c *CommandsManagerImpl) SyncApplicationCommands(ctx context.Context, api interfaces.APIRequester, applicationID, guildID string) (*domain.ApplicationCommandsSync, error) {
+	var local []*domain.ApplicationCommand
+	chatInputCommands := 0
+	seen := make(map[string]bool)
+	for _, command := range c.GetCommands() {
+		definition := command.GetApplicationCommand()
+		if definition == nil {
+			continue
+		}
+		// definitions are checked here, discord answers an invalid one with a bare 400
+		if err := validateApplicationCommand(definition); err != nil {
+			return nil, fmt.Errorf("[CommandsManager] invalid application command %q: %w", definition.Name, err)
+		}
+		key := applicationCommandKey(definition)
+		if seen[key] {
+			return nil, fmt.Errorf("[CommandsManager] application command %q is declared twice", definition.Name)
+		}
+		seen[key] = true
+		if definition.Type == domain.ApplicationCommandType_CHAT_INPUT {
+			chatInputCommands++
+		}
+		local = append(local, definition)
+	}
+	if chatInputCommands > maxChatInputCommands {
+		return nil, fmt.Errorf("[CommandsManager] discord allows %d slash commands, got %d", maxChatInputCommands, chatInputCommands)
+	}
+
+	remote, err := api.GetApplicationCommands(ctx, applicationID, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("[CommandsManager] failed to get registered application commands: %w", err)
+	}
+	registered := make(map[string]domain.ApplicationCommand, len(remote))
+	for _, command := range remote {
+		registered[applicationCommandKey(&command)] = command
+	}
+
+	global := guildID == ""
+	result := &domain.ApplicationCommandsSync{}
+
+	for _, definition := range local {
+		key := applicationCommandKey(definition)
+		current, ok := registered[key]
+		if !ok {
+			if _, err := api.CreateApplicationCommand(ctx, applicationID, guildID, definition); err != nil {
+				return result, fmt.Errorf("[CommandsManager] failed to create application command %q: %w", definition.Name, err)
+			}
+			result.Created = append(result.Created, definition.Name)
+			continue
+		}
+		delete(registered, key)
+
+		same, err := sameApplicationCommand(definition, &current, global)
+		if err != nil {
+			return result, err
+		}
+		if same {
+			result.Unchanged = append(result.Unchanged, definition.Name)
+			continue
+		}
+		if _, err := api.EditApplicationCommand(ctx, applicationID, guildID, current.ID, definition); err != nil {
+			return result, fmt.Errorf("[CommandsManager] failed to edit application command %q: %w", definition.Name, err)
+		}
+		result.Updated = append(result.Updated, definition.Name)
+	}
+
+	// whatever is left was registered but isn't declared anymore
+	for _, command := range remote {
+		if _, ok := registered[applicationCommandKey(&command)]; !ok {
+			continue
+		}
+		if err := api.DeleteApplicationCommand(ctx, applicationID, guildID, command.ID); err != nil {
+			return result, fmt.Errorf("[CommandsManager] failed to delete application command %q: %w", command.Name, err)
+		}
+		result.Deleted = append(result.Deleted, command.Name)
+	}
+
+	return result, nil
+}
+
+// applicationCommandKey identifies a command, a slash command and a context menu can share a name.
+func applicationCommandKey(command *domain.ApplicationCommand) string {
+	commandType := command.Type
+	if commandType == 0 {
+		commandType = domain.ApplicationCommandType_CHAT_INPUT
+	}
+	return fmt.Sprintf("%d:%s", commandType, command.Name)
+}
+
+// sameApplicationCommand compares a local definition with the registered command.
+// Both are normalized first: discord fills in defaults and sends numbers back as floats.
+func sameApplicationCommand(local, registered *domain.ApplicationCommand, global bool) (bool, error) {
+	a, err := json.Marshal(normalizeApplicationCommand(local, global))
+	if err != nil {
+		return false, fmt.Errorf("[CommandsManager] failed to marshal application command %q: %w", local.Name, err)
+	}
+	b, err := json.Marshal(normalizeApplicationCommand(registered, global))
+	if err != nil {
+		return false, fmt.Errorf("[CommandsManager] failed to marshal application command %q: %w", registered.Name, err)
+	}
+	return bytes.Equal(a, b), nil
+}
+
+func normalizeApplicationCommand(command *domain.ApplicationCommand, global bool) domain.ApplicationCommand {
+	normalized := *command
+	normalized.ID = ""
+	normalized.ApplicationID = ""
+	normalized.GuildID = ""
+	normalized.Version = ""
+	if normalized.Type == 0 {
+		normalized.Type = domain.ApplicationCommandType_CHAT_INPUT
+	}
+	// DMs only apply to global commands, and allowing them is the default
+	if !global || (normalized.DMPermission != nil && *normalized.DMPermission) {
+		normalized.DMPermission = nil
+	}
+	return normalized
+}
+
+func validateApplicationCommand(command *domain.ApplicationCommand) error {
+	if command.Type != domain.ApplicationCommandType_CHAT_INPUT {
+		// context menu names are free text shown in the menu
+		if length := utf8.RuneCountInString(command.Name); length < 1 || length > 32 {
+			return fmt.Errorf("name must be 1 to 32 characters, got %d", length)
+		}
+		// and they have no description nor options
+		if command.Description != "" || len(command.Options) > 0 {
+			return fmt.Errorf("user and message commands can't have a description or options")
+		}
+		return nil
+	}
+
+	if !commandNamePattern.MatchString(command.Name) || strings.ToLower(command.Name) != command.Name {
+		return fmt.Errorf("name must be 1 to 32 lowercase letters, digits, - or _")
+	}
+	if err := validateDescription(command.Description); err != nil {
+		return err
+	}
+	return validateOptions(command.Options, 0)
+}
+
+// depth is 0 for the options of the command, 1 inside a subcommand group or a subcommand, 2 inside a grouped subcommand.
+func validateOptions(options []domain.ApplicationCommandOption, depth int) error {
+	if len(options) > maxCommandOptions {
+		return fmt.Errorf("at most %d options are allowed, got %d", maxCommandOptions, len(options))
+	}
+
+	names := make(map[string]bool, len(options))
+	subcommands, optional := 0, false
+	for _, option := range options {
+		if !commandNamePattern.MatchString(option.Name) || strings.ToLower(option.Name) != option.Name {
+			return fmt.Errorf("option %q: name must be 1 to 32 lowercase letters, digits, - or _", option.Name)
+		}
+		if names[option.Name] {
+			return fmt.Errorf("option %q is declared twice", option.Name)
+		}
+		names[option.Name] = true
+		if err := validateDescription(option.Description); err != nil {
+			return fmt.Errorf("option %q: %w", option.Name, err)
+		}
+
+		switch option.Type {
+		case domain.ApplicationCommandOptionType_SUB_COMMAND_GROUP:
+			if depth != 0 {
+				return fmt.Errorf("option %q: subcommand groups can only be at the top level", option.Name)
+			}
+			for _, sub := range option.Options {
+				if sub.Type != domain.ApplicationCommandOptionType_SUB_COMMAND {
+					return fmt.Errorf("option %q: subcommand groups can only contain subcommands", option.Name)
+				}
+			}
+			subcommands++
+		case domain.ApplicationCommandOptionType_SUB_COMMAND:
+			if depth > 1 {
+				return fmt.Errorf("option %q: subcommands can't be nested deeper than a group", option.Name)
+			}
+			for _, sub := range option.Options {
+				if sub.Type == domain.ApplicationCommandOptionType_SUB_COMMAND || sub.Type == domain.ApplicationCommandOptionType_SUB_COMMAND_GROUP {
+					return fmt.Errorf("option %q: subcommands can't contain subcommands", option.Name)
+				}
+			}
+			subcommands++
+		default:
+			if len(option.Options) > 0 {
+				return fmt.Errorf("option %q: only subcommands and groups can have options", option.Name)
+			}
+			if option.Required && optional {
+				return fmt.Errorf("option %q: required options must come before optional ones", option.Name)
+			}
+			optional = optional || !option.Required
+			if err := validateChoices(option); err != nil {
+				return fmt.Errorf("option %q: %w", option.Name, err)
+			}
+		}
+
+		if err := validateOptions(option.Options, depth+1); err != nil {
+			return fmt.Errorf("option %q: %w", option.Name, err)
+		}
+	}
+
+	if subcommands > 0 && subcommands != len(options) {
+		return fmt.Errorf("subcommands can't be mixed with other options")
+	}
+	return nil
+}
+
+func validateChoices(option domain.ApplicationCommandOption) error {
+	if len(option.Choices) == 0 {
+		return nil
+	}
+	switch option.Type {
+	case domain.ApplicationCommandOptionType_STRING, domain.ApplicationCommandOptionType_INTEGER, domain.ApplicationCommandOptionType_NUMBER:
+	default:
+		return fmt.Errorf("only string, integer and number options can have choices")
+	}
+	if option.Autocomplete {
+		return fmt.Errorf("autocomplete can't be used with choices")
+	}
+	if len(option.Choices) > maxOptionChoices {
+		return fmt.Errorf("at most %d choices are allowed, got %d", maxOptionChoices, len(option.Choices))
+	}
+	return nil
+}
+
+func validateDescription(description string) error {
+	length := utf8.RuneCountInString(description)
+	if length < 1 || length > maxDescriptionLength {
+		return fmt.Errorf("description must be 1 to %d characters, got %d", maxDescriptionLength, length)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package commandsmanager
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// fakeCommandsAPI only implements the application command endpoints.
+type fakeCommandsAPI struct {
+	interfaces.APIRequester
+	registered []domain.ApplicationCommand
+	created    []string
+	edited     []string
+	deleted    []string
+}
+
+func (f *fakeCommandsAPI) GetApplicationCommands(ctx context.Context, applicationID, guildID string) ([]domain.ApplicationCommand, error) {
+	return f.registered, nil
+}
+
+func (f *fakeCommandsAPI) CreateApplicationCommand(ctx context.Context, applicationID, guildID string, command *domain.ApplicationCommand) (*domain.ApplicationCommand, error) {
+	f.created = append(f.created, command.Name)
+	return command, nil
+}
+
+func (f *fakeCommandsAPI) EditApplicationCommand(ctx context.Context, applicationID, guildID, commandID string, command *domain.ApplicationCommand) (*domain.ApplicationCommand, error) {
+	f.edited = append(f.edited, commandID)
+	return command, nil
+}
+
+func (f *fakeCommandsAPI) DeleteApplicationCommand(ctx context.Context, applicationID, guildID, commandID string) error {
+	f.deleted = append(f.deleted, commandID)
+	return nil
+}
+
+type testCommand struct {
+	BaseCommandImpl
+}
+
+func (cmd *testCommand) Run(c interfaces.Client, ctx interfaces.CommandContext) error {
+	return nil
+}
+
+func newTestCommand(name, description string, definition *domain.ApplicationCommand) interfaces.BaseCommand {
+	c := &testCommand{}
+	c.Name = name
+	c.Description = description
+	c.ApplicationCommand = definition
+	return c
+}
+
+// registered decodes commands the way discord sends them back.
+func registered(t *testing.T, raw string) []domain.ApplicationCommand {
+	var commands []domain.ApplicationCommand
+	if err := json.Unmarshal([]byte(raw), &commands); err != nil {
+		t.Fatalf("Failed to decode registered commands: %v", err)
+	}
+	return commands
+}
+
+func TestSyncApplicationCommands_OnlyPushesChanges(t *testing.T) {
+	minValue := float64(1)
+	manager := NewCommandsManager(&CommandsManagerOptions{})
+	manager.AddCommands(
+		newTestCommand("ping", "A ping command", &domain.ApplicationCommand{}),
+		newTestCommand("roll", "Roll a dice", &domain.ApplicationCommand{
+			Options: []domain.ApplicationCommandOption{{
+				Type:        domain.ApplicationCommandOptionType_INTEGER,
+				Name:        "sides",
+				Description: "How many sides",
+				MinValue:    &minValue,
+				Choices: []domain.ApplicationCommandOptionChoice{
+					{Name: "six", Value: 6},
+					{Name: "twenty", Value: 20},
+				},
+			}},
+		}),
+		newTestCommand("echo", "Repeat a message", &domain.ApplicationCommand{}),
+		newTestCommand("help", "Show the commands", &domain.ApplicationCommand{}),
+		// prefix only
+		newTestCommand("debug", "Debug things", nil),
+	)
+
+	api := &fakeCommandsAPI{registered: registered(t, `[
+		{"id":"1","type":1,"application_id":"9","version":"5","name":"ping","description":"A ping command","dm_permission":true,"default_member_permissions":null,"nsfw":false},
+		{"id":"2","type":1,"application_id":"9","version":"5","name":"roll","description":"Roll a dice","dm_permission":true,
+			"options":[{"type":4,"name":"sides","description":"How many sides","min_value":1,"choices":[{"name":"six","value":6},{"name":"twenty","value":20}]}]},
+		{"id":"3","type":1,"application_id":"9","version":"5","name":"echo","description":"Repeats a message","dm_permission":true},
+		{"id":"4","type":1,"application_id":"9","version":"5","name":"old","description":"Not declared anymore","dm_permission":true}
+	]`)}
+
+	sync, err := manager.SyncApplicationCommands(context.Background(), api, "9", "")
+	if err != nil {
+		t.Fatalf("SyncApplicationCommands failed: %v", err)
+	}
+
+	if !slices.Equal(api.created, []string{"help"}) {
+		t.Fatalf("Expected only help to be created, got %v", api.created)
+	}
+	if !slices.Equal(api.edited, []string{"3"}) {
+		t.Fatalf("Expected only echo (3) to be edited, got %v", api.edited)
+	}
+	if !slices.Equal(api.deleted, []string{"4"}) {
+		t.Fatalf("Expected only old (4) to be deleted, got %v", api.deleted)
+	}
+	if !slices.Equal(sync.Unchanged, []string{"ping", "roll"}) {
+		t.Fatalf("Expected ping and roll to be unchanged, got %v", sync.Unchanged)
+	}
+}
+
+func TestSyncApplicationCommands_RejectsInvalidDefinitions(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition *domain.ApplicationCommand
+		want       string
+	}{
+		{"Upper", &domain.ApplicationCommand{}, "lowercase"},
+		{"nodesc", &domain.ApplicationCommand{Description: strings.Repeat("a", 101)}, "description"},
+		{"order", &domain.ApplicationCommand{Options: []domain.ApplicationCommandOption{
+			{Type: domain.ApplicationCommandOptionType_STRING, Name: "a", Description: "a"},
+			{Type: domain.ApplicationCommandOptionType_STRING, Name: "b", Description: "b", Required: true},
+		}}, "required options"},
+		{"mixed", &domain.ApplicationCommand{Options: []domain.ApplicationCommandOption{
+			{Type: domain.ApplicationCommandOptionType_SUB_COMMAND, Name: "a", Description: "a"},
+			{Type: domain.ApplicationCommandOptionType_STRING, Name: "b", Description: "b"},
+		}}, "mixed"},
+	}
+
+	for _, tt := range tests {
+		manager := NewCommandsManager(&CommandsManagerOptions{})
+		manager.AddCommand(newTestCommand(tt.name, "A command", tt.definition))
+
+		api := &fakeCommandsAPI{}
+		_, err := manager.SyncApplicationCommands(context.Background(), api, "9", "")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error about %q, got %v", tt.name, tt.want, err)
+		}
+		if len(api.created) > 0 {
+			t.Errorf("%s: invalid commands shouldn't be sent to discord", tt.name)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package cooldownstore
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+const (
+	// how many of the least recently used entries are checked for expiry on each use
+	sweepPerTake = 4
+	// used when the given bound is 0 or less, which would drop every use as soon as it's recorded
+	DefaultMaxEntries = 100000
+)
+
+// CooldownStoreImpl keeps the recent uses of each key in memory.
+//
+// Entries are kept in least recently used order: each use sweeps a few expired entries from the front,
+// and when there are more than maxEntries the least recently used one is dropped,
+// which resets its cooldown early rather than growing without bound.
+type CooldownStoreImpl struct {
+	mu         sync.Mutex
+	maxEntries int
+	// key -> element of lru holding an *entry
+	entries map[string]*list.Element
+	lru     *list.List
+
+	// key -> runs in progress, keys are removed when their last run is over
+	running map[string]int
+}
+
+type entry struct {
+	key string
+	// the uses still in the window, oldest first
+	uses []time.Time
+	// when the newest use leaves the window
+	expiresAt time.Time
+}
+
+// NewCooldownStore keeps maxEntries keys at most, DefaultMaxEntries if it's 0 or less.
+func NewCooldownStore(maxEntries int) interfaces.CooldownStore {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	return &CooldownStoreImpl{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		running:    make(map[string]int),
+	}
+}
+
+func (s *CooldownStoreImpl) Take(key string, cooldown domain.Cooldown, now time.Time) (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	element, ok := s.entries[key]
+	if !ok {
+		element = s.lru.PushBack(&entry{key: key})
+		s.entries[key] = element
+	}
+	e := element.Value.(*entry)
+	s.lru.MoveToBack(element)
+
+	// drop the uses that left the window
+	start := now.Add(-cooldown.Window)
+	kept := 0
+	for kept < len(e.uses) && !e.uses[kept].After(start) {
+		kept++
+	}
+	e.uses = e.uses[kept:]
+
+	if len(e.uses) >= cooldown.Uses {
+		return e.uses[0].Add(cooldown.Window).Sub(now), false
+	}
+	e.uses = append(e.uses, now)
+	e.expiresAt = now.Add(cooldown.Window)
+
+	for s.lru.Len() > s.maxEntries {
+		s.remove(s.lru.Front())
+	}
+	return 0, true
+}
+
+// sweep removes expired entries from the front, it must be called with s.mu held.
+func (s *CooldownStoreImpl) sweep(now time.Time) {
+	for i := 0; i < sweepPerTake; i++ {
+		front := s.lru.Front()
+		if front == nil || front.Value.(*entry).expiresAt.After(now) {
+			return
+		}
+		s.remove(front)
+	}
+}
+
+func (s *CooldownStoreImpl) remove(element *list.Element) {
+	s.lru.Remove(element)
+	delete(s.entries, element.Value.(*entry).key)
+}
+
+func (s *CooldownStoreImpl) Acquire(key string, max int) (func(), bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running[key] >= max {
+		return nil, false
+	}
+	s.running[key]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			if s.running[key]--; s.running[key] <= 0 {
+				delete(s.running, key)
+			}
+			s.mu.Unlock()
+		})
+	}, true
+}
+
+func (s *CooldownStoreImpl) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.Len()
+}
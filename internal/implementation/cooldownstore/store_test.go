@@ -0,0 +1,85 @@
+package cooldownstore
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+func TestTake_SlidingWindow(t *testing.T) {
+	store := NewCooldownStore(10)
+	cooldown := domain.Cooldown{Uses: 2, Window: time.Minute}
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		if _, ok := store.Take("key", cooldown, now.Add(time.Duration(i)*10*time.Second)); !ok {
+			t.Fatalf("Expected use %d to be allowed", i)
+		}
+	}
+	retryAfter, ok := store.Take("key", cooldown, now.Add(30*time.Second))
+	if ok || retryAfter != 30*time.Second {
+		t.Fatalf("Expected to wait 30s for the first use to leave the window, got %s, %v", retryAfter, ok)
+	}
+	if _, ok := store.Take("other", cooldown, now.Add(30*time.Second)); !ok {
+		t.Fatalf("Expected keys not to share uses")
+	}
+	if _, ok := store.Take("key", cooldown, now.Add(time.Minute)); !ok {
+		t.Fatalf("Expected a use once the first one left the window")
+	}
+}
+
+func TestTake_BoundedAndExpired(t *testing.T) {
+	store := NewCooldownStore(3)
+	cooldown := domain.Cooldown{Uses: 1, Window: time.Minute}
+	now := time.Now()
+
+	for i := 0; i < 5; i++ {
+		store.Take(fmt.Sprint(i), cooldown, now)
+	}
+	if store.Len() != 3 {
+		t.Fatalf("Expected 3 entries at most, got %d", store.Len())
+	}
+	// the least recently used ones were dropped, so their cooldown is over
+	if _, ok := store.Take("0", cooldown, now); !ok {
+		t.Fatalf("Expected the dropped entry to be allowed again")
+	}
+
+	// expired entries are swept on the next use
+	store.Take("late", cooldown, now.Add(2*time.Minute))
+	if store.Len() != 1 {
+		t.Fatalf("Expected the expired entries to be swept, got %d", store.Len())
+	}
+}
+
+func TestNewCooldownStore_ClampsMaxEntries(t *testing.T) {
+	cooldown := domain.Cooldown{Uses: 1, Window: time.Minute}
+	for _, maxEntries := range []int{0, -1} {
+		store := NewCooldownStore(maxEntries)
+		store.Take("key", cooldown, time.Now())
+		if _, ok := store.Take("key", cooldown, time.Now()); ok {
+			t.Fatalf("Expected the cooldown to hold with maxEntries %d", maxEntries)
+		}
+	}
+}
+
+func TestAcquire(t *testing.T) {
+	store := NewCooldownStore(10).(*CooldownStoreImpl)
+
+	release, ok := store.Acquire("key", 1)
+	if !ok {
+		t.Fatalf("Expected the first run to start")
+	}
+	if _, ok := store.Acquire("key", 1); ok {
+		t.Fatalf("Expected the second run to be refused")
+	}
+	release()
+	release()
+	if len(store.running) != 0 {
+		t.Fatalf("Expected the key to be removed with its last run, got %v", store.running)
+	}
+	if _, ok := store.Acquire("key", 1); !ok {
+		t.Fatalf("Expected a run once the first one is over")
+	}
+}
@@ -81,6 +81,19 @@ func (c *DiscordCacheManagerImpl) GetChannel(ID string) (*domain.Channel, bool)
 	return channel, ok
 }
 
+func (c *DiscordCacheManagerImpl) GetGuildChannels(guildID string) []*domain.Channel {
+	c.channelsCacheMu.RLock()
+	defer c.channelsCacheMu.RUnlock()
+
+	var channels []*domain.Channel
+	for _, channel := range c.channelsCache {
+		if channel.GuildID == guildID {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
 func (c *DiscordCacheManagerImpl) ChannelsCount() int {
 	return len(c.channelsCache)
 }
@@ -0,0 +1,156 @@
+package guildsettings
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+const (
+	// used when the given bounds are 0 or less, which would cache nothing
+	DefaultCacheSize = 1000
+	DefaultCacheTTL  = 10 * time.Minute
+)
+
+// CachedGuildSettingsRepository keeps the recently used settings of another repository in memory.
+//
+// Reads go to the backend on a miss, or when the settings were cached more than ttl ago,
+// writes go through to the backend before updating the cache.
+// At most maxEntries are cached, the least recently used are dropped past that.
+type CachedGuildSettingsRepository struct {
+	backend    interfaces.GuildSettingsRepository
+	maxEntries int
+	ttl        time.Duration
+
+	// held across the backend calls of a guild, so a slow read never caches settings older than a write
+	locks guildLocks
+	// only held to change the cache, never across a backend call
+	mu sync.Mutex
+	// guild ID -> element of lru holding a *cachedSettings
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type cachedSettings struct {
+	settings *domain.GuildSettings
+	cachedAt time.Time
+}
+
+// NewCachedGuildSettingsRepository caches maxEntries guilds for ttl,
+// DefaultCacheSize and DefaultCacheTTL replace values that are 0 or less.
+func NewCachedGuildSettingsRepository(backend interfaces.GuildSettingsRepository, maxEntries int, ttl time.Duration) interfaces.GuildSettingsRepository {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &CachedGuildSettingsRepository{
+		backend:    backend,
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+func (r *CachedGuildSettingsRepository) Get(ctx context.Context, guildID string) (*domain.GuildSettings, error) {
+	if settings, ok := r.cached(guildID); ok {
+		return settings, nil
+	}
+
+	defer r.locks.lock(guildID)()
+	// read by another caller while this one waited
+	if settings, ok := r.cached(guildID); ok {
+		return settings, nil
+	}
+	settings, err := r.backend.Get(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+	r.cache(settings)
+	return settings.Clone(), nil
+}
+
+func (r *CachedGuildSettingsRepository) Save(ctx context.Context, settings *domain.GuildSettings) error {
+	defer r.locks.lock(settings.GuildID)()
+
+	if err := r.backend.Save(ctx, settings); err != nil {
+		// the backend may or may not have it, read it again next time
+		r.forget(settings.GuildID)
+		return err
+	}
+	r.cache(settings.Clone())
+	return nil
+}
+
+func (r *CachedGuildSettingsRepository) Update(ctx context.Context, guildID string, change func(settings *domain.GuildSettings) error) (*domain.GuildSettings, error) {
+	defer r.locks.lock(guildID)()
+
+	settings, err := r.backend.Update(ctx, guildID, change)
+	if err != nil {
+		r.forget(guildID)
+		return nil, err
+	}
+	r.cache(settings.Clone())
+	return settings, nil
+}
+
+func (r *CachedGuildSettingsRepository) Delete(ctx context.Context, guildID string) error {
+	defer r.locks.lock(guildID)()
+
+	r.forget(guildID)
+	return r.backend.Delete(ctx, guildID)
+}
+
+// cached returns a copy of the cached settings of guildID, if they are fresh.
+func (r *CachedGuildSettingsRepository) cached(guildID string) (*domain.GuildSettings, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	element, ok := r.entries[guildID]
+	if !ok {
+		return nil, false
+	}
+	cached := element.Value.(*cachedSettings)
+	if time.Since(cached.cachedAt) >= r.ttl {
+		return nil, false
+	}
+	r.lru.MoveToBack(element)
+	return cached.settings.Clone(), true
+}
+
+// cache must be called with the guild locked, settings must not be shared with callers.
+func (r *CachedGuildSettingsRepository) cache(settings *domain.GuildSettings) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := &cachedSettings{settings: settings, cachedAt: time.Now()}
+	if element, ok := r.entries[settings.GuildID]; ok {
+		element.Value = entry
+		r.lru.MoveToBack(element)
+		return
+	}
+	r.entries[settings.GuildID] = r.lru.PushBack(entry)
+	for r.lru.Len() > r.maxEntries {
+		r.remove(r.lru.Front().Value.(*cachedSettings).settings.GuildID)
+	}
+}
+
+func (r *CachedGuildSettingsRepository) forget(guildID string) {
+	r.mu.Lock()
+	r.remove(guildID)
+	r.mu.Unlock()
+}
+
+// remove must be called with r.mu held.
+func (r *CachedGuildSettingsRepository) remove(guildID string) {
+	if element, ok := r.entries[guildID]; ok {
+		r.lru.Remove(element)
+		delete(r.entries, guildID)
+	}
+}
@@ -0,0 +1,140 @@
+package guildsettings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// FileGuildSettingsRepository stores the settings of each guild as JSON, in <dir>/<guild ID>.json.
+// Files are replaced atomically, a crash never leaves half written settings.
+// Settings stored with an older schema are migrated when read and saved back.
+type FileGuildSettingsRepository struct {
+	dir string
+	// serializes the changes of a guild, so Update reads and writes without another change in between
+	locks guildLocks
+}
+
+func NewFileGuildSettingsRepository(dir string) (*FileGuildSettingsRepository, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("[GuildSettings] failed to create %s: %w", dir, err)
+	}
+	return &FileGuildSettingsRepository{dir: dir}, nil
+}
+
+func (r *FileGuildSettingsRepository) path(guildID string) (string, error) {
+	// guild IDs become file names, anything else could escape the directory
+	if !domain.IsSnowflake(guildID) {
+		return "", fmt.Errorf("[GuildSettings] invalid guild ID %q", guildID)
+	}
+	return filepath.Join(r.dir, guildID+".json"), nil
+}
+
+func (r *FileGuildSettingsRepository) Get(ctx context.Context, guildID string) (*domain.GuildSettings, error) {
+	defer r.locks.lock(guildID)()
+	return r.read(guildID)
+}
+
+// read must be called with the guild locked, it saves back migrated settings.
+func (r *FileGuildSettingsRepository) read(guildID string) (*domain.GuildSettings, error) {
+	path, err := r.path(guildID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return domain.NewGuildSettings(guildID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[GuildSettings] failed to read %s: %w", path, err)
+	}
+
+	settings, migrated, err := decodeSettings(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w, in %s", err, path)
+	}
+	settings.GuildID = guildID
+	if migrated {
+		if err := r.write(settings); err != nil {
+			// the migrated settings are still good to use, the migration runs again next time
+			log.Printf("[GuildSettings] Error saving migrated settings of guild %s: %v", guildID, err)
+		}
+	}
+	return settings, nil
+}
+
+func (r *FileGuildSettingsRepository) Save(ctx context.Context, settings *domain.GuildSettings) error {
+	defer r.locks.lock(settings.GuildID)()
+	return r.write(settings)
+}
+
+// write must be called with the guild locked.
+func (r *FileGuildSettingsRepository) write(settings *domain.GuildSettings) error {
+	path, err := r.path(settings.GuildID)
+	if err != nil {
+		return err
+	}
+	stored := settings.Clone()
+	stored.Version = domain.GuildSettingsVersion
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[GuildSettings] failed to encode settings of guild %s: %w", settings.GuildID, err)
+	}
+
+	// written next to the file then renamed over it, renames are atomic
+	tmp, err := os.CreateTemp(r.dir, settings.GuildID+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("[GuildSettings] failed to create a temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("[GuildSettings] failed to write %s: %w", tmp.Name(), err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("[GuildSettings] failed to sync %s: %w", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("[GuildSettings] failed to close %s: %w", tmp.Name(), err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("[GuildSettings] failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+func (r *FileGuildSettingsRepository) Update(ctx context.Context, guildID string, change func(settings *domain.GuildSettings) error) (*domain.GuildSettings, error) {
+	defer r.locks.lock(guildID)()
+
+	settings, err := r.read(guildID)
+	if err != nil {
+		return nil, err
+	}
+	if err := change(settings); err != nil {
+		return nil, err
+	}
+	if err := r.write(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (r *FileGuildSettingsRepository) Delete(ctx context.Context, guildID string) error {
+	defer r.locks.lock(guildID)()
+
+	path, err := r.path(guildID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("[GuildSettings] failed to delete %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package guildsettings
+
+import "sync"
+
+// guildLocks serializes the operations on the settings of each guild,
+// different guilds never wait on each other. The zero value is ready to use.
+type guildLocks struct {
+	mu sync.Mutex
+	// guild ID -> lock, removed once nobody holds or waits for it
+	locks map[string]*guildLock
+}
+
+type guildLock struct {
+	mu    sync.Mutex
+	users int
+}
+
+// lock locks the settings of guildID, unlock must be called once done with them.
+func (l *guildLocks) lock(guildID string) (unlock func()) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*guildLock)
+	}
+	g, ok := l.locks[guildID]
+	if !ok {
+		g = &guildLock{}
+		l.locks[guildID] = g
+	}
+	g.users++
+	l.mu.Unlock()
+
+	g.mu.Lock()
+	return func() {
+		g.mu.Unlock()
+		l.mu.Lock()
+		if g.users--; g.users == 0 {
+			delete(l.locks, guildID)
+		}
+		l.mu.Unlock()
+	}
+}
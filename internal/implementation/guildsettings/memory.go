@@ -0,0 +1,59 @@
+package guildsettings
+
+import (
+	"context"
+	"sync"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// MemoryGuildSettingsRepository keeps the settings until the bot stops.
+type MemoryGuildSettingsRepository struct {
+	settings map[string]*domain.GuildSettings
+	mu       sync.RWMutex
+}
+
+func NewMemoryGuildSettingsRepository() interfaces.GuildSettingsRepository {
+	return &MemoryGuildSettingsRepository{
+		settings: make(map[string]*domain.GuildSettings),
+	}
+}
+
+func (r *MemoryGuildSettingsRepository) Get(ctx context.Context, guildID string) (*domain.GuildSettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if settings, ok := r.settings[guildID]; ok {
+		return settings.Clone(), nil
+	}
+	return domain.NewGuildSettings(guildID), nil
+}
+
+func (r *MemoryGuildSettingsRepository) Save(ctx context.Context, settings *domain.GuildSettings) error {
+	r.mu.Lock()
+	r.settings[settings.GuildID] = settings.Clone()
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *MemoryGuildSettingsRepository) Update(ctx context.Context, guildID string, change func(settings *domain.GuildSettings) error) (*domain.GuildSettings, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	settings := domain.NewGuildSettings(guildID)
+	if stored, ok := r.settings[guildID]; ok {
+		settings = stored.Clone()
+	}
+	if err := change(settings); err != nil {
+		return nil, err
+	}
+	r.settings[guildID] = settings.Clone()
+	return settings, nil
+}
+
+func (r *MemoryGuildSettingsRepository) Delete(ctx context.Context, guildID string) error {
+	r.mu.Lock()
+	delete(r.settings, guildID)
+	r.mu.Unlock()
+	return nil
+}
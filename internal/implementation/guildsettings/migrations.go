@@ -0,0 +1,65 @@
+package guildsettings
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// migration upgrades settings decoded as a JSON object by one version, in place.
+type migration func(raw map[string]any) error
+
+// version -> migration to the next one, there must be one for every version before domain.GuildSettingsVersion.
+// Version 1 is the first schema written to disk, nothing needs migrating yet.
+var migrations = map[int]migration{}
+
+// runMigrations upgrades raw from its version to target with table.
+// migrated tells if anything ran, so the settings can be saved back.
+func runMigrations(raw map[string]any, table map[int]migration, target int) (migrated bool, err error) {
+	// every stored file has a version, a missing one is read as the first
+	version := 1
+	if v, ok := raw["version"].(float64); ok {
+		version = int(v)
+	}
+	if version > target {
+		return false, fmt.Errorf("[GuildSettings] settings version %d is newer than the supported %d", version, target)
+	}
+	for ; version < target; version++ {
+		migrate, ok := table[version]
+		if !ok {
+			return false, fmt.Errorf("[GuildSettings] no migration from settings version %d", version)
+		}
+		if err := migrate(raw); err != nil {
+			return false, fmt.Errorf("[GuildSettings] failed to migrate settings from version %d: %w", version, err)
+		}
+		raw["version"] = version + 1
+		migrated = true
+	}
+	return migrated, nil
+}
+
+// decodeSettings reads stored settings, migrating them to the current version first.
+// migrated tells if they were stored with an older version, so they can be saved back.
+func decodeSettings(data []byte) (settings *domain.GuildSettings, migrated bool, err error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, false, fmt.Errorf("[GuildSettings] invalid settings: %w", err)
+	}
+
+	migrated, err = runMigrations(raw, migrations, domain.GuildSettingsVersion)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// back to JSON to decode the current shape into the struct
+	data, err = json.Marshal(raw)
+	if err != nil {
+		return nil, false, fmt.Errorf("[GuildSettings] failed to encode migrated settings: %w", err)
+	}
+	settings = &domain.GuildSettings{}
+	if err := json.Unmarshal(data, settings); err != nil {
+		return nil, false, fmt.Errorf("[GuildSettings] invalid settings: %w", err)
+	}
+	return settings, migrated, nil
+}
@@ -0,0 +1,273 @@
+package guildsettings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+const guildID = "100000000000000001"
+
+func TestFile_SaveGetDelete(t *testing.T) {
+	ctx := context.Background()
+	repository, err := NewFileGuildSettingsRepository(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	settings, err := repository.Get(ctx, guildID)
+	if err != nil || settings.Volume != domain.DefaultVolume || len(settings.Prefixes) != 0 {
+		t.Fatalf("Expected the default settings, got %+v, %v", settings, err)
+	}
+
+	settings.Prefixes = []string{"!"}
+	settings.TTSChannels = map[string]string{"200000000000000002": "300000000000000003"}
+	if err := repository.Save(ctx, settings); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	stored, err := repository.Get(ctx, guildID)
+	if err != nil || stored.Prefixes[0] != "!" || stored.TTSChannels["200000000000000002"] != "300000000000000003" {
+		t.Fatalf("Expected the saved settings, got %+v, %v", stored, err)
+	}
+
+	if err := repository.Delete(ctx, guildID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stored, _ := repository.Get(ctx, guildID); len(stored.Prefixes) != 0 {
+		t.Fatalf("Expected the settings to be deleted, got %+v", stored)
+	}
+	if err := repository.Delete(ctx, guildID); err != nil {
+		t.Fatalf("Expected deleting twice to be fine, got %v", err)
+	}
+
+	if _, err := repository.Get(ctx, "../../etc/passwd"); err == nil {
+		t.Fatalf("Expected guild IDs that aren't snowflakes to be refused")
+	}
+}
+
+func TestFile_Versions(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, guildID+".json")
+	repository, err := NewFileGuildSettingsRepository(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := repository.Save(ctx, domain.NewGuildSettings(guildID)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil || raw["version"] != float64(domain.GuildSettingsVersion) {
+		t.Fatalf("Expected the settings to be saved with the current version, got %s", data)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version": 99}`), 0o644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+	if _, err := repository.Get(ctx, guildID); err == nil {
+		t.Fatalf("Expected settings from a newer version to be refused")
+	}
+}
+
+func TestRunMigrations(t *testing.T) {
+	table := map[int]migration{
+		1: func(raw map[string]any) error {
+			raw["volume"] = float64(domain.DefaultVolume)
+			return nil
+		},
+		2: func(raw map[string]any) error {
+			raw["prefixes"] = []any{raw["prefix"]}
+			delete(raw, "prefix")
+			return nil
+		},
+	}
+
+	raw := map[string]any{"version": float64(1), "prefix": "?"}
+	migrated, err := runMigrations(raw, table, 3)
+	if err != nil || !migrated {
+		t.Fatalf("Expected the settings to be migrated, got %v, %v", migrated, err)
+	}
+	if raw["version"] != 3 || raw["volume"] != float64(domain.DefaultVolume) || raw["prefixes"].([]any)[0] != "?" {
+		t.Fatalf("Expected both migrations to run in order, got %+v", raw)
+	}
+
+	if migrated, err := runMigrations(map[string]any{"version": float64(3)}, table, 3); err != nil || migrated {
+		t.Fatalf("Expected current settings to be left alone, got %v, %v", migrated, err)
+	}
+	if _, err := runMigrations(map[string]any{"version": float64(4)}, table, 3); err == nil {
+		t.Fatalf("Expected settings from a newer version to be refused")
+	}
+	if _, err := runMigrations(map[string]any{"version": float64(1)}, map[int]migration{}, 2); err == nil {
+		t.Fatalf("Expected a missing migration to be reported")
+	}
+}
+
+func TestUpdate_FailedChangeIsNotSaved(t *testing.T) {
+	ctx := context.Background()
+	file, err := NewFileGuildSettingsRepository(t.TempDir())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for name, repository := range map[string]interfaces.GuildSettingsRepository{
+		"memory": NewMemoryGuildSettingsRepository(),
+		"file":   file,
+		"cached": NewCachedGuildSettingsRepository(NewMemoryGuildSettingsRepository(), 10, time.Minute),
+	} {
+		updated, err := repository.Update(ctx, guildID, func(settings *domain.GuildSettings) error {
+			settings.Volume = 50
+			return nil
+		})
+		if err != nil || updated.Volume != 50 {
+			t.Fatalf("%s: expected the update, got %+v, %v", name, updated, err)
+		}
+
+		refused := errors.New("refused")
+		_, err = repository.Update(ctx, guildID, func(settings *domain.GuildSettings) error {
+			settings.Volume = 0
+			return refused
+		})
+		if !errors.Is(err, refused) {
+			t.Fatalf("%s: expected the error of the change, got %v", name, err)
+		}
+		if settings, _ := repository.Get(ctx, guildID); settings.Volume != 50 {
+			t.Fatalf("%s: expected the failed change not to be saved, got %+v", name, settings)
+		}
+	}
+}
+
+// countingRepository counts the reads that reach the backend.
+type countingRepository struct {
+	interfaces.GuildSettingsRepository
+	gets int
+}
+
+func (r *countingRepository) Get(ctx context.Context, guildID string) (*domain.GuildSettings, error) {
+	r.gets++
+	return r.GuildSettingsRepository.Get(ctx, guildID)
+}
+
+func TestCached_ReadThrough(t *testing.T) {
+	ctx := context.Background()
+	backend := &countingRepository{GuildSettingsRepository: NewMemoryGuildSettingsRepository()}
+	repository := NewCachedGuildSettingsRepository(backend, 2, time.Minute).(*CachedGuildSettingsRepository)
+
+	settings, _ := repository.Get(ctx, guildID)
+	settings.Prefixes = []string{"!"}
+	if cached, _ := repository.Get(ctx, guildID); backend.gets != 1 || len(cached.Prefixes) != 0 {
+		t.Fatalf("Expected a cached copy, got %+v after %d reads", cached, backend.gets)
+	}
+
+	if err := repository.Save(ctx, settings); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cached, _ := repository.Get(ctx, guildID); backend.gets != 1 || cached.Prefixes[0] != "!" {
+		t.Fatalf("Expected writes to go through the cache, got %+v after %d reads", cached, backend.gets)
+	}
+
+	// the least recently used guild is dropped past 2
+	repository.Get(ctx, "100000000000000002")
+	repository.Get(ctx, "100000000000000003")
+	if len(repository.entries) != 2 {
+		t.Fatalf("Expected 2 cached guilds, got %d", len(repository.entries))
+	}
+	repository.Get(ctx, guildID)
+	if backend.gets != 4 {
+		t.Fatalf("Expected the dropped guild to be read again, got %d reads", backend.gets)
+	}
+
+	// expired settings are read again
+	repository.ttl = 0
+	repository.Get(ctx, guildID)
+	if backend.gets != 5 {
+		t.Fatalf("Expected expired settings to be read again, got %d reads", backend.gets)
+	}
+
+	if err := repository.Delete(ctx, guildID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	repository.ttl = time.Minute
+	if settings, _ := repository.Get(ctx, guildID); len(settings.Prefixes) != 0 {
+		t.Fatalf("Expected the deleted settings to be gone from the cache, got %+v", settings)
+	}
+}
+
+// blockingRepository blocks the reads of one guild until release is closed.
+type blockingRepository struct {
+	interfaces.GuildSettingsRepository
+	blocked string
+	release chan struct{}
+}
+
+func (r *blockingRepository) Get(ctx context.Context, guildID string) (*domain.GuildSettings, error) {
+	if guildID == r.blocked {
+		<-r.release
+	}
+	return r.GuildSettingsRepository.Get(ctx, guildID)
+}
+
+func TestCached_SlowReadDoesntBlockOtherGuilds(t *testing.T) {
+	ctx := context.Background()
+	backend := &blockingRepository{GuildSettingsRepository: NewMemoryGuildSettingsRepository(), blocked: guildID, release: make(chan struct{})}
+	repository := NewCachedGuildSettingsRepository(backend, 10, time.Minute)
+
+	other := "100000000000000002"
+	repository.Get(ctx, other)
+
+	slow := make(chan struct{})
+	go func() {
+		defer close(slow)
+		repository.Get(ctx, guildID)
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		repository.Get(ctx, other)
+		repository.Get(ctx, "100000000000000003")
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected other guilds to be read while a read of %s is slow", guildID)
+	}
+
+	close(backend.release)
+	<-slow
+}
+
+func TestCached_DefaultsForBoundsNotPositive(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxEntries int
+		ttl        time.Duration
+	}{
+		{"zero", 0, 0},
+		{"negative", -1, -time.Minute},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			backend := &countingRepository{GuildSettingsRepository: NewMemoryGuildSettingsRepository()}
+			repository := NewCachedGuildSettingsRepository(backend, test.maxEntries, test.ttl).(*CachedGuildSettingsRepository)
+
+			if repository.maxEntries != DefaultCacheSize || repository.ttl != DefaultCacheTTL {
+				t.Fatalf("Expected the defaults, got %d entries for %s", repository.maxEntries, repository.ttl)
+			}
+			repository.Get(ctx, guildID)
+			repository.Get(ctx, guildID)
+			if backend.gets != 1 {
+				t.Fatalf("Expected the second read to be cached, got %d reads", backend.gets)
+			}
+		})
+	}
+}
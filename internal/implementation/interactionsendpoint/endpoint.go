@@ -0,0 +1,169 @@
+package interactionsendpoint
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+const (
+	// interactions are small, anything bigger isn't from discord
+	maxBodySize = 1 << 20
+	// discord gives up on the HTTP request after 3 seconds,
+	// the router defers slow commands before that
+	responseDeadline = 3 * time.Second
+	// signed requests older or newer than this are refused, a captured one can't be replayed later
+	maxTimestampSkew = 5 * time.Second
+)
+
+// EndpointImpl receives interactions over HTTP, the "Interactions Endpoint URL" of the application.
+// https://discord.com/developers/docs/interactions/overview#setting-up-an-endpoint
+//
+// Interactions are dispatched to the same router as the gateway ones,
+// their initial response is sent back in the HTTP response body.
+type EndpointImpl struct {
+	publicKey ed25519.PublicKey
+	router    interfaces.InteractionsRouter
+	api       interfaces.APIRequester
+}
+
+type EndpointOptions struct {
+	// hex encoded, shown as "Public Key" on the developer portal
+	PublicKey string
+	Router    interfaces.InteractionsRouter
+	// used for responses that can't be in the HTTP body, like file uploads
+	API interfaces.APIRequester
+}
+
+func NewEndpoint(options *EndpointOptions) (*EndpointImpl, error) {
+	publicKey, err := hex.DecodeString(options.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("[InteractionsEndpoint] invalid public key: %w", err)
+	}
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("[InteractionsEndpoint] public key must be %d bytes, got %d", ed25519.PublicKeySize, len(publicKey))
+	}
+	return &EndpointImpl{
+		publicKey: publicKey,
+		router:    options.Router,
+		api:       options.API,
+	}, nil
+}
+
+func (e *EndpointImpl) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+
+	// discord checks that requests with a bad signature are rejected before accepting the endpoint
+	if !e.verify(r.Header.Get("X-Signature-Ed25519"), r.Header.Get("X-Signature-Timestamp"), body) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	var interaction domain.Interaction
+	if err := json.Unmarshal(body, &interaction); err != nil {
+		log.Printf("[InteractionsEndpoint] Error unmarshaling interaction: %v", err)
+		http.Error(w, "invalid interaction", http.StatusBadRequest)
+		return
+	}
+
+	if interaction.Type == domain.InteractionType_PING {
+		writeResponse(w, &domain.InteractionResponse{Type: domain.InteractionCallbackType_PONG})
+		return
+	}
+
+	responder := newHTTPResponder(e.api)
+	defer close(responder.closed)
+
+	// receives what the handler panicked with, nil when it returned
+	done := make(chan any, 1)
+	go func() {
+		defer func() {
+			// net/http only recovers panics of the handler goroutine, this one would kill the bot
+			r := recover()
+			if r != nil {
+				log.Printf("[InteractionsEndpoint] Panic handling interaction %s: %v\n%s", interaction.ID, r, debug.Stack())
+			}
+			done <- r
+		}()
+		e.router.HandleInteraction(&interaction, responder)
+	}()
+
+	deadline := time.NewTimer(responseDeadline)
+	defer deadline.Stop()
+
+	select {
+	case pending := <-responder.pending:
+		pending.written <- writeResponse(w, pending.response)
+	case r := <-done:
+		if r != nil {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		// the handler returned without responding, discord shows the interaction as failed
+		w.WriteHeader(http.StatusNoContent)
+	case <-deadline.C:
+		log.Printf("[InteractionsEndpoint] Interaction %s wasn't answered in time", interaction.ID)
+		http.Error(w, "no response", http.StatusGatewayTimeout)
+	case <-r.Context().Done():
+	}
+}
+
+// verify checks the signature discord made of timestamp + body, and that timestamp is recent.
+func (e *EndpointImpl) verify(signature, timestamp string, body []byte) bool {
+	if signature == "" || timestamp == "" {
+		return false
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if skew := time.Since(time.Unix(seconds, 0)); skew > maxTimestampSkew || skew < -maxTimestampSkew {
+		return false
+	}
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+
+	message := make([]byte, 0, len(timestamp)+len(body))
+	message = append(message, timestamp...)
+	message = append(message, body...)
+	return ed25519.Verify(e.publicKey, message, sig)
+}
+
+func writeResponse(w http.ResponseWriter, response *domain.InteractionResponse) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(response); err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return fmt.Errorf("[InteractionsEndpoint] failed to marshal response: %w", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("[InteractionsEndpoint] failed to write response: %w", err)
+	}
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
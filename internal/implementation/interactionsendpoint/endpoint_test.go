@@ -0,0 +1,163 @@
+package interactionsendpoint
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// fakeRouter answers every interaction with its name, panics for "panic", and records whether a second response was refused.
+type fakeRouter struct {
+	interfaces.InteractionsRouter
+	secondErr chan error
+}
+
+func (f *fakeRouter) HandleInteraction(interaction *domain.Interaction, responder interfaces.InteractionResponder) {
+	data, _ := interaction.ApplicationCommandData()
+	if data.Name == "panic" {
+		panic("handler exploded")
+	}
+	response := &domain.InteractionResponse{
+		Type: domain.InteractionCallbackType_CHANNEL_MESSAGE_WITH_SOURCE,
+		Data: &domain.InteractionResponseData{Content: "ran " + data.Name},
+	}
+	responder.Respond(context.Background(), interaction, response)
+	f.secondErr <- responder.Respond(context.Background(), interaction, response)
+}
+
+func newTestEndpoint(t *testing.T) (*httptest.Server, ed25519.PrivateKey, *fakeRouter) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate keys: %v", err)
+	}
+
+	router := &fakeRouter{secondErr: make(chan error, 1)}
+	endpoint, err := NewEndpoint(&EndpointOptions{
+		PublicKey: hex.EncodeToString(publicKey),
+		Router:    router,
+	})
+	if err != nil {
+		t.Fatalf("NewEndpoint failed: %v", err)
+	}
+
+	srv := httptest.NewServer(endpoint)
+	t.Cleanup(srv.Close)
+	return srv, privateKey, router
+}
+
+func post(t *testing.T, url string, key ed25519.PrivateKey, body string) *http.Response {
+	return postAt(t, url, key, body, time.Now())
+}
+
+func postAt(t *testing.T, url string, key ed25519.PrivateKey, body string, at time.Time) *http.Response {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	signature := ed25519.Sign(key, []byte(timestamp+body))
+
+	req, err := http.NewRequest("POST", url, bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(signature))
+	req.Header.Set("X-Signature-Timestamp", timestamp)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func decodeResponse(t *testing.T, resp *http.Response) domain.InteractionResponse {
+	var response domain.InteractionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return response
+}
+
+func TestEndpoint_RejectsBadSignatures(t *testing.T) {
+	srv, _, _ := newTestEndpoint(t)
+	_, otherKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	resp := post(t, srv.URL, otherKey, `{"type":1}`)
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 for a foreign signature, got %d", resp.StatusCode)
+	}
+
+	resp, err := http.Post(srv.URL, "application/json", bytes.NewBufferString(`{"type":1}`))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 without signature, got %d", resp.StatusCode)
+	}
+}
+
+func TestEndpoint_RejectsReplayedRequests(t *testing.T) {
+	srv, key, _ := newTestEndpoint(t)
+
+	resp := postAt(t, srv.URL, key, `{"id":"1","type":1,"token":"tok"}`, time.Now().Add(-time.Minute))
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 for an old timestamp, got %d", resp.StatusCode)
+	}
+	resp = postAt(t, srv.URL, key, `{"id":"1","type":1,"token":"tok"}`, time.Now().Add(time.Minute))
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 for a future timestamp, got %d", resp.StatusCode)
+	}
+}
+
+func TestEndpoint_RecoversHandlerPanics(t *testing.T) {
+	srv, key, _ := newTestEndpoint(t)
+
+	resp := post(t, srv.URL, key, `{"id":"1","application_id":"2","type":2,"token":"tok","data":{"id":"3","name":"panic","type":1}}`)
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("Expected 500 when the handler panics, got %d", resp.StatusCode)
+	}
+}
+
+func TestEndpoint_AnswersPing(t *testing.T) {
+	srv, key, _ := newTestEndpoint(t)
+
+	resp := post(t, srv.URL, key, `{"id":"1","type":1,"token":"tok"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	if response := decodeResponse(t, resp); response.Type != domain.InteractionCallbackType_PONG {
+		t.Fatalf("Expected a PONG, got %+v", response)
+	}
+}
+
+func TestEndpoint_ReturnsInitialResponseInBody(t *testing.T) {
+	srv, key, router := newTestEndpoint(t)
+
+	resp := post(t, srv.URL, key, `{"id":"1","application_id":"2","type":2,"token":"tok","data":{"id":"3","name":"ping","type":1}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	response := decodeResponse(t, resp)
+	if response.Type != domain.InteractionCallbackType_CHANNEL_MESSAGE_WITH_SOURCE || response.Data == nil || response.Data.Content != "ran ping" {
+		t.Fatalf("Expected the command response, got %+v", response)
+	}
+
+	select {
+	case err := <-router.secondErr:
+		if err == nil {
+			t.Fatalf("Expected a second initial response to be refused")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Second response never returned")
+	}
+}
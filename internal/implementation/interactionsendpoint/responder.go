@@ -0,0 +1,65 @@
+package interactionsendpoint
+
+import (
+	"context"
+	"errors"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+var errRequestDone = errors.New("[InteractionsEndpoint] the HTTP request is over, the initial response can't be sent anymore")
+
+type pendingResponse struct {
+	response *domain.InteractionResponse
+	written  chan error
+}
+
+// httpResponder hands the initial response to the HTTP handler waiting for it.
+// Respond returns once the response is written, so follow ups can't reach discord before it.
+type httpResponder struct {
+	api     interfaces.APIRequester
+	pending chan *pendingResponse
+	// closed when the HTTP handler returns
+	closed chan struct{}
+}
+
+func newHTTPResponder(api interfaces.APIRequester) *httpResponder {
+	return &httpResponder{
+		api:     api,
+		pending: make(chan *pendingResponse),
+		closed:  make(chan struct{}),
+	}
+}
+
+func (r *httpResponder) Respond(ctx context.Context, interaction *domain.Interaction, response *domain.InteractionResponse) error {
+	if len(response.GetFiles()) == 0 {
+		return r.respond(ctx, response)
+	}
+
+	// the HTTP body is JSON only, uploads are deferred then sent as the edit of the deferred message
+	if response.Type != domain.InteractionCallbackType_CHANNEL_MESSAGE_WITH_SOURCE {
+		return errors.New("[InteractionsEndpoint] only message responses can carry files")
+	}
+	err := r.respond(ctx, &domain.InteractionResponse{
+		Type: domain.InteractionCallbackType_DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE,
+		Data: &domain.InteractionResponseData{Flags: response.Data.Flags},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.api.EditOriginalInteractionResponse(ctx, interaction.ApplicationID, interaction.Token, response.Data.ToEditMessage())
+	return err
+}
+
+func (r *httpResponder) respond(ctx context.Context, response *domain.InteractionResponse) error {
+	pending := &pendingResponse{response: response, written: make(chan error, 1)}
+	select {
+	case r.pending <- pending:
+		return <-pending.written
+	case <-r.closed:
+		return errRequestDone
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
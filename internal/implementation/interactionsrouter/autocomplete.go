@@ -0,0 +1,102 @@
+package interactionsrouter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"unicode/utf8"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+const (
+	maxAutocompleteChoices = 25
+	maxChoiceNameLength    = 100
+)
+
+func (r *InteractionsRouterImpl) handleAutocomplete(interaction *domain.Interaction, responder interfaces.InteractionResponder) {
+	data, err := interaction.ApplicationCommandData()
+	if err != nil {
+		log.Printf("[Interactions] Error decoding interaction %s: %v", interaction.ID, err)
+		return
+	}
+
+	// whatever goes wrong, the user gets an empty list instead of "loading options failed"
+	var choices []domain.ApplicationCommandOptionChoice
+	defer func() {
+		r.respondChoices(interaction, responder, choices)
+	}()
+
+	focused, ok := data.Focused()
+	if !ok {
+		log.Printf("[Interactions] Autocomplete of /%s without a focused option", data.Name)
+		return
+	}
+	cmd, ok := r.commandsManager.GetCommand(data.Name)
+	if !ok {
+		log.Printf("[Interactions] Autocomplete of unknown application command %q", data.Name)
+		return
+	}
+	provider, ok := cmd.GetAutocompleteProvider(data.Subcommands(), focused.Name)
+	if !ok {
+		log.Printf("[Interactions] No autocomplete provider for option %q of /%s", focused.Name, data.Name)
+		return
+	}
+
+	// there is no defer for autocomplete, the choices must be sent within the 3 seconds
+	ctx, cancel := context.WithTimeout(context.Background(), r.autoDeferAfter)
+	defer cancel()
+
+	type result struct {
+		choices []domain.ApplicationCommandOptionChoice
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		var res result
+		defer func() { done <- res }()
+		defer recoverPanic(&res.err)
+		res.choices, res.err = provider(r.client, r.commandsCtxMaker.FromAutocompleteInteraction(ctx, interaction, data, focused))
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			log.Printf("[Interactions] Error autocompleting option %q of /%s: %v", focused.Name, data.Name, res.err)
+			var panicErr *domain.PanicError
+			if errors.As(res.err, &panicErr) {
+				log.Printf("[Interactions] Stack of the autocomplete panic:\n%s", panicErr.Stack)
+			}
+			return
+		}
+		choices = res.choices
+	case <-ctx.Done():
+		log.Printf("[Interactions] Autocomplete of option %q of /%s timed out", focused.Name, data.Name)
+	}
+}
+
+func (r *InteractionsRouterImpl) respondChoices(interaction *domain.Interaction, responder interfaces.InteractionResponder, choices []domain.ApplicationCommandOptionChoice) {
+	if len(choices) > maxAutocompleteChoices {
+		choices = choices[:maxAutocompleteChoices]
+	}
+	sent := make([]domain.ApplicationCommandOptionChoice, len(choices))
+	for i, choice := range choices {
+		sent[i] = choice
+		// a single name too long makes discord reject the whole list
+		if utf8.RuneCountInString(choice.Name) > maxChoiceNameLength {
+			sent[i].Name = string([]rune(choice.Name)[:maxChoiceNameLength-1]) + "…"
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), responseTimeout)
+	defer cancel()
+
+	err := responder.Respond(ctx, interaction, &domain.InteractionResponse{
+		Type: domain.InteractionCallbackType_APPLICATION_COMMAND_AUTOCOMPLETE_RESULT,
+		Data: &domain.InteractionResponseData{Choices: &sent},
+	})
+	if err != nil {
+		log.Printf("[Interactions] Error sending autocomplete choices: %v", err)
+	}
+}
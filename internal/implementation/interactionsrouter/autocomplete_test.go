@@ -0,0 +1,113 @@
+package interactionsrouter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+func newAutocompleteRouter(providers map[string]interfaces.AutocompleteProvider) *InteractionsRouterImpl {
+	router, _ := newTestRouter(func(ctx interfaces.InteractionContext) error { return nil })
+	cmd, _ := router.commandsManager.GetCommand("test")
+	cmd.(*testCommand).Autocomplete = providers
+	return router
+}
+
+func autocompleteInteraction(t *testing.T, data string) *domain.Interaction {
+	interaction := testInteraction(t, data)
+	interaction.Type = domain.InteractionType_APPLICATION_COMMAND_AUTOCOMPLETE
+	return interaction
+}
+
+func TestRouter_AutocompleteAnswersWithChoices(t *testing.T) {
+	var focused, guildID, other string
+	router := newAutocompleteRouter(map[string]interfaces.AutocompleteProvider{
+		"voice set channel": func(c interfaces.Client, ctx interfaces.AutocompleteContext) ([]domain.ApplicationCommandOptionChoice, error) {
+			focused = ctx.GetFocused().StringValue()
+			guildID = ctx.GetGuildID()
+			if option, ok := ctx.GetOption("volume"); ok {
+				other = option.StringValue()
+			}
+			choices := make([]domain.ApplicationCommandOptionChoice, 30)
+			for i := range choices {
+				choices[i] = domain.ApplicationCommandOptionChoice{Name: fmt.Sprintf("channel %d", i), Value: fmt.Sprint(i)}
+			}
+			return choices, nil
+		},
+	})
+	responder := &fakeResponder{}
+
+	router.HandleInteraction(autocompleteInteraction(t, `{"id":"9","name":"test","type":1,
+		"options":[{"name":"voice","type":2,"options":[{"name":"set","type":1,"options":[
+			{"name":"volume","type":4,"value":50},
+			{"name":"channel","type":3,"value":"gen","focused":true}
+		]}]}]}`), responder)
+
+	if focused != "gen" || guildID != "4" || other != "50" {
+		t.Fatalf("Expected the provider to see gen in guild 4 with volume 50, got %q, %q, %q", focused, guildID, other)
+	}
+	if len(responder.responses) != 1 || responder.responses[0].Type != domain.InteractionCallbackType_APPLICATION_COMMAND_AUTOCOMPLETE_RESULT {
+		t.Fatalf("Expected a single autocomplete result, got %+v", responder.responses)
+	}
+	if choices := *responder.responses[0].Data.Choices; len(choices) != 25 {
+		t.Fatalf("Expected the choices to be cut to 25, got %d", len(choices))
+	}
+}
+
+func TestRouter_SlowAutocompleteAnswersEmpty(t *testing.T) {
+	router := newAutocompleteRouter(map[string]interfaces.AutocompleteProvider{
+		"query": func(c interfaces.Client, ctx interfaces.AutocompleteContext) ([]domain.ApplicationCommandOptionChoice, error) {
+			<-ctx.Context().Done()
+			return []domain.ApplicationCommandOptionChoice{{Name: "late", Value: "late"}}, nil
+		},
+	})
+	responder := &fakeResponder{}
+
+	router.HandleInteraction(autocompleteInteraction(t, `{"id":"9","name":"test","type":1,
+		"options":[{"name":"query","type":3,"value":"a","focused":true}]}`), responder)
+
+	if len(responder.responses) != 1 || responder.responses[0].Data.Choices == nil || len(*responder.responses[0].Data.Choices) != 0 {
+		t.Fatalf("Expected an empty autocomplete result, got %+v", responder.responses)
+	}
+}
+
+func TestRouter_PanickingAutocompleteAnswersEmpty(t *testing.T) {
+	router := newAutocompleteRouter(map[string]interfaces.AutocompleteProvider{
+		"query": func(c interfaces.Client, ctx interfaces.AutocompleteContext) ([]domain.ApplicationCommandOptionChoice, error) {
+			panic("provider exploded")
+		},
+	})
+	responder := &fakeResponder{}
+
+	router.HandleInteraction(autocompleteInteraction(t, `{"id":"9","name":"test","type":1,
+		"options":[{"name":"query","type":3,"value":"a","focused":true}]}`), responder)
+
+	if len(responder.responses) != 1 || responder.responses[0].Data.Choices == nil || len(*responder.responses[0].Data.Choices) != 0 {
+		t.Fatalf("Expected an empty autocomplete result, got %+v", responder.responses)
+	}
+}
+
+func TestBaseCommand_MarksAutocompleteOptions(t *testing.T) {
+	router := newAutocompleteRouter(map[string]interfaces.AutocompleteProvider{
+		"query": func(c interfaces.Client, ctx interfaces.AutocompleteContext) ([]domain.ApplicationCommandOptionChoice, error) {
+			return nil, nil
+		},
+	})
+	cmd, _ := router.commandsManager.GetCommand("test")
+	cmd.(*testCommand).ApplicationCommand.Options = []domain.ApplicationCommandOption{
+		{Type: domain.ApplicationCommandOptionType_SUB_COMMAND, Name: "search", Options: []domain.ApplicationCommandOption{
+			{Type: domain.ApplicationCommandOptionType_STRING, Name: "query"},
+			{Type: domain.ApplicationCommandOptionType_STRING, Name: "other"},
+		}},
+	}
+
+	options := cmd.GetApplicationCommand().Options[0].Options
+	if !options[0].Autocomplete || options[1].Autocomplete {
+		t.Fatalf("Expected only query to be marked as autocomplete, got %+v", options)
+	}
+	if cmd.(*testCommand).ApplicationCommand.Options[0].Options[0].Autocomplete {
+		t.Fatalf("Expected the declared definition to be left untouched")
+	}
+}
@@ -0,0 +1,170 @@
+package interactionsrouter
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+type componentRoute struct {
+	segments []string
+	handler  interfaces.ComponentHandler
+}
+
+type watchedMessage struct {
+	channelID string
+	timeout   time.Duration
+	handler   interfaces.ComponentHandler
+	timer     *time.Timer
+}
+
+func (r *InteractionsRouterImpl) HandleComponent(pattern string, handler interfaces.ComponentHandler) {
+	r.mu.Lock()
+	r.componentRoutes = append(r.componentRoutes, componentRoute{
+		segments: strings.Split(pattern, ":"),
+		handler:  handler,
+	})
+	r.mu.Unlock()
+}
+
+func (r *InteractionsRouterImpl) WatchComponents(channelID, messageID string, timeout time.Duration, handler interfaces.ComponentHandler) {
+	watched := &watchedMessage{
+		channelID: channelID,
+		timeout:   timeout,
+		handler:   handler,
+	}
+
+	r.mu.Lock()
+	if previous, ok := r.watchedMessages[messageID]; ok {
+		previous.timer.Stop()
+	}
+	watched.timer = time.AfterFunc(timeout, func() { r.expireComponents(messageID, watched) })
+	r.watchedMessages[messageID] = watched
+	r.mu.Unlock()
+}
+
+// expireComponents disables the components of a watched message nobody used for its timeout.
+func (r *InteractionsRouterImpl) expireComponents(messageID string, watched *watchedMessage) {
+	r.mu.Lock()
+	if r.watchedMessages[messageID] != watched {
+		// watched again in the meantime
+		r.mu.Unlock()
+		return
+	}
+	delete(r.watchedMessages, messageID)
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), responseTimeout)
+	defer cancel()
+
+	// the message is fetched again, its components may have been updated since it was watched
+	message, err := r.client.GetMessage(ctx, watched.channelID, messageID)
+	if err != nil {
+		if !domain.IsNotFound(err) {
+			log.Printf("[Interactions] Error getting message %s to disable its components: %v", messageID, err)
+		}
+		return
+	}
+	if len(message.Components) == 0 {
+		return
+	}
+
+	components := domain.DisableComponents(message.Components)
+	if _, err := r.client.EditMessage(ctx, watched.channelID, messageID, &domain.EditMessage{Components: &components}); err != nil {
+		log.Printf("[Interactions] Error disabling components of message %s: %v", messageID, err)
+	}
+}
+
+func (r *InteractionsRouterImpl) handleComponent(interaction *domain.Interaction, responder interfaces.InteractionResponder) {
+	data, err := interaction.MessageComponentData()
+	if err != nil {
+		log.Printf("[Interactions] Error decoding interaction %s: %v", interaction.ID, err)
+		return
+	}
+
+	handler, params := r.findComponentHandler(interaction, data.CustomID)
+	if handler == nil {
+		// expired, or sent by a previous version of the bot
+		r.respondError(interaction, responder, "This is no longer available.")
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(context.Background(), interactionTokenLifetime)
+	defer cancel()
+	ctx := r.commandsCtxMaker.FromComponentInteraction(runCtx, interaction, data, params, r.client.GetAPIRequester(), responder)
+
+	autoDefer := time.AfterFunc(r.autoDeferAfter, func() {
+		deferCtx, cancel := context.WithTimeout(context.Background(), responseTimeout)
+		defer cancel()
+		if err := ctx.DeferUpdate(deferCtx); err != nil {
+			log.Printf("[Interactions] Error deferring component %q: %v", data.CustomID, err)
+		}
+	})
+	err = func() (err error) {
+		defer recoverPanic(&err)
+		return handler(r.client, ctx)
+	}()
+	autoDefer.Stop()
+
+	if err != nil {
+		r.replyError(ctx, r.errorHandler.Handle(&interfaces.CommandFailure{
+			Err:       err,
+			Invoked:   "component " + data.CustomID,
+			UserID:    ctx.GetAuthor().ID,
+			GuildID:   ctx.GetGuildID(),
+			ChannelID: ctx.GetChannelID(),
+		}))
+		return
+	}
+	if !ctx.Responded() {
+		log.Printf("[Interactions] Component %q handled without responding", data.CustomID)
+	}
+}
+
+// findComponentHandler returns the handler of the watched message first, then of the first matching pattern.
+func (r *InteractionsRouterImpl) findComponentHandler(interaction *domain.Interaction, customID string) (interfaces.ComponentHandler, map[string]string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var handler interfaces.ComponentHandler
+	if interaction.Message != nil {
+		if watched, ok := r.watchedMessages[interaction.Message.ID]; ok {
+			// the timeout counts from the last use
+			watched.timer.Reset(watched.timeout)
+			handler = watched.handler
+		}
+	}
+
+	for _, route := range r.componentRoutes {
+		if params, ok := matchCustomID(route.segments, customID); ok {
+			if handler == nil {
+				handler = route.handler
+			}
+			return handler, params
+		}
+	}
+	return handler, nil
+}
+
+func matchCustomID(segments []string, customID string) (map[string]string, bool) {
+	parts := strings.Split(customID, ":")
+	if len(parts) != len(segments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[segment[1:len(segment)-1]] = parts[i]
+			continue
+		}
+		if segment != parts[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
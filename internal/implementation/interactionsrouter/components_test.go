@@ -0,0 +1,103 @@
+package interactionsrouter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+func componentInteraction(t *testing.T, messageID, customID string) *domain.Interaction {
+	var interaction domain.Interaction
+	raw := `{"id":"1","application_id":"2","type":3,"token":"tok","channel_id":"3","guild_id":"4",
+		"member":{"user":{"id":"5","username":"someone"}},
+		"message":{"id":"` + messageID + `","channel_id":"3"},
+		"data":{"custom_id":"` + customID + `","component_type":2}}`
+	if err := json.Unmarshal([]byte(raw), &interaction); err != nil {
+		t.Fatalf("Failed to decode interaction: %v", err)
+	}
+	return &interaction
+}
+
+func TestComponents_PatternCapturesState(t *testing.T) {
+	router, _ := newTestRouter(nil)
+	var guildID string
+	router.HandleComponent("player:pause:{guild_id}", func(c interfaces.Client, ctx interfaces.ComponentContext) error {
+		t.Fatalf("The pause handler shouldn't run for a skip")
+		return nil
+	})
+	router.HandleComponent("player:skip:{guild_id}", func(c interfaces.Client, ctx interfaces.ComponentContext) error {
+		guildID = ctx.GetParam("guild_id")
+		return ctx.Update(context.Background(), &domain.InteractionResponseData{Content: "skipped"})
+	})
+	responder := &fakeResponder{}
+
+	router.HandleInteraction(componentInteraction(t, "10", domain.CustomID("player", "skip", "42")), responder)
+
+	if guildID != "42" {
+		t.Fatalf("Expected guild_id 42, got %q", guildID)
+	}
+	if len(responder.responses) != 1 || responder.responses[0].Type != domain.InteractionCallbackType_UPDATE_MESSAGE {
+		t.Fatalf("Expected a single update response, got %+v", responder.responses)
+	}
+}
+
+func TestComponents_UnknownCustomIDIsRefused(t *testing.T) {
+	router, _ := newTestRouter(nil)
+	responder := &fakeResponder{}
+
+	router.HandleInteraction(componentInteraction(t, "10", "player:skip"), responder)
+
+	if len(responder.responses) != 1 || responder.responses[0].Data.Flags&domain.MessageFlags_EPHEMERAL == 0 {
+		t.Fatalf("Expected an ephemeral refusal, got %+v", responder.responses)
+	}
+}
+
+func TestComponents_WatchedMessageExpires(t *testing.T) {
+	router, _ := newTestRouter(nil)
+	client := router.client.(*fakeClient)
+	client.messages["10"] = &domain.Message{
+		ID: "10",
+		Components: []domain.Component{domain.NewActionRow(
+			domain.Component{Type: domain.ComponentType_BUTTON, Style: domain.ButtonStyle_PRIMARY, CustomID: "vote:yes", Label: "Yes"},
+			domain.Component{Type: domain.ComponentType_BUTTON, Style: domain.ButtonStyle_LINK, URL: "https://example.com", Label: "Docs"},
+		)},
+	}
+
+	clicks := 0
+	router.WatchComponents("3", "10", 100*time.Millisecond, func(c interfaces.Client, ctx interfaces.ComponentContext) error {
+		clicks++
+		return ctx.DeferUpdate(context.Background())
+	})
+
+	// each use pushes the expiry back
+	for range 3 {
+		time.Sleep(60 * time.Millisecond)
+		router.HandleInteraction(componentInteraction(t, "10", "vote:yes"), &fakeResponder{})
+	}
+	if clicks != 3 {
+		t.Fatalf("Expected 3 clicks to be handled, got %d", clicks)
+	}
+	if client.getEdit("10") != nil {
+		t.Fatalf("Components shouldn't be disabled while in use")
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	edit := client.getEdit("10")
+	if edit == nil || edit.Components == nil {
+		t.Fatalf("Expected the components to be disabled after the timeout")
+	}
+	buttons := (*edit.Components)[0].Components
+	if !buttons[0].Disabled || buttons[1].Disabled {
+		t.Fatalf("Expected only the interactive button to be disabled, got %+v", buttons)
+	}
+
+	responder := &fakeResponder{}
+	router.HandleInteraction(componentInteraction(t, "10", "vote:yes"), responder)
+	if clicks != 3 || len(responder.responses) != 1 || responder.responses[0].Data.Flags&domain.MessageFlags_EPHEMERAL == 0 {
+		t.Fatalf("Expected clicks after expiry to be refused, got %d clicks and %+v", clicks, responder.responses)
+	}
+}
@@ -0,0 +1,82 @@
+package interactionsrouter
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+type modalRoute struct {
+	segments []string
+	handler  interfaces.ModalHandler
+}
+
+func (r *InteractionsRouterImpl) HandleModal(pattern string, handler interfaces.ModalHandler) {
+	r.mu.Lock()
+	r.modalRoutes = append(r.modalRoutes, modalRoute{
+		segments: strings.Split(pattern, ":"),
+		handler:  handler,
+	})
+	r.mu.Unlock()
+}
+
+func (r *InteractionsRouterImpl) handleModalSubmit(interaction *domain.Interaction, responder interfaces.InteractionResponder) {
+	data, err := interaction.ModalSubmitData()
+	if err != nil {
+		log.Printf("[Interactions] Error decoding interaction %s: %v", interaction.ID, err)
+		return
+	}
+
+	handler, params := r.findModalHandler(data.CustomID)
+	if handler == nil {
+		r.respondError(interaction, responder, "This form is no longer available.")
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(context.Background(), interactionTokenLifetime)
+	defer cancel()
+	ctx := r.commandsCtxMaker.FromModalInteraction(runCtx, interaction, data, params, r.client.GetAPIRequester(), responder)
+
+	autoDefer := time.AfterFunc(r.autoDeferAfter, func() {
+		deferCtx, cancel := context.WithTimeout(context.Background(), responseTimeout)
+		defer cancel()
+		if err := ctx.DeferReply(deferCtx); err != nil {
+			log.Printf("[Interactions] Error deferring modal %q: %v", data.CustomID, err)
+		}
+	})
+	err = func() (err error) {
+		defer recoverPanic(&err)
+		return handler(r.client, ctx)
+	}()
+	autoDefer.Stop()
+
+	if err != nil {
+		r.replyError(ctx, r.errorHandler.Handle(&interfaces.CommandFailure{
+			Err:       err,
+			Invoked:   "modal " + data.CustomID,
+			UserID:    ctx.GetAuthor().ID,
+			GuildID:   ctx.GetGuildID(),
+			ChannelID: ctx.GetChannelID(),
+		}))
+		return
+	}
+	if !ctx.Responded() {
+		log.Printf("[Interactions] Modal %q handled without responding", data.CustomID)
+	}
+}
+
+func (r *InteractionsRouterImpl) findModalHandler(customID string) (interfaces.ModalHandler, map[string]string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, route := range r.modalRoutes {
+		if params, ok := matchCustomID(route.segments, customID); ok {
+			return route.handler, params
+		}
+	}
+	return nil, nil
+}
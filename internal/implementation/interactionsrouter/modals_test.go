@@ -0,0 +1,62 @@
+package interactionsrouter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+func TestModals_ShowAndSubmit(t *testing.T) {
+	var replyErr error
+	router, _ := newTestRouter(func(ctx interfaces.InteractionContext) error {
+		modal := domain.NewModal(domain.CustomID("welcome", ctx.GetGuildID()), "Welcome message",
+			domain.NewTextInput("template", "Template", domain.TextInputStyle_PARAGRAPH),
+		)
+		if err := ctx.ShowModal(context.Background(), modal); err != nil {
+			return err
+		}
+		replyErr = ctx.Reply(context.Background(), &domain.InteractionResponseData{Content: "too late"})
+		return nil
+	})
+
+	var guildID, template string
+	router.HandleModal("welcome:{guild_id}", func(c interfaces.Client, ctx interfaces.ModalContext) error {
+		guildID = ctx.GetParam("guild_id")
+		template = ctx.GetValue("template")
+		return ctx.Reply(context.Background(), &domain.InteractionResponseData{Content: "saved"})
+	})
+
+	responder := &fakeResponder{}
+	router.HandleInteraction(testInteraction(t, `{"id":"9","name":"test","type":1}`), responder)
+
+	if len(responder.responses) != 1 || responder.responses[0].Type != domain.InteractionCallbackType_MODAL {
+		t.Fatalf("Expected a modal response, got %+v", responder.responses)
+	}
+	shown := responder.responses[0].Data
+	if shown.CustomID != "welcome:4" || shown.Title != "Welcome message" || shown.Components[0].Type != domain.ComponentType_ACTION_ROW {
+		t.Fatalf("Expected the modal with its inputs in action rows, got %+v", shown)
+	}
+	if replyErr == nil {
+		t.Fatalf("Expected replying after showing a modal to fail")
+	}
+
+	var submit domain.Interaction
+	raw := `{"id":"11","application_id":"2","type":5,"token":"tok2","channel_id":"3","guild_id":"4",
+		"data":{"custom_id":"welcome:4","components":[{"type":1,"components":[{"type":4,"custom_id":"template","value":"Hi {user}!"}]}]}}`
+	if err := json.Unmarshal([]byte(raw), &submit); err != nil {
+		t.Fatalf("Failed to decode interaction: %v", err)
+	}
+
+	responder = &fakeResponder{}
+	router.HandleInteraction(&submit, responder)
+
+	if guildID != "4" || template != "Hi {user}!" {
+		t.Fatalf("Expected guild 4 and the template, got %q and %q", guildID, template)
+	}
+	if len(responder.responses) != 1 || responder.responses[0].Data.Content != "saved" {
+		t.Fatalf("Expected the submit to be answered, got %+v", responder.responses)
+	}
+}
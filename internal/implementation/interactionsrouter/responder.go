@@ -0,0 +1,21 @@
+package interactionsrouter
+
+import (
+	"context"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// GatewayResponderImpl answers interactions received over the gateway with the REST callback endpoint.
+type GatewayResponderImpl struct {
+	api interfaces.APIRequester
+}
+
+func NewGatewayResponder(api interfaces.APIRequester) interfaces.InteractionResponder {
+	return &GatewayResponderImpl{api: api}
+}
+
+func (r *GatewayResponderImpl) Respond(ctx context.Context, interaction *domain.Interaction, response *domain.InteractionResponse) error {
+	return r.api.CreateInteractionResponse(ctx, interaction.ID, interaction.Token, response)
+}
@@ -0,0 +1,191 @@
+package interactionsrouter
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+const (
+	// discord drops interactions not acknowledged within 3 seconds,
+	// slow handlers are deferred a bit before that to account for latency
+	AutoDeferAfter = 2500 * time.Millisecond
+	// bound of the responses sent by the router itself
+	responseTimeout = 5 * time.Second
+	// the token of an interaction can't be used after that, handlers are given this long
+	interactionTokenLifetime = 15 * time.Minute
+)
+
+type InteractionsRouterImpl struct {
+	client           interfaces.Client
+	commandsManager  interfaces.CommandsManager
+	commandsCtxMaker interfaces.CommandsContextMaker
+	argumentsParser  interfaces.ArgumentsParser
+	errorHandler     interfaces.CommandErrorHandler
+	autoDeferAfter   time.Duration
+
+	mu              sync.RWMutex
+	componentRoutes []componentRoute
+	modalRoutes     []modalRoute
+	// message ID -> components watched for expiry
+	watchedMessages map[string]*watchedMessage
+}
+
+func NewInteractionsRouter(c interfaces.Client, commandsManager interfaces.CommandsManager, commandsCtxMaker interfaces.CommandsContextMaker, argumentsParser interfaces.ArgumentsParser, errorHandler interfaces.CommandErrorHandler) interfaces.InteractionsRouter {
+	return &InteractionsRouterImpl{
+		client:           c,
+		commandsManager:  commandsManager,
+		commandsCtxMaker: commandsCtxMaker,
+		argumentsParser:  argumentsParser,
+		errorHandler:     errorHandler,
+		autoDeferAfter:   AutoDeferAfter,
+		watchedMessages:  make(map[string]*watchedMessage),
+	}
+}
+
+func (r *InteractionsRouterImpl) HandleInteraction(interaction *domain.Interaction, responder interfaces.InteractionResponder) {
+	switch interaction.Type {
+	case domain.InteractionType_APPLICATION_COMMAND:
+		r.handleApplicationCommand(interaction, responder)
+	case domain.InteractionType_APPLICATION_COMMAND_AUTOCOMPLETE:
+		r.handleAutocomplete(interaction, responder)
+	case domain.InteractionType_MESSAGE_COMPONENT:
+		r.handleComponent(interaction, responder)
+	case domain.InteractionType_MODAL_SUBMIT:
+		r.handleModalSubmit(interaction, responder)
+	default:
+		log.Printf("[Interactions] Unhandled interaction type %d", interaction.Type)
+	}
+}
+
+func (r *InteractionsRouterImpl) handleApplicationCommand(interaction *domain.Interaction, responder interfaces.InteractionResponder) {
+	data, err := interaction.ApplicationCommandData()
+	if err != nil {
+		log.Printf("[Interactions] Error decoding interaction %s: %v", interaction.ID, err)
+		return
+	}
+
+	// the subcommand that was used, or the command itself
+	cmd, ok := r.commandsManager.GetCommandPath(data.Name, data.Subcommands())
+	if top, _ := r.commandsManager.GetCommand(data.Name); !ok || top.GetApplicationCommand() == nil {
+		// registered on discord but not anymore here, the sync didn't run yet
+		log.Printf("[Interactions] Unknown application command %q", data.Name)
+		r.respondError(interaction, responder, "This command doesn't exist anymore.")
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(context.Background(), interactionTokenLifetime)
+	defer cancel()
+	ctx := r.commandsCtxMaker.FromInteraction(runCtx, interaction, data, r.client.GetAPIRequester(), responder)
+
+	autoDefer := time.AfterFunc(r.autoDeferAfter, func() {
+		deferCtx, cancel := context.WithTimeout(context.Background(), responseTimeout)
+		defer cancel()
+		if err := ctx.DeferReply(deferCtx); err != nil {
+			log.Printf("[Interactions] Error deferring /%s: %v", data.Name, err)
+		}
+	})
+	err = r.convertArgs(ctx, cmd.GetArguments())
+	if err == nil {
+		err = r.commandsManager.RunCommand(r.client, cmd, ctx)
+	}
+	autoDefer.Stop()
+
+	if err != nil {
+		r.replyError(ctx, r.errorHandler.Handle(&interfaces.CommandFailure{
+			Err:       err,
+			Command:   cmd,
+			Invoked:   "/" + ctx.GetInvokedName(),
+			UserID:    ctx.GetAuthor().ID,
+			GuildID:   ctx.GetGuildID(),
+			ChannelID: ctx.GetChannelID(),
+		}))
+		return
+	}
+	if !ctx.Responded() {
+		log.Printf("[Interactions] /%s returned without responding", ctx.GetInvokedName())
+	}
+}
+
+// convertArgs converts the options typed as text that stand for other argument types, like durations,
+// the same way prefix arguments are.
+// Member arguments are user options, they're refused when discord didn't resolve a member for them.
+func (r *InteractionsRouterImpl) convertArgs(ctx interfaces.InteractionContext, arguments []domain.Argument) error {
+	args := ctx.GetArgs()
+	for _, argument := range arguments {
+		if !args.Has(argument.Name) {
+			continue
+		}
+		if argument.Type == domain.ArgumentType_MEMBER && !argument.ConvertedFromText() && args.Member(argument.Name) == nil {
+			return &domain.UsageError{Argument: &argument, Reason: notAMember(ctx, args, argument.Name)}
+		}
+		if !argument.ConvertedFromText() {
+			continue
+		}
+		converted, err := r.argumentsParser.Parse(ctx.Context(), ctx.GetGuildID(), []domain.Argument{argument}, args.String(argument.Name))
+		if err != nil {
+			return err
+		}
+		args.Set(argument.Name, converted.All(argument.Name)...)
+	}
+	return nil
+}
+
+// notAMember tells why a user option isn't a member, like the prefix arguments do.
+func notAMember(ctx interfaces.InteractionContext, args *domain.Args, name string) string {
+	if ctx.GetGuildID() == "" {
+		return "members can only be given in a server"
+	}
+	ID := args.String(name)
+	if user := args.User(name); user != nil {
+		ID = user.ID
+	}
+	return fmt.Sprintf("<@%s> isn't a member of this server", ID)
+}
+
+type replier interface {
+	SetEphemeral(ephemeral bool)
+	Reply(ctx context.Context, data *domain.InteractionResponseData) error
+}
+
+// replyError tells the user a command failed.
+// It's private, unless the command deferred publicly: then it fills the "thinking..." message.
+func (r *InteractionsRouterImpl) replyError(ctx replier, content string) {
+	responseCtx, cancel := context.WithTimeout(context.Background(), responseTimeout)
+	defer cancel()
+
+	ctx.SetEphemeral(true)
+	if err := ctx.Reply(responseCtx, &domain.InteractionResponseData{Content: content}); err != nil {
+		log.Printf("[Interactions] Error sending error response: %v", err)
+	}
+}
+
+func (r *InteractionsRouterImpl) respondError(interaction *domain.Interaction, responder interfaces.InteractionResponder, content string) {
+	ctx, cancel := context.WithTimeout(context.Background(), responseTimeout)
+	defer cancel()
+
+	err := responder.Respond(ctx, interaction, &domain.InteractionResponse{
+		Type: domain.InteractionCallbackType_CHANNEL_MESSAGE_WITH_SOURCE,
+		Data: &domain.InteractionResponseData{
+			Content: content,
+			Flags:   domain.MessageFlags_EPHEMERAL,
+		},
+	})
+	if err != nil {
+		log.Printf("[Interactions] Error sending error response: %v", err)
+	}
+}
+
+// recoverPanic turns a panic of a handler into a *domain.PanicError in err, like RunCommand does for commands.
+// The handlers run on goroutines of their own, a panic there would kill the bot.
+func recoverPanic(err *error) {
+	if r := recover(); r != nil {
+		*err = &domain.PanicError{Value: r, Stack: debug.Stack()}
+	}
+}
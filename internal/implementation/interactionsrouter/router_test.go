@@ -0,0 +1,311 @@
+package interactionsrouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/implementation/argumentsparser"
+	"github.com/marouane-souiri/vocalize/internal/implementation/commanderrors"
+	"github.com/marouane-souiri/vocalize/internal/implementation/commandscontext"
+	"github.com/marouane-souiri/vocalize/internal/implementation/commandsmanager"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+type fakeClient struct {
+	interfaces.Client
+	api interfaces.APIRequester
+
+	mu       sync.Mutex
+	messages map[string]*domain.Message
+	edits    map[string]*domain.EditMessage
+}
+
+func (f *fakeClient) GetAPIRequester() interfaces.APIRequester {
+	return f.api
+}
+
+func (f *fakeClient) GetMessage(ctx context.Context, channelID, messageID string) (*domain.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	message, ok := f.messages[messageID]
+	if !ok {
+		return nil, &domain.APIError{Status: 404, Code: domain.APIErrorCode_UNKNOWN_MESSAGE}
+	}
+	return message, nil
+}
+
+func (f *fakeClient) EditMessage(ctx context.Context, channelID, messageID string, message *domain.EditMessage) (*domain.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.edits[messageID] = message
+	return &domain.Message{}, nil
+}
+
+func (f *fakeClient) getEdit(messageID string) *domain.EditMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.edits[messageID]
+}
+
+// fakeInteractionsAPI records the webhook calls made after the initial response.
+type fakeInteractionsAPI struct {
+	interfaces.APIRequester
+	mu        sync.Mutex
+	edits     []*domain.EditMessage
+	followups []*domain.InteractionResponseData
+}
+
+func (f *fakeInteractionsAPI) EditOriginalInteractionResponse(ctx context.Context, applicationID, token string, message *domain.EditMessage) (*domain.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.edits = append(f.edits, message)
+	return &domain.Message{}, nil
+}
+
+func (f *fakeInteractionsAPI) CreateFollowupMessage(ctx context.Context, applicationID, token string, message *domain.InteractionResponseData) (*domain.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.followups = append(f.followups, message)
+	return &domain.Message{}, nil
+}
+
+type fakeResponder struct {
+	mu        sync.Mutex
+	responses []*domain.InteractionResponse
+}
+
+func (f *fakeResponder) Respond(ctx context.Context, interaction *domain.Interaction, response *domain.InteractionResponse) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses = append(f.responses, response)
+	return nil
+}
+
+type testCommand struct {
+	commandsmanager.BaseCommandImpl
+	run func(ctx interfaces.InteractionContext) error
+}
+
+func (cmd *testCommand) Run(c interfaces.Client, ctx interfaces.CommandContext) error {
+	return cmd.run(ctx.(interfaces.InteractionContext))
+}
+
+func newTestRouter(run func(ctx interfaces.InteractionContext) error) (*InteractionsRouterImpl, *fakeInteractionsAPI) {
+	cmd := &testCommand{run: run}
+	cmd.Name = "test"
+	cmd.Description = "A test command"
+	cmd.ApplicationCommand = &domain.ApplicationCommand{}
+	// "/test set" runs the same
+	set := &testCommand{run: run}
+	set.Name = "set"
+	set.Description = "A test subcommand"
+	cmd.Subcommands = []interfaces.BaseCommand{set}
+
+	manager := commandsmanager.NewCommandsManager(&commandsmanager.CommandsManagerOptions{})
+	manager.AddCommand(cmd)
+
+	api := &fakeInteractionsAPI{}
+	client := &fakeClient{
+		api:      api,
+		messages: make(map[string]*domain.Message),
+		edits:    make(map[string]*domain.EditMessage),
+	}
+	router := NewInteractionsRouter(client, manager, commandscontext.NewCommandsContextMaker(), argumentsparser.NewArgumentsParser(client), commanderrors.NewCommandErrorHandler()).(*InteractionsRouterImpl)
+	router.autoDeferAfter = 30 * time.Millisecond
+	return router, api
+}
+
+func testInteraction(t *testing.T, data string) *domain.Interaction {
+	var interaction domain.Interaction
+	raw := `{"id":"1","application_id":"2","type":2,"token":"tok","channel_id":"3","guild_id":"4",
+		"member":{"user":{"id":"5","username":"someone"}},"data":` + data + `}`
+	if err := json.Unmarshal([]byte(raw), &interaction); err != nil {
+		t.Fatalf("Failed to decode interaction: %v", err)
+	}
+	return &interaction
+}
+
+func TestRouter_FastCommandReplies(t *testing.T) {
+	router, api := newTestRouter(func(ctx interfaces.InteractionContext) error {
+		return ctx.Reply(context.Background(), &domain.InteractionResponseData{Content: "hi"})
+	})
+	responder := &fakeResponder{}
+
+	router.HandleInteraction(testInteraction(t, `{"id":"9","name":"test","type":1}`), responder)
+
+	if len(responder.responses) != 1 || responder.responses[0].Type != domain.InteractionCallbackType_CHANNEL_MESSAGE_WITH_SOURCE {
+		t.Fatalf("Expected a single message response, got %+v", responder.responses)
+	}
+	if responder.responses[0].Data.Content != "hi" {
+		t.Fatalf("Expected content hi, got %q", responder.responses[0].Data.Content)
+	}
+	if len(api.edits) != 0 {
+		t.Fatalf("Expected no edit, got %d", len(api.edits))
+	}
+}
+
+func TestRouter_SlowCommandIsDeferred(t *testing.T) {
+	router, api := newTestRouter(func(ctx interfaces.InteractionContext) error {
+		ctx.SetEphemeral(true)
+		time.Sleep(100 * time.Millisecond)
+		return ctx.Reply(context.Background(), &domain.InteractionResponseData{Content: "done"})
+	})
+	responder := &fakeResponder{}
+
+	router.HandleInteraction(testInteraction(t, `{"id":"9","name":"test","type":1}`), responder)
+
+	if len(responder.responses) != 1 || responder.responses[0].Type != domain.InteractionCallbackType_DEFERRED_CHANNEL_MESSAGE_WITH_SOURCE {
+		t.Fatalf("Expected a single deferred response, got %+v", responder.responses)
+	}
+	if responder.responses[0].Data.Flags&domain.MessageFlags_EPHEMERAL == 0 {
+		t.Fatalf("Expected the defer to be ephemeral")
+	}
+	if len(api.edits) != 1 || *api.edits[0].Content != "done" {
+		t.Fatalf("Expected the reply to fill the deferred response, got %+v", api.edits)
+	}
+}
+
+func TestRouter_FailedCommandRepliesPrivately(t *testing.T) {
+	router, _ := newTestRouter(func(ctx interfaces.InteractionContext) error {
+		return errors.New("boom")
+	})
+	responder := &fakeResponder{}
+
+	router.HandleInteraction(testInteraction(t, `{"id":"9","name":"test","type":1}`), responder)
+
+	if len(responder.responses) != 1 || responder.responses[0].Data.Flags&domain.MessageFlags_EPHEMERAL == 0 {
+		t.Fatalf("Expected an ephemeral error response, got %+v", responder.responses)
+	}
+}
+
+func TestRouter_ResolvesOptions(t *testing.T) {
+	var name string
+	var target *domain.Member
+	router, _ := newTestRouter(func(ctx interfaces.InteractionContext) error {
+		option, ok := ctx.GetOption("user")
+		if !ok {
+			return errors.New("missing user option")
+		}
+		target, _ = ctx.GetCommandData().Resolved.Member(option.StringValue())
+		if option, ok := ctx.GetOption("name"); ok {
+			name = option.StringValue()
+		}
+		return ctx.Reply(context.Background(), &domain.InteractionResponseData{Content: "ok"})
+	})
+
+	router.HandleInteraction(testInteraction(t, `{"id":"9","name":"test","type":1,
+		"options":[{"name":"set","type":1,"options":[
+			{"name":"user","type":6,"value":"7"},
+			{"name":"name","type":3,"value":"bob"}
+		]}],
+		"resolved":{"users":{"7":{"id":"7","username":"target"}},"members":{"7":{"nick":"tgt","roles":[]}}}}`), &fakeResponder{})
+
+	if name != "bob" {
+		t.Fatalf("Expected the subcommand option name to be bob, got %q", name)
+	}
+	if target == nil || target.User == nil || target.User.Username != "target" || target.Nickname != "tgt" {
+		t.Fatalf("Expected the resolved member with its user, got %+v", target)
+	}
+}
+
+func TestRouter_OptionsAreArgs(t *testing.T) {
+	var args *domain.Args
+	var author *domain.User
+	var member *domain.Member
+	var invoked string
+	router, _ := newTestRouter(func(ctx interfaces.InteractionContext) error {
+		args, author, member = ctx.GetArgs(), ctx.GetAuthor(), ctx.GetMember()
+		invoked = ctx.GetPrefix() + ctx.GetInvokedName()
+		if _, ok := ctx.Context().Deadline(); !ok {
+			return errors.New("expected the context to have a deadline")
+		}
+		return ctx.Reply(ctx.Context(), &domain.InteractionResponseData{Content: "ok"})
+	})
+
+	router.HandleInteraction(testInteraction(t, `{"id":"9","name":"test","type":1,
+		"options":[
+			{"name":"user","type":6,"value":"7"},
+			{"name":"volume","type":4,"value":50}
+		],
+		"resolved":{"users":{"7":{"id":"7","username":"target"}},"members":{"7":{"nick":"tgt","roles":[]}}}}`), &fakeResponder{})
+
+	if args == nil || args.Int("volume") != 50 {
+		t.Fatalf("Expected volume 50 in the args, got %+v", args)
+	}
+	if user := args.User("user"); user == nil || user.Username != "target" {
+		t.Fatalf("Expected the resolved user in the args, got %+v", user)
+	}
+	if args.Member("user") == nil {
+		t.Fatalf("Expected the resolved member in the args")
+	}
+	if author == nil || author.ID != "5" || member == nil || member.ID != "5" || member.GuildID != "4" {
+		t.Fatalf("Expected the author and its member with IDs, got %+v, %+v", author, member)
+	}
+	if invoked != "/test" {
+		t.Fatalf("Expected /test to be invoked, got %q", invoked)
+	}
+}
+
+func TestRouter_ConvertsTextOptionsOfSubcommands(t *testing.T) {
+	var duration time.Duration
+	var invoked string
+	router, _ := newTestRouter(func(ctx interfaces.InteractionContext) error {
+		duration, invoked = ctx.GetArgs().Duration("for"), ctx.GetInvokedName()
+		return ctx.Reply(ctx.Context(), &domain.InteractionResponseData{Content: "ok"})
+	})
+	cmd, _ := router.commandsManager.GetCommandPath("test", []string{"set"})
+	cmd.(*testCommand).Arguments = []domain.Argument{{Name: "for", Type: domain.ArgumentType_DURATION}}
+	responder := &fakeResponder{}
+
+	router.HandleInteraction(testInteraction(t, `{"id":"9","name":"test","type":1,
+		"options":[{"name":"set","type":1,"options":[{"name":"for","type":3,"value":"1:30"}]}]}`), responder)
+
+	if duration != 90*time.Second || invoked != "test set" {
+		t.Fatalf("Expected test set to run for 1m30s, got %q for %s", invoked, duration)
+	}
+
+	router.HandleInteraction(testInteraction(t, `{"id":"9","name":"test","type":1,
+		"options":[{"name":"set","type":1,"options":[{"name":"for","type":3,"value":"soon"}]}]}`), responder)
+
+	last := responder.responses[len(responder.responses)-1]
+	if last.Data.Flags&domain.MessageFlags_EPHEMERAL == 0 || !strings.Contains(last.Data.Content, "duration") {
+		t.Fatalf("Expected a private usage error, got %+v", last.Data)
+	}
+}
+
+func TestRouter_MemberOptionWithoutMember(t *testing.T) {
+	ran := false
+	router, _ := newTestRouter(func(ctx interfaces.InteractionContext) error {
+		ran = true
+		return ctx.Reply(ctx.Context(), &domain.InteractionResponseData{Content: "ok"})
+	})
+	cmd, _ := router.commandsManager.GetCommand("test")
+	cmd.(*testCommand).Arguments = []domain.Argument{{Name: "user", Type: domain.ArgumentType_MEMBER}}
+	responder := &fakeResponder{}
+
+	// the user left the server, discord only resolves the user
+	router.HandleInteraction(testInteraction(t, `{"id":"9","name":"test","type":1,
+		"options":[{"name":"user","type":6,"value":"7"}],
+		"resolved":{"users":{"7":{"id":"7","username":"target"}}}}`), responder)
+
+	if ran {
+		t.Fatalf("Expected the command not to run without a member")
+	}
+	last := responder.responses[len(responder.responses)-1]
+	if last.Data.Flags&domain.MessageFlags_EPHEMERAL == 0 || !strings.Contains(last.Data.Content, "<@7> isn't a member of this server") {
+		t.Fatalf("Expected a private usage error, got %+v", last.Data)
+	}
+
+	router.HandleInteraction(testInteraction(t, `{"id":"9","name":"test","type":1,
+		"options":[{"name":"user","type":6,"value":"7"}],
+		"resolved":{"users":{"7":{"id":"7","username":"target"}},"members":{"7":{"nick":"tgt","roles":[]}}}}`), responder)
+	if !ran {
+		t.Fatalf("Expected the command to run with the resolved member")
+	}
+}
@@ -0,0 +1,91 @@
+package middlewares
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// LimitsMiddleware enforces the cooldowns and the concurrency limits of commands.
+// Members with one of bypassRoleIDs aren't subject to cooldowns, concurrency limits still apply to them.
+// Add it after RequirementsMiddleware so denied runs don't count as uses.
+func LimitsMiddleware(store interfaces.CooldownStore, bypassRoleIDs []string) interfaces.CommandMiddleware {
+	return func(next interfaces.CommandRunner) interfaces.CommandRunner {
+		return func(c interfaces.Client, command interfaces.BaseCommand, ctx interfaces.CommandContext) error {
+			bypass := bypasses(ctx, bypassRoleIDs)
+			// the limits of a group are shared by its subcommands, they're keyed by the group
+			for _, cmd := range commandPath(command) {
+				release, err := takeLimits(store, cmd, ctx, bypass)
+				if err != nil {
+					return err
+				}
+				defer release()
+			}
+			return next(c, command, ctx)
+		}
+	}
+}
+
+// takeLimits records a use of command and takes a run of its concurrency limit,
+// release gives the run back once the command is over.
+func takeLimits(store interfaces.CooldownStore, command interfaces.BaseCommand, ctx interfaces.CommandContext, bypass bool) (release func(), err error) {
+	limits := command.GetLimits()
+
+	if cooldown := limits.Cooldown; cooldown != nil && cooldown.Uses > 0 && !bypass {
+		retryAfter, ok := store.Take(limitKey(command, "cooldown", cooldown.Scope, ctx), *cooldown, time.Now())
+		if !ok {
+			return nil, &domain.CommandDeniedError{
+				Reason:     fmt.Sprintf("Slow down! You can use this command again in %s.", formatRetryAfter(retryAfter)),
+				RetryAfter: retryAfter,
+			}
+		}
+	}
+
+	if limits.MaxConcurrency > 0 {
+		release, ok := store.Acquire(limitKey(command, "concurrency", limits.ConcurrencyScope, ctx), limits.MaxConcurrency)
+		if !ok {
+			return nil, &domain.CommandDeniedError{Reason: "This command is already running, try again once it's done."}
+		}
+		return release, nil
+	}
+	return func() {}, nil
+}
+
+func bypasses(ctx interfaces.CommandContext, bypassRoleIDs []string) bool {
+	member := ctx.GetMember()
+	if member == nil {
+		return false
+	}
+	return slices.ContainsFunc(member.Roles, func(role string) bool {
+		return slices.Contains(bypassRoleIDs, role)
+	})
+}
+
+// limitKey keys the uses of a command by scope.
+// Commands live as long as the bot, their address tells apart subcommands sharing a name.
+func limitKey(command interfaces.BaseCommand, kind string, scope domain.LimitScope, ctx interfaces.CommandContext) string {
+	var scopeID string
+	switch scope {
+	case domain.LimitScope_USER:
+		scopeID = "user:" + ctx.GetAuthor().ID
+	case domain.LimitScope_CHANNEL:
+		scopeID = "channel:" + ctx.GetChannelID()
+	case domain.LimitScope_GUILD:
+		if ctx.GetGuildID() != "" {
+			scopeID = "guild:" + ctx.GetGuildID()
+		} else {
+			scopeID = "channel:" + ctx.GetChannelID()
+		}
+	case domain.LimitScope_GLOBAL:
+		scopeID = "global"
+	}
+	return fmt.Sprintf("%s:%s:%p:%s", kind, command.GetName(), command, scopeID)
+}
+
+// formatRetryAfter rounds up to the second, e.g. "12s" or "1m30s".
+func formatRetryAfter(d time.Duration) string {
+	return (d + time.Second - 1).Truncate(time.Second).String()
+}
@@ -0,0 +1,102 @@
+package middlewares
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/implementation/cooldownstore"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+func runLimited(command *fakeCommand, ctx interfaces.CommandContext, store interfaces.CooldownStore) error {
+	runner := LimitsMiddleware(store, []string{djRoleID})(func(c interfaces.Client, command interfaces.BaseCommand, ctx interfaces.CommandContext) error {
+		return command.Run(c, ctx)
+	})
+	return runner(nil, command, ctx)
+}
+
+func TestLimits_Cooldown(t *testing.T) {
+	store := cooldownstore.NewCooldownStore(100)
+	command := &fakeCommand{limits: domain.CommandLimits{Cooldown: &domain.Cooldown{Uses: 1, Window: time.Minute, Scope: domain.LimitScope_USER}}}
+
+	if err := runLimited(command, messageContext(channelID), store); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	err := runLimited(command, messageContext(channelID), store)
+	var deniedErr *domain.CommandDeniedError
+	if !errors.As(err, &deniedErr) || deniedErr.RetryAfter <= 0 || deniedErr.RetryAfter > time.Minute {
+		t.Fatalf("Expected a cooldown with the time left, got %v", err)
+	}
+
+	// other users have their own cooldown
+	other := messageContext(channelID)
+	other.author = &domain.User{ID: "700000000000000007"}
+	if err := runLimited(command, other, store); err != nil {
+		t.Fatalf("Expected another user not to be on cooldown, got %v", err)
+	}
+
+	// members with a bypass role have none
+	for i := 0; i < 3; i++ {
+		if err := runLimited(command, messageContext(channelID, djRoleID), store); err != nil {
+			t.Fatalf("Expected the bypass role to skip the cooldown, got %v", err)
+		}
+	}
+}
+
+func TestLimits_Concurrency(t *testing.T) {
+	store := cooldownstore.NewCooldownStore(100)
+	command := &fakeCommand{
+		limits: domain.CommandLimits{MaxConcurrency: 1, ConcurrencyScope: domain.LimitScope_GUILD},
+		wait:   make(chan struct{}),
+	}
+
+	done := make(chan error)
+	go func() { done <- runLimited(command, messageContext(channelID), store) }()
+
+	// wait for the first run to hold its slot
+	deadline := time.Now().Add(time.Second)
+	for {
+		release, ok := store.Acquire(limitKey(command, "concurrency", domain.LimitScope_GUILD, messageContext(channelID)), 1)
+		if !ok {
+			break
+		}
+		release()
+		if time.Now().After(deadline) {
+			t.Fatalf("The first run didn't start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	var deniedErr *domain.CommandDeniedError
+	if err := runLimited(&fakeCommand{limits: command.limits}, messageContext(channelID), store); err != nil {
+		t.Fatalf("Expected another command not to share the limit, got %v", err)
+	}
+	if err := runLimited(command, messageContext(threadID), store); !errors.As(err, &deniedErr) {
+		t.Fatalf("Expected the second run in the guild to be denied, got %v", err)
+	}
+
+	close(command.wait)
+	if err := <-done; err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := runLimited(command, messageContext(channelID), store); err != nil {
+		t.Fatalf("Expected a run once the first one is over, got %v", err)
+	}
+}
+
+func TestLimits_GroupCooldownIsShared(t *testing.T) {
+	store := cooldownstore.NewCooldownStore(100)
+	group := &fakeCommand{limits: domain.CommandLimits{Cooldown: &domain.Cooldown{Uses: 1, Window: time.Minute, Scope: domain.LimitScope_USER}}}
+	set := &fakeCommand{parent: group}
+	list := &fakeCommand{parent: group}
+
+	if err := runLimited(set, messageContext(channelID), store); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var deniedErr *domain.CommandDeniedError
+	if err := runLimited(list, messageContext(channelID), store); !errors.As(err, &deniedErr) || list.ran {
+		t.Fatalf("Expected the cooldown of the group to apply to its other subcommands, got %v", err)
+	}
+}
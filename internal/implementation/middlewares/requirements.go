@@ -0,0 +1,209 @@
+package middlewares
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// RequirementsMiddleware denies commands whose requirements aren't met, then runs their guards.
+// ownerIDs are the users allowed to run owner only commands.
+func RequirementsMiddleware(ownerIDs []string) interfaces.CommandMiddleware {
+	return func(next interfaces.CommandRunner) interfaces.CommandRunner {
+		return func(c interfaces.Client, command interfaces.BaseCommand, ctx interfaces.CommandContext) error {
+			if err := CheckRequirements(c, command, ctx, ownerIDs); err != nil {
+				return err
+			}
+			for _, cmd := range commandPath(command) {
+				for _, guard := range cmd.GetGuards() {
+					if err := guard(c, ctx); err != nil {
+						return err
+					}
+				}
+			}
+			return next(c, command, ctx)
+		}
+	}
+}
+
+// commandPath returns the parents of command and command, from the top level one down.
+func commandPath(command interfaces.BaseCommand) []interfaces.BaseCommand {
+	var path []interfaces.BaseCommand
+	for ; command != nil; command = command.GetParent() {
+		path = append(path, command)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// PathRequirements returns the requirements of command merged with those of its parents,
+// a subcommand can't be run by someone who couldn't run its group.
+func PathRequirements(command interfaces.BaseCommand) domain.CommandRequirements {
+	var merged domain.CommandRequirements
+	for _, cmd := range commandPath(command) {
+		requirements := cmd.GetRequirements()
+		merged.UserPermissions |= requirements.UserPermissions
+		merged.BotPermissions |= requirements.BotPermissions
+		merged.OwnerOnly = merged.OwnerOnly || requirements.OwnerOnly
+		merged.GuildOnly = merged.GuildOnly || requirements.GuildOnly
+		merged.DMOnly = merged.DMOnly || requirements.DMOnly
+		merged.NSFWOnly = merged.NSFWOnly || requirements.NSFWOnly
+	}
+	return merged
+}
+
+// CheckRequirements tells if command can run in ctx, with the requirements of its parents, without the guards.
+// It returns a *domain.CommandDeniedError when it can't, other errors when the check itself failed.
+func CheckRequirements(c interfaces.Client, command interfaces.BaseCommand, ctx interfaces.CommandContext, ownerIDs []string) error {
+	requirements := PathRequirements(command)
+	inGuild := ctx.GetGuildID() != ""
+
+	if requirements.OwnerOnly && !slices.Contains(ownerIDs, ctx.GetAuthor().ID) {
+		return &domain.CommandDeniedError{Reason: "Only the owners of the bot can use this command."}
+	}
+	if requirements.GuildOnly && !inGuild {
+		return &domain.CommandDeniedError{Reason: "This command can only be used in a server."}
+	}
+	if requirements.DMOnly && inGuild {
+		return &domain.CommandDeniedError{Reason: "This command can only be used in DMs."}
+	}
+	// nothing else to check in DMs, where every permission is granted
+	if !inGuild || (requirements.UserPermissions == 0 && requirements.BotPermissions == 0 && !requirements.NSFWOnly) {
+		return nil
+	}
+
+	checker := &permissionsChecker{client: c, ctx: ctx}
+	if requirements.NSFWOnly {
+		channel, err := checker.channel()
+		if err != nil {
+			return err
+		}
+		if !channel.NSFW {
+			return &domain.CommandDeniedError{Reason: "This command can only be used in age-restricted channels."}
+		}
+	}
+	if requirements.UserPermissions != 0 {
+		permissions, err := checker.userPermissions()
+		if err != nil {
+			return err
+		}
+		if missing := permissions.Missing(requirements.UserPermissions); missing != 0 {
+			return &domain.CommandDeniedError{Reason: fmt.Sprintf("You need the %s to use this command.", describe(missing))}
+		}
+	}
+	if requirements.BotPermissions != 0 {
+		permissions, err := checker.botPermissions()
+		if err != nil {
+			return err
+		}
+		if missing := permissions.Missing(requirements.BotPermissions); missing != 0 {
+			return &domain.CommandDeniedError{Reason: fmt.Sprintf("I need the %s in this channel to do that.", describe(missing))}
+		}
+	}
+	return nil
+}
+
+// describe names permissions for denial messages, e.g. "Connect and Speak permissions".
+func describe(permissions domain.Permissions) string {
+	names := permissions.Names()
+	if len(names) == 1 {
+		return names[0] + " permission"
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1] + " permissions"
+}
+
+// permissionsChecker computes permissions in the channel of a command.
+// Interactions come with them, they're computed from the cache for messages.
+type permissionsChecker struct {
+	client interfaces.Client
+	ctx    interfaces.CommandContext
+
+	// the channel the overwrites come from, the parent one for threads
+	permissionsChannel *domain.Channel
+	guild              *domain.Guild
+}
+
+func (p *permissionsChecker) interaction() *domain.Interaction {
+	if ctx, ok := p.ctx.(interface{ GetInteraction() *domain.Interaction }); ok {
+		return ctx.GetInteraction()
+	}
+	return nil
+}
+
+func (p *permissionsChecker) channel() (*domain.Channel, error) {
+	channel, err := p.client.GetChannel(p.ctx.Context(), p.ctx.GetChannelID())
+	if err != nil {
+		return nil, fmt.Errorf("[Middlewares] failed to get channel %s: %w", p.ctx.GetChannelID(), err)
+	}
+	if isThread(channel.Type) && channel.ParentID != nil {
+		// threads are age-restricted and have overwrites through their parent
+		return p.parent(channel)
+	}
+	return channel, nil
+}
+
+func (p *permissionsChecker) parent(thread *domain.Channel) (*domain.Channel, error) {
+	parent, err := p.client.GetChannel(p.ctx.Context(), *thread.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("[Middlewares] failed to get channel %s: %w", *thread.ParentID, err)
+	}
+	return parent, nil
+}
+
+func (p *permissionsChecker) userPermissions() (domain.Permissions, error) {
+	member := p.ctx.GetMember()
+	if member != nil && member.Permissions != nil {
+		return *member.Permissions, nil
+	}
+	if member == nil {
+		var err error
+		if member, err = p.client.GetMember(p.ctx.Context(), p.ctx.GetAuthor().ID, p.ctx.GetGuildID()); err != nil {
+			return 0, fmt.Errorf("[Middlewares] failed to get member %s: %w", p.ctx.GetAuthor().ID, err)
+		}
+	}
+	if member.ID == "" {
+		// members of messages come without their user
+		withID := *member
+		withID.ID = p.ctx.GetAuthor().ID
+		member = &withID
+	}
+	return p.compute(member)
+}
+
+func (p *permissionsChecker) botPermissions() (domain.Permissions, error) {
+	if interaction := p.interaction(); interaction != nil {
+		return interaction.AppPermissions, nil
+	}
+	self := p.client.GetSelfUser()
+	if self == nil {
+		return 0, fmt.Errorf("[Middlewares] the bot user isn't known before READY")
+	}
+	member, err := p.client.GetMember(p.ctx.Context(), self.ID, p.ctx.GetGuildID())
+	if err != nil {
+		return 0, fmt.Errorf("[Middlewares] failed to get the bot member: %w", err)
+	}
+	return p.compute(member)
+}
+
+func (p *permissionsChecker) compute(member *domain.Member) (domain.Permissions, error) {
+	var err error
+	if p.guild == nil {
+		if p.guild, err = p.client.GetGuild(p.ctx.Context(), p.ctx.GetGuildID()); err != nil {
+			return 0, fmt.Errorf("[Middlewares] failed to get guild %s: %w", p.ctx.GetGuildID(), err)
+		}
+	}
+	if p.permissionsChannel == nil {
+		if p.permissionsChannel, err = p.channel(); err != nil {
+			return 0, err
+		}
+	}
+	return domain.ComputePermissions(p.guild, p.permissionsChannel, member, time.Now()), nil
+}
+
+func isThread(t domain.ChannelType) bool {
+	return t == domain.ChannelType_ANNOUNCEMENT_THREAD || t == domain.ChannelType_PUBLIC_THREAD || t == domain.ChannelType_PRIVATE_THREAD
+}
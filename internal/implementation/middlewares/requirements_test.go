@@ -0,0 +1,276 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+const (
+	guildID   = "100000000000000001"
+	channelID = "200000000000000002"
+	threadID  = "200000000000000003"
+	userID    = "300000000000000003"
+	botID     = "400000000000000004"
+	ownerID   = "500000000000000005"
+	djRoleID  = "600000000000000006"
+)
+
+// the guild lets everyone send messages, the DJ role manages them except in the channel
+var testGuild = &domain.Guild{
+	ID:      guildID,
+	OwnerID: ownerID,
+	Roles: []domain.Role{
+		{ID: guildID, Permissions: domain.Permissions_VIEW_CHANNEL | domain.Permissions_SEND_MESSAGES | domain.Permissions_CONNECT},
+		{ID: djRoleID, Permissions: domain.Permissions_MANAGE_MESSAGES},
+	},
+}
+
+var testChannel = &domain.Channel{
+	ID:      channelID,
+	GuildID: guildID,
+	PermissionOverwrites: []domain.PermissionOverwrite{
+		{ID: guildID, Type: domain.PermissionOverwriteType_ROLE, Deny: domain.Permissions_SEND_MESSAGES},
+		{ID: djRoleID, Type: domain.PermissionOverwriteType_ROLE, Allow: domain.Permissions_SEND_MESSAGES, Deny: domain.Permissions_MANAGE_MESSAGES},
+		{ID: userID, Type: domain.PermissionOverwriteType_MEMBER, Allow: domain.Permissions_MANAGE_MESSAGES},
+	},
+}
+
+type fakeClient struct {
+	interfaces.Client
+	members map[string]*domain.Member
+}
+
+func (f *fakeClient) GetGuild(ctx context.Context, ID string) (*domain.Guild, error) {
+	return testGuild, nil
+}
+
+func (f *fakeClient) GetChannel(ctx context.Context, ID string) (*domain.Channel, error) {
+	if ID == threadID {
+		parentID := channelID
+		return &domain.Channel{ID: threadID, GuildID: guildID, Type: domain.ChannelType_PUBLIC_THREAD, ParentID: &parentID}, nil
+	}
+	return testChannel, nil
+}
+
+func (f *fakeClient) GetMember(ctx context.Context, memberID, guild string) (*domain.Member, error) {
+	if member, ok := f.members[memberID]; ok {
+		return member, nil
+	}
+	return nil, &domain.APIError{Status: 404, Code: domain.APIErrorCode_UNKNOWN_MEMBER}
+}
+
+func (f *fakeClient) GetSelfUser() *domain.User {
+	return &domain.User{ID: botID}
+}
+
+type fakeContext struct {
+	interfaces.CommandContext
+	guildID, channelID string
+	author             *domain.User
+	member             *domain.Member
+}
+
+func (f *fakeContext) GetGuildID() string        { return f.guildID }
+func (f *fakeContext) GetChannelID() string      { return f.channelID }
+func (f *fakeContext) GetAuthor() *domain.User   { return f.author }
+func (f *fakeContext) GetMember() *domain.Member { return f.member }
+func (f *fakeContext) Context() context.Context  { return context.Background() }
+
+type fakeCommand struct {
+	interfaces.BaseCommand
+	requirements domain.CommandRequirements
+	guards       []interfaces.CommandGuard
+	limits       domain.CommandLimits
+	parent       interfaces.BaseCommand
+	ran          bool
+	// blocks Run until closed, when set
+	wait chan struct{}
+}
+
+func (f *fakeCommand) GetName() string                   { return "test" }
+func (f *fakeCommand) GetLimits() domain.CommandLimits   { return f.limits }
+func (f *fakeCommand) GetParent() interfaces.BaseCommand { return f.parent }
+
+func (f *fakeCommand) GetRequirements() domain.CommandRequirements { return f.requirements }
+func (f *fakeCommand) GetGuards() []interfaces.CommandGuard        { return f.guards }
+func (f *fakeCommand) Run(c interfaces.Client, ctx interfaces.CommandContext) error {
+	f.ran = true
+	if f.wait != nil {
+		<-f.wait
+	}
+	return nil
+}
+
+func run(c interfaces.Client, command *fakeCommand, ctx interfaces.CommandContext) error {
+	runner := RequirementsMiddleware([]string{ownerID})(func(c interfaces.Client, command interfaces.BaseCommand, ctx interfaces.CommandContext) error {
+		return command.Run(c, ctx)
+	})
+	return runner(c, command, ctx)
+}
+
+// messageContext is a prefix command in the test channel, members of messages have no ID.
+func messageContext(channel string, roles ...string) *fakeContext {
+	return &fakeContext{
+		guildID:   guildID,
+		channelID: channel,
+		author:    &domain.User{ID: userID},
+		member:    &domain.Member{Roles: roles},
+	}
+}
+
+func TestComputePermissions_Overwrites(t *testing.T) {
+	member := &domain.Member{ID: "700000000000000007", Roles: []string{djRoleID}}
+	permissions := domain.ComputePermissions(testGuild, testChannel, member, time.Now())
+	// the DJ overwrite allows sending back and denies managing, no member overwrite
+	if !permissions.Has(domain.Permissions_SEND_MESSAGES) || permissions.Has(domain.Permissions_MANAGE_MESSAGES) {
+		t.Fatalf("Expected send without manage, got %v", permissions.Names())
+	}
+
+	// the member overwrite comes last
+	member.ID = userID
+	if permissions := domain.ComputePermissions(testGuild, testChannel, member, time.Now()); !permissions.Has(domain.Permissions_MANAGE_MESSAGES) {
+		t.Fatalf("Expected the member overwrite to allow manage, got %v", permissions.Names())
+	}
+
+	// timed out members can only read
+	until := time.Now().Add(time.Hour)
+	member.CommunicationDisabledUntil = &until
+	if permissions := domain.ComputePermissions(testGuild, testChannel, member, time.Now()); permissions.Has(domain.Permissions_SEND_MESSAGES) {
+		t.Fatalf("Expected a timed out member not to send, got %v", permissions.Names())
+	}
+
+	if permissions := domain.ComputePermissions(testGuild, testChannel, &domain.Member{ID: ownerID}, time.Now()); permissions != domain.Permissions_ALL {
+		t.Fatalf("Expected the owner to have every permission")
+	}
+}
+
+func TestRequirements_UserPermissions(t *testing.T) {
+	c := &fakeClient{}
+	command := &fakeCommand{requirements: domain.CommandRequirements{UserPermissions: domain.Permissions_MANAGE_MESSAGES | domain.Permissions_SEND_MESSAGES}}
+
+	// without the member overwrite, @everyone can't send in the channel
+	err := run(c, command, &fakeContext{guildID: guildID, channelID: channelID, author: &domain.User{ID: "700000000000000007"}, member: &domain.Member{}})
+	var deniedErr *domain.CommandDeniedError
+	if !errors.As(err, &deniedErr) || command.ran {
+		t.Fatalf("Expected the command to be denied, got %v", err)
+	}
+	if !strings.Contains(deniedErr.Reason, "Send Messages and Manage Messages permissions") {
+		t.Fatalf("Expected the missing permissions in the reason, got %q", deniedErr.Reason)
+	}
+
+	// the channel overwrites of the parent apply in threads
+	if err := run(c, command, messageContext(threadID, djRoleID)); err != nil || !command.ran {
+		t.Fatalf("Expected the command to run, got %v", err)
+	}
+
+	// interactions come with the permissions of the member
+	command.ran = false
+	granted := domain.Permissions_MANAGE_MESSAGES | domain.Permissions_SEND_MESSAGES
+	ctx := messageContext(channelID)
+	ctx.member.Permissions = &granted
+	if err := run(c, command, ctx); err != nil || !command.ran {
+		t.Fatalf("Expected the command to run, got %v", err)
+	}
+}
+
+func TestRequirements_BotPermissions(t *testing.T) {
+	c := &fakeClient{members: map[string]*domain.Member{botID: {ID: botID}}}
+	command := &fakeCommand{requirements: domain.CommandRequirements{BotPermissions: domain.Permissions_CONNECT | domain.Permissions_SPEAK}}
+
+	err := run(c, command, messageContext(channelID))
+	var deniedErr *domain.CommandDeniedError
+	if !errors.As(err, &deniedErr) || !strings.Contains(deniedErr.Reason, "I need the Speak permission") {
+		t.Fatalf("Expected the bot to miss Speak, got %v", err)
+	}
+
+	c.members[botID].Roles = []string{djRoleID}
+	testGuild.Roles[1].Permissions |= domain.Permissions_SPEAK
+	defer func() { testGuild.Roles[1].Permissions &^= domain.Permissions_SPEAK }()
+	if err := run(c, command, messageContext(channelID)); err != nil || !command.ran {
+		t.Fatalf("Expected the command to run, got %v", err)
+	}
+}
+
+func TestRequirements_FlagsAndGuards(t *testing.T) {
+	c := &fakeClient{}
+	dm := &fakeContext{channelID: channelID, author: &domain.User{ID: userID}}
+
+	tests := map[string]struct {
+		requirements domain.CommandRequirements
+		ctx          *fakeContext
+		allowed      bool
+	}{
+		"owner only":          {domain.CommandRequirements{OwnerOnly: true}, dm, false},
+		"guild only in DMs":   {domain.CommandRequirements{GuildOnly: true}, dm, false},
+		"DM only in a guild":  {domain.CommandRequirements{DMOnly: true}, messageContext(channelID), false},
+		"NSFW only":           {domain.CommandRequirements{NSFWOnly: true}, messageContext(channelID), false},
+		"NSFW only in DMs":    {domain.CommandRequirements{NSFWOnly: true}, dm, true},
+		"permissions in DMs":  {domain.CommandRequirements{UserPermissions: domain.Permissions_ADMINISTRATOR}, dm, true},
+		"DM only in DMs":      {domain.CommandRequirements{DMOnly: true}, dm, true},
+		"guild only in guild": {domain.CommandRequirements{GuildOnly: true}, messageContext(channelID), true},
+	}
+	for name, test := range tests {
+		command := &fakeCommand{requirements: test.requirements}
+		err := run(c, command, test.ctx)
+		if command.ran != test.allowed {
+			t.Fatalf("%s: expected allowed to be %v, got %v", name, test.allowed, err)
+		}
+	}
+
+	var calls []string
+	guard := func(name string, err error) interfaces.CommandGuard {
+		return func(c interfaces.Client, ctx interfaces.CommandContext) error {
+			calls = append(calls, name)
+			return err
+		}
+	}
+	denied := &domain.CommandDeniedError{Reason: "Nothing is playing."}
+	command := &fakeCommand{guards: []interfaces.CommandGuard{guard("first", nil), guard("second", denied), guard("third", nil)}}
+	if err := run(c, command, dm); err != denied || command.ran {
+		t.Fatalf("Expected the guard to deny the command, got %v", err)
+	}
+	if strings.Join(calls, " ") != "first second" {
+		t.Fatalf("Expected the guards to run in order until one denies, got %v", calls)
+	}
+}
+
+func TestRequirements_Parents(t *testing.T) {
+	c := &fakeClient{}
+	var guarded bool
+	group := &fakeCommand{
+		requirements: domain.CommandRequirements{GuildOnly: true},
+		guards: []interfaces.CommandGuard{func(c interfaces.Client, ctx interfaces.CommandContext) error {
+			guarded = true
+			return nil
+		}},
+	}
+	sub := &fakeCommand{parent: group}
+
+	dm := &fakeContext{channelID: channelID, author: &domain.User{ID: userID}}
+	var deniedErr *domain.CommandDeniedError
+	if err := run(c, sub, dm); !errors.As(err, &deniedErr) || sub.ran {
+		t.Fatalf("Expected the requirements of the group to deny its subcommand, got %v", err)
+	}
+	if err := run(c, sub, messageContext(channelID)); err != nil || !sub.ran || !guarded {
+		t.Fatalf("Expected the guards of the group to run before its subcommand, got %v", err)
+	}
+
+	group.guards = []interfaces.CommandGuard{func(c interfaces.Client, ctx interfaces.CommandContext) error {
+		return &domain.CommandDeniedError{Reason: "Nothing is playing."}
+	}}
+	sub.ran = false
+	if err := run(c, sub, messageContext(channelID)); !errors.As(err, &deniedErr) || sub.ran {
+		t.Fatalf("Expected the guard of the group to deny its subcommand, got %v", err)
+	}
+
+	merged := PathRequirements(&fakeCommand{parent: group, requirements: domain.CommandRequirements{OwnerOnly: true}})
+	if !merged.GuildOnly || !merged.OwnerOnly {
+		t.Fatalf("Expected the requirements of the path to be merged, got %+v", merged)
+	}
+}
@@ -0,0 +1,93 @@
+package prefixresolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+type PrefixResolverImpl struct {
+	client          interfaces.Client
+	settings        interfaces.GuildSettingsRepository
+	defaultPrefix   string
+	caseInsensitive bool
+}
+
+type PrefixResolverOptions struct {
+	Client   interfaces.Client
+	Settings interfaces.GuildSettingsRepository
+	// used in DMs and in guilds without prefixes
+	DefaultPrefix string
+	// "V!" matches the prefix "v!" too
+	CaseInsensitive bool
+}
+
+func NewPrefixResolver(options *PrefixResolverOptions) interfaces.PrefixResolver {
+	return &PrefixResolverImpl{
+		client:          options.Client,
+		settings:        options.Settings,
+		defaultPrefix:   options.DefaultPrefix,
+		caseInsensitive: options.CaseInsensitive,
+	}
+}
+
+func (r *PrefixResolverImpl) GetPrefixes(ctx context.Context, guildID string) ([]string, error) {
+	if guildID == "" {
+		return []string{r.defaultPrefix}, nil
+	}
+	settings, err := r.settings.Get(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("[PrefixResolver] failed to get the settings of guild %s: %w", guildID, err)
+	}
+	if len(settings.Prefixes) == 0 {
+		return []string{r.defaultPrefix}, nil
+	}
+	return settings.Prefixes, nil
+}
+
+func (r *PrefixResolverImpl) MatchPrefix(ctx context.Context, guildID, content string) (*domain.PrefixMatch, error) {
+	prefixes, err := r.GetPrefixes(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	if line, ok := r.matchMention(content); ok {
+		return &domain.PrefixMatch{Prefix: prefixes[0], Line: line, Mention: true}, nil
+	}
+
+	// the longest prefix first, so "!!" isn't read as "!" followed by "!"
+	var matched string
+	for _, prefix := range prefixes {
+		if len(prefix) > len(matched) && len(content) >= len(prefix) && r.equal(content[:len(prefix)], prefix) {
+			matched = prefix
+		}
+	}
+	if matched == "" {
+		return nil, nil
+	}
+	return &domain.PrefixMatch{Prefix: content[:len(matched)], Line: content[len(matched):]}, nil
+}
+
+// matchMention reads "<@id> line" and "<@!id> line", the mention must be followed by a space or nothing.
+func (r *PrefixResolverImpl) matchMention(content string) (string, bool) {
+	self := r.client.GetSelfUser()
+	if self == nil {
+		return "", false
+	}
+	for _, mention := range []string{"<@" + self.ID + ">", "<@!" + self.ID + ">"} {
+		if rest, ok := strings.CutPrefix(content, mention); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\n') {
+			return rest, true
+		}
+	}
+	return "", false
+}
+
+func (r *PrefixResolverImpl) equal(a, b string) bool {
+	if r.caseInsensitive {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
@@ -0,0 +1,83 @@
+package prefixresolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/implementation/guildsettings"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+const (
+	guildID = "100000000000000001"
+	botID   = "200000000000000002"
+)
+
+type fakeClient struct {
+	interfaces.Client
+}
+
+func (f *fakeClient) GetSelfUser() *domain.User {
+	return &domain.User{ID: botID}
+}
+
+func newTestResolver(t *testing.T, caseInsensitive bool, prefixes ...string) interfaces.PrefixResolver {
+	settings := guildsettings.NewMemoryGuildSettingsRepository()
+	if err := settings.Save(context.Background(), &domain.GuildSettings{GuildID: guildID, Prefixes: prefixes}); err != nil {
+		t.Fatalf("Failed to save settings: %v", err)
+	}
+	return NewPrefixResolver(&PrefixResolverOptions{
+		Client:          &fakeClient{},
+		Settings:        settings,
+		DefaultPrefix:   ".",
+		CaseInsensitive: caseInsensitive,
+	})
+}
+
+func TestMatchPrefix(t *testing.T) {
+	resolver := newTestResolver(t, false, "!", "!!", "v!")
+
+	tests := []struct {
+		guildID, content string
+		match            *domain.PrefixMatch
+	}{
+		{guildID, "!!play song", &domain.PrefixMatch{Prefix: "!!", Line: "play song"}},
+		{guildID, "!play", &domain.PrefixMatch{Prefix: "!", Line: "play"}},
+		{guildID, "V!play", nil},
+		// the default prefix doesn't work in guilds with their own
+		{guildID, ".play", nil},
+		{"", ".play", &domain.PrefixMatch{Prefix: ".", Line: "play"}},
+		{guildID, "<@" + botID + "> play", &domain.PrefixMatch{Prefix: "!", Line: " play", Mention: true}},
+		{guildID, "<@!" + botID + ">", &domain.PrefixMatch{Prefix: "!", Line: "", Mention: true}},
+		{guildID, "<@" + botID + ">play", nil},
+		{guildID, "<@300000000000000003> play", nil},
+	}
+	for _, test := range tests {
+		match, err := resolver.MatchPrefix(context.Background(), test.guildID, test.content)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.content, err)
+		}
+		if (match == nil) != (test.match == nil) || (match != nil && *match != *test.match) {
+			t.Fatalf("%q: expected %+v, got %+v", test.content, test.match, match)
+		}
+	}
+}
+
+func TestMatchPrefix_CaseInsensitive(t *testing.T) {
+	resolver := newTestResolver(t, true, "v!")
+
+	match, err := resolver.MatchPrefix(context.Background(), guildID, "V!play")
+	if err != nil || match == nil || match.Prefix != "V!" || match.Line != "play" {
+		t.Fatalf("Expected V! to match v!, got %+v, %v", match, err)
+	}
+}
+
+func TestGetPrefixes_Default(t *testing.T) {
+	resolver := newTestResolver(t, false)
+
+	prefixes, err := resolver.GetPrefixes(context.Background(), guildID)
+	if err != nil || len(prefixes) != 1 || prefixes[0] != "." {
+		t.Fatalf("Expected the default prefix, got %v, %v", prefixes, err)
+	}
+}
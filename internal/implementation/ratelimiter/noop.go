@@ -0,0 +1,35 @@
+package ratelimiter
+
+import (
+	"context"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// NoopRateLimiterImpl lets every request through.
+// It's used when REST calls go through the rate limit proxy, which does the limiting for every process.
+type NoopRateLimiterImpl struct{}
+
+func NewNoopRateLimiter() *NoopRateLimiterImpl {
+	return &NoopRateLimiterImpl{}
+}
+
+func (r *NoopRateLimiterImpl) Wait(ctx context.Context, route domain.Route) error {
+	return ctx.Err()
+}
+
+func (r *NoopRateLimiterImpl) UpdateLimit(route domain.Route, bucket string, limit *domain.RateLimit) {
+}
+
+func (r *NoopRateLimiterImpl) IsRateLimited(route domain.Route) bool {
+	return false
+}
+
+func (r *NoopRateLimiterImpl) RetryAfter(route domain.Route) time.Duration {
+	return 0
+}
+
+func (r *NoopRateLimiterImpl) QueueMetrics() map[string]domain.RateLimitQueueMetrics {
+	return map[string]domain.RateLimitQueueMetrics{}
+}
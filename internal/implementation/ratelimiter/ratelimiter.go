@@ -1,66 +1,322 @@
 package ratelimiter
 
 import (
+	"context"
 	"sync"
 	"time"
 
 	"github.com/marouane-souiri/vocalize/internal/domain"
 )
 
+const (
+	// https://discord.com/developers/docs/topics/rate-limits#global-rate-limit
+	defaultGlobalLimit  = 50
+	defaultGlobalWindow = time.Second
+	// a limit key unused for this long, once its window is over, is dropped with its queue and metrics
+	defaultIdleTimeout = time.Minute
+)
+
+// RateLimiterImpl follows the buckets discord reports.
+//
+// Discord doesn't publish which routes share a limit, it answers with an X-RateLimit-Bucket hash.
+// Each route template is mapped to the last bucket hash seen for it,
+// and limits are stored per "bucket:majorParam" so routes sharing a bucket share the limit.
+// Until a route has been answered once, its limit is keyed by the route itself.
+//
+// Requests queue per limit key and get their permit in FIFO order.
+// The remaining counter is decremented locally when a permit is handed out,
+// so a burst never sends more requests than the bucket has room for.
+//
+// There is a limit key per channel and guild used, the idle ones are swept
+// so a long running bot doesn't keep one for everything it ever touched.
 type RateLimiterImpl struct {
 	mu          sync.RWMutex
 	globalLimit *domain.RateLimit
+	// route key -> bucket hash, there is one per route template so it isn't swept
+	buckets map[string]string
+	// limit key -> limit
 	routeLimits map[string]*domain.RateLimit
+	// limit key -> queue
+	queues  map[string]*limitQueue
+	metrics map[string]*domain.RateLimitQueueMetrics
+
+	idleTimeout time.Duration
+	lastSweep   time.Time
+
+	// proactive global limit, discord allows globalPerWindow requests per globalWindow
+	globalQueue       queue
+	globalPerWindow   int
+	globalWindow      time.Duration
+	globalWindowStart time.Time
+	globalCount       int
+}
+
+// queue is a FIFO lock: the runtime hands the token to receivers in the order they started waiting.
+type queue chan struct{}
+
+func newQueue() queue {
+	q := make(queue, 1)
+	q <- struct{}{}
+	return q
+}
+
+func (q queue) acquire(ctx context.Context) error {
+	select {
+	case <-q:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q queue) release() {
+	q <- struct{}{}
+}
+
+type limitQueue struct {
+	queue
+	// requests holding or waiting for the queue, it isn't swept while there are some
+	users    int
+	lastUsed time.Time
 }
 
 func NewRateLimiter() *RateLimiterImpl {
 	return &RateLimiterImpl{
-		routeLimits: make(map[string]*domain.RateLimit),
+		buckets:         make(map[string]string),
+		routeLimits:     make(map[string]*domain.RateLimit),
+		queues:          make(map[string]*limitQueue),
+		metrics:         make(map[string]*domain.RateLimitQueueMetrics),
+		idleTimeout:     defaultIdleTimeout,
+		lastSweep:       time.Now(),
+		globalQueue:     newQueue(),
+		globalPerWindow: defaultGlobalLimit,
+		globalWindow:    defaultGlobalWindow,
+	}
+}
+
+// limitKey must be called with r.mu held.
+func (r *RateLimiterImpl) limitKey(route domain.Route) string {
+	if bucket, ok := r.buckets[route.Key()]; ok {
+		return bucket + ":" + route.MajorParam
 	}
+	return route.Key() + ":" + route.MajorParam
 }
 
-func (r *RateLimiterImpl) UpdateLimit(route string, limit *domain.RateLimit) {
+// acquireQueue returns the queue of the route, releaseQueue must be called once done with it.
+func (r *RateLimiterImpl) acquireQueue(route domain.Route) (string, *limitQueue) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	limit.ResetAt = time.Now().Add(limit.ResetAfter)
+	if now := time.Now(); now.Sub(r.lastSweep) >= r.idleTimeout {
+		r.sweep(now)
+	}
 
-	if limit.Global {
-		r.globalLimit = limit
-	} else {
-		r.routeLimits[route] = limit
+	key := r.limitKey(route)
+	q, ok := r.queues[key]
+	if !ok {
+		q = &limitQueue{queue: newQueue()}
+		r.queues[key] = q
+	}
+	q.users++
+	return key, q
+}
+
+func (r *RateLimiterImpl) releaseQueue(q *limitQueue) {
+	r.mu.Lock()
+	q.users--
+	q.lastUsed = time.Now()
+	r.mu.Unlock()
+}
+
+// sweep drops the limit keys unused for idleTimeout whose window is over, it must be called with r.mu held.
+// The next request of a dropped key goes as if it was never answered.
+func (r *RateLimiterImpl) sweep(now time.Time) {
+	r.lastSweep = now
+	for key, q := range r.queues {
+		if q.users > 0 || now.Sub(q.lastUsed) < r.idleTimeout {
+			continue
+		}
+		if limit, ok := r.routeLimits[key]; ok && now.Before(limit.ResetAt) {
+			continue
+		}
+		delete(r.queues, key)
+		delete(r.metrics, key)
+		delete(r.routeLimits, key)
+	}
+	// limits learned from responses of requests that didn't queue here, like through the proxy
+	for key, limit := range r.routeLimits {
+		if _, ok := r.queues[key]; !ok && now.Sub(limit.ResetAt) >= r.idleTimeout {
+			delete(r.routeLimits, key)
+		}
+	}
+}
+
+func (r *RateLimiterImpl) Wait(ctx context.Context, route domain.Route) error {
+	start := time.Now()
+	key, q := r.acquireQueue(route)
+	defer r.releaseQueue(q)
+
+	if err := q.acquire(ctx); err != nil {
+		return err
+	}
+	defer q.release()
+
+	// first in line, wait until the bucket has room then take a permit
+	for {
+		wait := r.reserve(route)
+		if wait <= 0 {
+			break
+		}
+		if err := domain.SleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+
+	if err := r.waitGlobal(ctx); err != nil {
+		return err
+	}
+
+	r.recordWait(key, time.Since(start))
+	return nil
+}
+
+// reserve takes a permit from the route bucket, or returns how long to wait for one.
+func (r *RateLimiterImpl) reserve(route domain.Route) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	// a 429 with global: true blocks everything
+	if r.globalLimit != nil && now.Before(r.globalLimit.ResetAt) {
+		return r.globalLimit.ResetAt.Sub(now)
+	}
+
+	rl, ok := r.routeLimits[r.limitKey(route)]
+	if !ok {
+		// never answered, nothing to go by yet
+		return 0
+	}
+
+	if !now.Before(rl.ResetAt) {
+		// the window is over, assume a fresh one until discord says otherwise
+		if rl.Limit <= 0 {
+			return 0
+		}
+		rl.Remaining = rl.Limit
+		rl.ResetAt = now.Add(rl.Window)
+	}
+
+	if rl.Remaining <= 0 {
+		return rl.ResetAt.Sub(now)
+	}
+	rl.Remaining--
+	return 0
+}
+
+func (r *RateLimiterImpl) waitGlobal(ctx context.Context) error {
+	if err := r.globalQueue.acquire(ctx); err != nil {
+		return err
+	}
+	defer r.globalQueue.release()
+
+	for {
+		r.mu.Lock()
+		now := time.Now()
+		if now.Sub(r.globalWindowStart) >= r.globalWindow {
+			r.globalWindowStart = now
+			r.globalCount = 0
+		}
+		if r.globalCount < r.globalPerWindow {
+			r.globalCount++
+			r.mu.Unlock()
+			return nil
+		}
+		wait := r.globalWindowStart.Add(r.globalWindow).Sub(now)
+		r.mu.Unlock()
+
+		if err := domain.SleepContext(ctx, wait); err != nil {
+			return err
+		}
 	}
 }
 
-func (r *RateLimiterImpl) IsRateLimited(route string) bool {
+func (r *RateLimiterImpl) recordWait(key string, waited time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.metrics[key]
+	if !ok {
+		m = &domain.RateLimitQueueMetrics{}
+		r.metrics[key] = m
+	}
+	m.Requests++
+	m.TotalWait += waited
+	m.MaxWait = max(m.MaxWait, waited)
+}
+
+func (r *RateLimiterImpl) QueueMetrics() map[string]domain.RateLimitQueueMetrics {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	metrics := make(map[string]domain.RateLimitQueueMetrics, len(r.metrics))
+	for key, m := range r.metrics {
+		metrics[key] = *m
+	}
+	return metrics
+}
+
+func (r *RateLimiterImpl) UpdateLimit(route domain.Route, bucket string, limit *domain.RateLimit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	now := time.Now()
+	limit.ResetAt = now.Add(limit.ResetAfter)
 
-	if r.globalLimit != nil && now.Before(r.globalLimit.ResetAt) {
-		return true
+	if limit.Global {
+		r.globalLimit = limit
+		return
 	}
 
-	if rl, ok := r.routeLimits[route]; ok && now.Before(rl.ResetAt) && rl.Remaining <= 0 {
-		return true
+	if bucket != "" {
+		r.buckets[route.Key()] = bucket
 	}
+	key := r.limitKey(route)
+
+	// responses of requests sent earlier in the window don't know about the permits handed out since,
+	// never let them give back room we already used
+	if current, ok := r.routeLimits[key]; ok && now.Before(current.ResetAt) {
+		limit.Remaining = min(limit.Remaining, current.Remaining)
+	}
+	if limit.Limit > 0 {
+		limit.Window = limit.ResetAfter
+	}
+	if current, ok := r.routeLimits[key]; ok {
+		if limit.Limit == 0 {
+			limit.Limit = current.Limit
+		}
+		// the first response of a window tells its whole length, later ones only what's left of it
+		limit.Window = max(limit.Window, current.Window)
+	}
+	r.routeLimits[key] = limit
+}
 
-	return false
+func (r *RateLimiterImpl) IsRateLimited(route domain.Route) bool {
+	return r.RetryAfter(route) > 0
 }
 
-func (r *RateLimiterImpl) RetryAfter(route string) time.Duration {
+func (r *RateLimiterImpl) RetryAfter(route domain.Route) time.Duration {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	now := time.Now()
 
 	if r.globalLimit != nil && now.Before(r.globalLimit.ResetAt) {
-		return time.Until(r.globalLimit.ResetAt)
+		return r.globalLimit.ResetAt.Sub(now)
 	}
 
-	if rl, ok := r.routeLimits[route]; ok && now.Before(rl.ResetAt) && rl.Remaining <= 0 {
-		return time.Until(rl.ResetAt)
+	if rl, ok := r.routeLimits[r.limitKey(route)]; ok && now.Before(rl.ResetAt) && rl.Remaining <= 0 {
+		return rl.ResetAt.Sub(now)
 	}
 
 	return 0
@@ -0,0 +1,294 @@
+package ratelimiter
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/implementation/ratelimiter/ratelimitertest"
+	"github.com/marouane-souiri/vocalize/internal/implementation/requester"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+func newTestSetup(t *testing.T, limit int, window time.Duration) (*ratelimitertest.FakeDiscord, *RateLimiterImpl, interfaces.APIRequester) {
+	discord := ratelimitertest.NewFakeDiscord(limit, window)
+	srv := httptest.NewServer(discord)
+	t.Cleanup(srv.Close)
+
+	rl := NewRateLimiter()
+	api := requester.NewAPIRequester(&requester.APIRequesterOptions{
+		Token:       "test_token",
+		RateLimiter: rl,
+		BaseURL:     srv.URL,
+	})
+	return discord, rl, api
+}
+
+func TestNewRoute(t *testing.T) {
+	tests := []struct {
+		method, endpoint string
+		key, major       string
+	}{
+		{"GET", "/channels/111/messages?limit=50&before=222", "GET /channels/{channel_id}/messages", "111"},
+		{"PATCH", "/channels/111/messages/222", "PATCH /channels/{channel_id}/messages/{id}", "111"},
+		{"PUT", "/channels/111/messages/222/reactions/%F0%9F%91%8D/@me", "PUT /channels/{channel_id}/messages/{id}/reactions/{emoji}/@me", "111"},
+		{"DELETE", "/channels/111/messages/222/reactions/blob:333/444", "DELETE /channels/{channel_id}/messages/{id}/reactions/{emoji}/{user_id}", "111"},
+		{"PUT", "/guilds/555/bans/666", "PUT /guilds/{guild_id}/bans/{id}", "555"},
+		{"PATCH", "/webhooks/777/sometoken/messages/@original", "PATCH /webhooks/{webhook_id}/{token}/messages/@original", "777/sometoken"},
+		{"POST", "/interactions/888/sometoken/callback", "POST /interactions/{id}/{token}/callback", ""},
+		{"GET", "/applications/999/commands", "GET /applications/{id}/commands", ""},
+	}
+
+	for _, tt := range tests {
+		route := domain.NewRoute(tt.method, tt.endpoint)
+		if route.Key() != tt.key || route.MajorParam != tt.major {
+			t.Errorf("NewRoute(%s %s) = %q major %q, want %q major %q",
+				tt.method, tt.endpoint, route.Key(), route.MajorParam, tt.key, tt.major)
+		}
+	}
+}
+
+func TestRateLimiter_RoutesShareDiscoveredBucket(t *testing.T) {
+	discord, rl, api := newTestSetup(t, 2, 300*time.Millisecond)
+	ctx := context.Background()
+
+	// one request on each route teaches the limiter they are the same bucket
+	if _, err := api.GetChannelMessages(ctx, "1", nil); err != nil {
+		t.Fatalf("GetChannelMessages failed: %v", err)
+	}
+	if _, err := api.SendMessage(ctx, "1", &domain.SendMessage{Content: "hi"}); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	// the bucket is now empty, whichever route is used
+	if !rl.IsRateLimited(domain.NewRoute("GET", "/channels/1/messages")) {
+		t.Fatalf("GET messages should be limited once the shared bucket is exhausted")
+	}
+	if !rl.IsRateLimited(domain.NewRoute("POST", "/channels/1/messages")) {
+		t.Fatalf("POST messages should be limited once the shared bucket is exhausted")
+	}
+
+	start := time.Now()
+	for i := range 4 {
+		var err error
+		if i%2 == 0 {
+			_, err = api.GetChannelMessages(ctx, "1", nil)
+		} else {
+			_, err = api.SendMessage(ctx, "1", &domain.SendMessage{Content: "hi"})
+		}
+		if err != nil {
+			t.Fatalf("Request %d failed: %v", i, err)
+		}
+	}
+
+	if _, tooMany := discord.Stats(); tooMany != 0 {
+		t.Fatalf("Expected the shared bucket to be respected, got %d 429s", tooMany)
+	}
+	// 4 more requests at 2 per window need 2 more windows
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Fatalf("Expected to wait for the bucket to reset twice, took %v", elapsed)
+	}
+}
+
+func TestRateLimiter_MajorParamsAreIndependent(t *testing.T) {
+	discord, rl, api := newTestSetup(t, 1, 5*time.Second)
+	ctx := context.Background()
+
+	if _, err := api.SendMessage(ctx, "1", &domain.SendMessage{Content: "hi"}); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if !rl.IsRateLimited(domain.NewRoute("POST", "/channels/1/messages")) {
+		t.Fatalf("Channel 1 should be limited")
+	}
+	if rl.IsRateLimited(domain.NewRoute("POST", "/channels/2/messages")) {
+		t.Fatalf("Channel 2 shouldn't be limited by channel 1")
+	}
+
+	start := time.Now()
+	if _, err := api.SendMessage(ctx, "2", &domain.SendMessage{Content: "hi"}); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Channel 2 waited for channel 1 limit: %v", elapsed)
+	}
+	if _, tooMany := discord.Stats(); tooMany != 0 {
+		t.Fatalf("Expected no 429, got %d", tooMany)
+	}
+}
+
+func TestRateLimiter_ReactionRoutesShareBucket(t *testing.T) {
+	discord, rl, api := newTestSetup(t, 2, 5*time.Second)
+	ctx := context.Background()
+
+	if err := api.CreateReaction(ctx, "1", "2", "👍"); err != nil {
+		t.Fatalf("CreateReaction failed: %v", err)
+	}
+	if err := api.DeleteUserReaction(ctx, "1", "3", "blob:42", "4"); err != nil {
+		t.Fatalf("DeleteUserReaction failed: %v", err)
+	}
+
+	// both routes were answered with the same hash, a different emoji, message or user doesn't matter
+	if !rl.IsRateLimited(domain.NewRoute("PUT", "/channels/1/messages/9/reactions/%F0%9F%8E%B5/@me")) {
+		t.Fatalf("Reaction routes should share the bucket")
+	}
+	if !rl.IsRateLimited(domain.NewRoute("DELETE", "/channels/1/messages/9/reactions/%F0%9F%8E%B5/5")) {
+		t.Fatalf("Reaction routes should share the bucket")
+	}
+	if requests, tooMany := discord.Stats(); requests != 2 || tooMany != 0 {
+		t.Fatalf("Expected 2 requests without 429, got %d requests and %d 429s", requests, tooMany)
+	}
+}
+
+func TestRateLimiter_ConcurrentBurstDoesNotStorm(t *testing.T) {
+	discord, rl, api := newTestSetup(t, 5, 200*time.Millisecond)
+	ctx := context.Background()
+
+	// learn the bucket first, a burst on a route never answered can't be limited
+	if _, err := api.SendMessage(ctx, "1", &domain.SendMessage{Content: "hi"}); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 30)
+	for range 30 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := api.SendMessage(ctx, "1", &domain.SendMessage{Content: "hi"}); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if requests, tooMany := discord.Stats(); tooMany != 0 {
+		t.Fatalf("Expected no 429 on a burst, got %d for %d requests", tooMany, requests)
+	}
+
+	// the warm up request was queued before its bucket was known
+	metrics := rl.QueueMetrics()["messages:1"]
+	if metrics.Requests != 30 || metrics.MaxWait < 800*time.Millisecond {
+		t.Fatalf("Expected 30 queued requests with the last ones waiting >= 4 windows, got %+v", metrics)
+	}
+}
+
+func TestRateLimiter_PermitsAreFIFO(t *testing.T) {
+	rl := NewRateLimiter()
+	route := domain.NewRoute("POST", "/channels/1/messages")
+	rl.UpdateLimit(route, "messages", &domain.RateLimit{Limit: 1, Remaining: 0, ResetAfter: 100 * time.Millisecond})
+
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for i := range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := rl.Wait(context.Background(), route); err != nil {
+				t.Errorf("Wait failed: %v", err)
+				return
+			}
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+		}()
+		// make sure they queue in order
+		time.Sleep(10 * time.Millisecond)
+	}
+	wg.Wait()
+
+	for i, got := range order {
+		if got != i {
+			t.Fatalf("Expected permits in queue order, got %v", order)
+		}
+	}
+}
+
+func TestRateLimiter_WaitHonorsContext(t *testing.T) {
+	rl := NewRateLimiter()
+	route := domain.NewRoute("POST", "/channels/1/messages")
+	rl.UpdateLimit(route, "messages", &domain.RateLimit{Limit: 1, Remaining: 0, ResetAfter: 10 * time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := rl.Wait(ctx, route); err != context.DeadlineExceeded {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestRateLimiter_FreshWindowLastsAWholeWindow(t *testing.T) {
+	rl := NewRateLimiter()
+	route := domain.NewRoute("POST", "/channels/1/messages")
+	rl.UpdateLimit(route, "messages", &domain.RateLimit{Limit: 2, Remaining: 1, ResetAfter: time.Second})
+	// the last response of the window only has 20ms left of it
+	rl.UpdateLimit(route, "messages", &domain.RateLimit{Limit: 2, Remaining: 0, ResetAfter: 20 * time.Millisecond})
+
+	time.Sleep(30 * time.Millisecond)
+	// the window is over, a fresh one is assumed until discord answers
+	if wait := rl.reserve(route); wait != 0 {
+		t.Fatalf("Expected a permit of the fresh window, got a wait of %v", wait)
+	}
+	rl.reserve(route)
+	if wait := rl.reserve(route); wait < 900*time.Millisecond {
+		t.Fatalf("Expected the fresh window to last a whole window, got a wait of %v", wait)
+	}
+}
+
+func TestRateLimiter_ProactiveGlobalLimit(t *testing.T) {
+	rl := NewRateLimiter()
+	rl.globalPerWindow = 5
+	rl.globalWindow = 100 * time.Millisecond
+
+	start := time.Now()
+	for i := range 12 {
+		// every request on its own channel, only the global limit applies
+		route := domain.NewRoute("POST", fmt.Sprintf("/channels/%d/messages", i))
+		if err := rl.Wait(context.Background(), route); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+
+	// 12 requests at 5 per window span 3 windows
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Fatalf("Expected the global limit to spread requests over 3 windows, took %v", elapsed)
+	}
+}
+
+func TestRateLimiter_SweepsIdleKeys(t *testing.T) {
+	rl := NewRateLimiter()
+	rl.idleTimeout = 50 * time.Millisecond
+
+	busy := domain.NewRoute("POST", "/channels/1/messages")
+	rl.UpdateLimit(busy, "messages", &domain.RateLimit{Limit: 1, Remaining: 0, ResetAfter: 10 * time.Second})
+	for i := range 10 {
+		route := domain.NewRoute("POST", fmt.Sprintf("/channels/%d/messages", i+2))
+		if err := rl.Wait(context.Background(), route); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+		rl.UpdateLimit(route, "messages", &domain.RateLimit{Limit: 5, Remaining: 4, ResetAfter: 10 * time.Millisecond})
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if err := rl.Wait(context.Background(), domain.NewRoute("POST", "/channels/100/messages")); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	if len(rl.queues) != 1 || len(rl.metrics) != 1 {
+		t.Fatalf("Expected only the queue of the last request to be left, got %d queues and %d metrics", len(rl.queues), len(rl.metrics))
+	}
+	// still limited, it must be kept
+	if _, ok := rl.routeLimits["messages:1"]; !ok || len(rl.routeLimits) != 1 {
+		t.Fatalf("Expected only the limit still in its window to be left, got %d", len(rl.routeLimits))
+	}
+}
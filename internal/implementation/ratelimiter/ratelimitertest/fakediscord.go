@@ -0,0 +1,117 @@
+// Package ratelimitertest has a fake discord to test rate limiting against.
+package ratelimitertest
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// FakeDiscord enforces per bucket limits the way discord does and counts the 429s it had to send.
+type FakeDiscord struct {
+	mu sync.Mutex
+	// route key -> bucket hash
+	buckets map[string]string
+	limit   int
+	window  time.Duration
+	// bucket:majorParam -> window state
+	windows  map[string]*fakeWindow
+	requests int
+	tooMany  int
+	// Authorization headers received
+	tokens map[string]bool
+}
+
+type fakeWindow struct {
+	used    int
+	resetAt time.Time
+}
+
+// NewFakeDiscord allows limit requests per window for each bucket and major parameter.
+func NewFakeDiscord(limit int, window time.Duration) *FakeDiscord {
+	return &FakeDiscord{
+		buckets: map[string]string{
+			"GET /channels/{channel_id}/messages":                                     "messages",
+			"POST /channels/{channel_id}/messages":                                    "messages",
+			"GET /channels/{channel_id}/messages/{id}":                                "message",
+			"PUT /channels/{channel_id}/messages/{id}/reactions/{emoji}/@me":          "reactions",
+			"DELETE /channels/{channel_id}/messages/{id}/reactions/{emoji}/@me":       "reactions",
+			"DELETE /channels/{channel_id}/messages/{id}/reactions/{emoji}/{user_id}": "reactions",
+		},
+		limit:   limit,
+		window:  window,
+		windows: make(map[string]*fakeWindow),
+		tokens:  make(map[string]bool),
+	}
+}
+
+func (f *FakeDiscord) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route := domain.NewRoute(r.Method, r.URL.EscapedPath())
+	bucket, ok := f.buckets[route.Key()]
+	if !ok {
+		bucket = route.Key()
+	}
+	key := bucket + ":" + route.MajorParam
+
+	f.mu.Lock()
+	f.requests++
+	f.tokens[r.Header.Get("Authorization")] = true
+	now := time.Now()
+	win, ok := f.windows[key]
+	if !ok || now.After(win.resetAt) {
+		win = &fakeWindow{resetAt: now.Add(f.window)}
+		f.windows[key] = win
+	}
+	resetAfter := win.resetAt.Sub(now).Seconds()
+
+	if win.used >= f.limit {
+		f.tooMany++
+		f.mu.Unlock()
+		w.Header().Set("X-RateLimit-Bucket", bucket)
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprintf(w, `{"message":"You are being rate limited.","retry_after":%.3f,"global":false}`, resetAfter)
+		return
+	}
+	win.used++
+	remaining := f.limit - win.used
+	f.mu.Unlock()
+
+	w.Header().Set("X-RateLimit-Bucket", bucket)
+	w.Header().Set("X-RateLimit-Limit", fmt.Sprint(f.limit))
+	w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(remaining))
+	w.Header().Set("X-RateLimit-Reset-After", fmt.Sprintf("%.3f", resetAfter))
+	if r.Method == "GET" && route.Template == "/channels/{channel_id}/messages" {
+		w.Write([]byte(`[]`))
+		return
+	}
+	if r.Method == "PUT" || r.Method == "DELETE" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Write([]byte(`{"id":"1","content":"hi"}`))
+}
+
+// Stats returns how many requests were received, and how many of them got a 429.
+func (f *FakeDiscord) Stats() (requests, tooMany int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests, f.tooMany
+}
+
+// SawToken tells if a request was sent with this Authorization header.
+func (f *FakeDiscord) SawToken(authorization string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tokens[authorization]
+}
+
+// UseUp uses the whole window of bucket:majorParam for resetAfter,
+// as another process not going through the same limiter would.
+func (f *FakeDiscord) UseUp(bucket, majorParam string, resetAfter time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.windows[bucket+":"+majorParam] = &fakeWindow{used: f.limit, resetAt: time.Now().Add(resetAfter)}
+}
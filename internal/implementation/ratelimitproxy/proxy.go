@@ -0,0 +1,223 @@
+package ratelimitproxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+// hop-by-hop headers, they are meant for a single connection and are never forwarded
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+const (
+	// limiters of tokens that sent nothing for this long are dropped, their windows are long over
+	defaultIdleTimeout = 10 * time.Minute
+	// nginx's status for a client that closed the request before it was answered
+	statusClientClosedRequest = 499
+)
+
+// ProxyImpl is a REST proxy in front of discord shared by every process running on the same token.
+//
+// Processes send their requests to the proxy instead of discord (see APIRequesterOptions.BaseURL)
+// with a no-op rate limiter, the proxy queues them through one rate limiter per token
+// so global and bucket limits hold across processes.
+// Requests without the shared secret are refused, the proxy would otherwise relay anyone to discord.
+type ProxyImpl struct {
+	upstream    string
+	client      *http.Client
+	newLimiter  func() interfaces.RateLimiter
+	secret      []byte
+	idleTimeout time.Duration
+
+	mu sync.Mutex
+	// Authorization header -> rate limiter, limits belong to a token
+	limiters  map[string]*tokenLimiter
+	lastSweep time.Time
+}
+
+type tokenLimiter struct {
+	limiter interfaces.RateLimiter
+	// requests going through the limiter, it isn't dropped while there are some
+	inFlight int
+	lastUsed time.Time
+}
+
+type ProxyOptions struct {
+	// discord base URL, e.g. requester.APIBaseURL
+	Upstream string
+	// builds the rate limiter of each token seen by the proxy
+	NewLimiter func() interfaces.RateLimiter
+	// required, processes send it in the domain.RateLimitProxySecretHeader header
+	Secret string
+	// the rate limiter of a token that sent nothing for this long is dropped, defaults to 10 minutes
+	IdleTimeout time.Duration
+}
+
+func NewProxy(options *ProxyOptions) (*ProxyImpl, error) {
+	if options.Secret == "" {
+		return nil, errors.New("[RateLimitProxy] a secret is required")
+	}
+	idleTimeout := options.IdleTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = defaultIdleTimeout
+	}
+	return &ProxyImpl{
+		upstream:    strings.TrimSuffix(options.Upstream, "/"),
+		client:      &http.Client{Timeout: 30 * time.Second},
+		newLimiter:  options.NewLimiter,
+		secret:      []byte(options.Secret),
+		idleTimeout: idleTimeout,
+		limiters:    make(map[string]*tokenLimiter),
+		lastSweep:   time.Now(),
+	}, nil
+}
+
+// acquireLimiter returns the rate limiter of a token, release must be called once the request is over.
+func (p *ProxyImpl) acquireLimiter(authorization string) (limiter interfaces.RateLimiter, release func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(p.lastSweep) >= p.idleTimeout {
+		p.sweep(now)
+	}
+
+	l, ok := p.limiters[authorization]
+	if !ok {
+		l = &tokenLimiter{limiter: p.newLimiter()}
+		p.limiters[authorization] = l
+	}
+	l.inFlight++
+	return l.limiter, func() {
+		p.mu.Lock()
+		l.inFlight--
+		l.lastUsed = time.Now()
+		p.mu.Unlock()
+	}
+}
+
+// sweep drops the limiters idle for idleTimeout, it must be called with p.mu held.
+func (p *ProxyImpl) sweep(now time.Time) {
+	p.lastSweep = now
+	for authorization, l := range p.limiters {
+		if l.inFlight == 0 && now.Sub(l.lastUsed) >= p.idleTimeout {
+			delete(p.limiters, authorization)
+		}
+	}
+}
+
+func (p *ProxyImpl) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(domain.RateLimitProxySecretHeader)), p.secret) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	route := domain.NewRoute(r.Method, r.URL.EscapedPath())
+	limiter, release := p.acquireLimiter(r.Header.Get("Authorization"))
+	defer release()
+
+	if err := limiter.Wait(r.Context(), route); err != nil {
+		// the client is likely gone, the status is still written so the request isn't logged as a 200
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			http.Error(w, "timed out waiting for the rate limit", http.StatusGatewayTimeout)
+		case errors.Is(err, context.Canceled):
+			http.Error(w, "gave up waiting for the rate limit", statusClientClosedRequest)
+		default:
+			log.Printf("[RateLimitProxy] Failed to wait for the rate limit of %s: %v", route.Key(), err)
+			http.Error(w, "rate limiter unavailable", http.StatusServiceUnavailable)
+		}
+		return
+	}
+
+	target := p.upstream + r.URL.EscapedPath()
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, target, r.Body)
+	if err != nil {
+		log.Printf("[RateLimitProxy] Failed to create upstream request: %v", err)
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	req.ContentLength = r.ContentLength
+	req.Header = r.Header.Clone()
+	req.Header.Del(domain.RateLimitProxySecretHeader)
+	removeHopHeaders(req.Header)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		log.Printf("[RateLimitProxy] %s %s failed: %v", r.Method, r.URL.Path, err)
+		http.Error(w, "upstream unavailable", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	if limit, bucket := domain.ParseRateLimitHeaders(resp.Header); limit != nil {
+		limiter.UpdateLimit(route, bucket, limit)
+	}
+
+	var body io.Reader = resp.Body
+	if resp.StatusCode == http.StatusTooManyRequests {
+		// the body says how long to back off, it has to be read before being passed along
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			http.Error(w, "upstream unavailable", http.StatusBadGateway)
+			return
+		}
+		p.recordTooManyRequests(limiter, route, resp.Header, data)
+		body = bytes.NewReader(data)
+	}
+
+	header := w.Header()
+	for key, values := range resp.Header {
+		header[key] = values
+	}
+	removeHopHeaders(header)
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, body)
+}
+
+func (p *ProxyImpl) recordTooManyRequests(limiter interfaces.RateLimiter, route domain.Route, headers http.Header, body []byte) {
+	var rateLimitResponse domain.RateLimitResponse
+	if err := json.Unmarshal(body, &rateLimitResponse); err != nil {
+		log.Printf("[RateLimitProxy] Hit rate limit but failed to parse response: %v", err)
+		return
+	}
+
+	log.Printf("[RateLimitProxy] Rate limited on %s (global: %v), retry after %.2fs",
+		route.Key(), rateLimitResponse.Global, rateLimitResponse.RetryAfter)
+
+	limiter.UpdateLimit(route, headers.Get("X-RateLimit-Bucket"), &domain.RateLimit{
+		Remaining:  0,
+		ResetAfter: time.Duration(rateLimitResponse.RetryAfter * float64(time.Second)),
+		Global:     rateLimitResponse.Global,
+	})
+}
+
+func removeHopHeaders(header http.Header) {
+	for _, h := range hopHeaders {
+		header.Del(h)
+	}
+}
@@ -0,0 +1,223 @@
+package ratelimitproxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/implementation/ratelimiter"
+	"github.com/marouane-souiri/vocalize/internal/implementation/ratelimiter/ratelimitertest"
+	"github.com/marouane-souiri/vocalize/internal/implementation/requester"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+const testSecret = "test_secret"
+
+// newProcess is a requester as a bot process running behind the proxy would build it.
+func newProcess(proxyURL, token string) interfaces.APIRequester {
+	return requester.NewAPIRequester(&requester.APIRequesterOptions{
+		Token:       token,
+		RateLimiter: ratelimiter.NewNoopRateLimiter(),
+		BaseURL:     proxyURL,
+		ProxySecret: testSecret,
+	})
+}
+
+func newTestProxy(t *testing.T, discord *ratelimitertest.FakeDiscord) *httptest.Server {
+	srv, _ := newTestProxyWithTimeout(t, discord, 0)
+	return srv
+}
+
+func newTestProxyWithTimeout(t *testing.T, discord *ratelimitertest.FakeDiscord, idleTimeout time.Duration) (*httptest.Server, *ProxyImpl) {
+	upstream := httptest.NewServer(discord)
+	t.Cleanup(upstream.Close)
+
+	proxy, err := NewProxy(&ProxyOptions{
+		Upstream: upstream.URL,
+		NewLimiter: func() interfaces.RateLimiter {
+			return ratelimiter.NewRateLimiter()
+		},
+		Secret:      testSecret,
+		IdleTimeout: idleTimeout,
+	})
+	if err != nil {
+		t.Fatalf("NewProxy failed: %v", err)
+	}
+	srv := httptest.NewServer(proxy)
+	t.Cleanup(srv.Close)
+	return srv, proxy
+}
+
+func TestProxy_SharesLimitsAcrossProcesses(t *testing.T) {
+	discord := ratelimitertest.NewFakeDiscord(3, 300*time.Millisecond)
+	proxy := newTestProxy(t, discord)
+	ctx := context.Background()
+
+	first := newProcess(proxy.URL, "test_token")
+	second := newProcess(proxy.URL, "test_token")
+
+	// the proxy learns the bucket from the first answer
+	message, err := first.SendMessage(ctx, "1", &domain.SendMessage{Content: "hi"})
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if message.Content != "hi" {
+		t.Fatalf("Expected the response body to be passed along, got %+v", message)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 12)
+	for i := range 12 {
+		process := first
+		if i%2 == 1 {
+			process = second
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := process.SendMessage(ctx, "1", &domain.SendMessage{Content: "hi"}); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	requests, tooMany := discord.Stats()
+	if tooMany != 0 {
+		t.Fatalf("Expected the processes to share the bucket, got %d 429s out of %d requests", tooMany, requests)
+	}
+	if requests != 13 {
+		t.Fatalf("Expected 13 requests to reach discord, got %d", requests)
+	}
+}
+
+func TestProxy_ForwardsAuthorization(t *testing.T) {
+	discord := ratelimitertest.NewFakeDiscord(5, time.Second)
+	proxy := newTestProxy(t, discord)
+
+	if _, err := newProcess(proxy.URL, "test_token").SendMessage(context.Background(), "1", &domain.SendMessage{Content: "hi"}); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if !discord.SawToken("Bot test_token") {
+		t.Fatalf("Expected the Authorization header to reach discord")
+	}
+}
+
+func TestProxy_RelaysRateLimitErrors(t *testing.T) {
+	discord := ratelimitertest.NewFakeDiscord(1, 5*time.Second)
+	proxy := newTestProxy(t, discord)
+
+	// a process not going through the proxy uses up the bucket behind its back
+	discord.UseUp("messages", "1", 5*time.Second)
+
+	_, err := newProcess(proxy.URL, "test_token").SendMessage(context.Background(), "1", &domain.SendMessage{Content: "hi"})
+	if !domain.IsRateLimited(err) {
+		t.Fatalf("Expected a RateLimitError, got %v", err)
+	}
+}
+
+func TestProxy_RefusesRequestsWithoutSecret(t *testing.T) {
+	discord := ratelimitertest.NewFakeDiscord(5, time.Second)
+	proxy := newTestProxy(t, discord)
+
+	api := requester.NewAPIRequester(&requester.APIRequesterOptions{
+		Token:       "test_token",
+		RateLimiter: ratelimiter.NewNoopRateLimiter(),
+		BaseURL:     proxy.URL,
+		ProxySecret: "wrong",
+	})
+	_, err := api.SendMessage(context.Background(), "1", &domain.SendMessage{Content: "hi"})
+	if apiErr, ok := err.(*domain.APIError); !ok || apiErr.Status != http.StatusUnauthorized {
+		t.Fatalf("Expected a 401, got %v", err)
+	}
+	if requests, _ := discord.Stats(); requests != 0 {
+		t.Fatalf("Expected nothing to reach discord, got %d requests", requests)
+	}
+
+	if _, err := NewProxy(&ProxyOptions{Upstream: "http://discord"}); err == nil {
+		t.Fatalf("Expected a proxy without secret to be refused")
+	}
+}
+
+func TestProxy_DropsIdleLimiters(t *testing.T) {
+	discord := ratelimitertest.NewFakeDiscord(5, time.Second)
+	srv, proxy := newTestProxyWithTimeout(t, discord, 50*time.Millisecond)
+	ctx := context.Background()
+
+	for _, token := range []string{"a", "b", "c"} {
+		if _, err := newProcess(srv.URL, token).SendMessage(ctx, "1", &domain.SendMessage{Content: "hi"}); err != nil {
+			t.Fatalf("SendMessage failed: %v", err)
+		}
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, err := newProcess(srv.URL, "d").SendMessage(ctx, "1", &domain.SendMessage{Content: "hi"}); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	proxy.mu.Lock()
+	defer proxy.mu.Unlock()
+	if len(proxy.limiters) != 1 || proxy.limiters["Bot d"] == nil {
+		t.Fatalf("Expected only the limiter of the last token to be left, got %d", len(proxy.limiters))
+	}
+}
+
+// waitingLimiter makes every request wait until its context is done, or fails them with err.
+type waitingLimiter struct {
+	interfaces.RateLimiter
+	err error
+}
+
+func (l *waitingLimiter) Wait(ctx context.Context, route domain.Route) error {
+	if l.err != nil {
+		return l.err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestProxy_AnswersWhenWaitingFails(t *testing.T) {
+	limiter := &waitingLimiter{}
+	proxy, err := NewProxy(&ProxyOptions{
+		Upstream:   "http://discord.invalid",
+		NewLimiter: func() interfaces.RateLimiter { return limiter },
+		Secret:     testSecret,
+	})
+	if err != nil {
+		t.Fatalf("NewProxy failed: %v", err)
+	}
+
+	serve := func(ctx context.Context) *httptest.ResponseRecorder {
+		r := httptest.NewRequestWithContext(ctx, "POST", "/channels/1/messages", nil)
+		r.Header.Set(domain.RateLimitProxySecretHeader, testSecret)
+		w := httptest.NewRecorder()
+		proxy.ServeHTTP(w, r)
+		return w
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if w := serve(ctx); w.Code != statusClientClosedRequest || w.Body.Len() == 0 {
+		t.Fatalf("Expected a 499 with a body when the client gave up, got %d %q", w.Code, w.Body)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if w := serve(ctx); w.Code != http.StatusGatewayTimeout || w.Body.Len() == 0 {
+		t.Fatalf("Expected a 504 with a body on a deadline, got %d %q", w.Code, w.Body)
+	}
+
+	limiter.err = errors.New("broken")
+	if w := serve(context.Background()); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected a 503 when the limiter failed, got %d %q", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,109 @@
+package reactionsmanager
+
+import (
+	"sync"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/interfaces"
+)
+
+type watchedMessage struct {
+	listener *domain.ReactionListener
+	timeout  time.Duration
+	timer    *time.Timer
+}
+
+type ReactionsManagerImpl struct {
+	listeners map[string]*watchedMessage
+	mu        sync.RWMutex
+}
+
+func NewReactionsManager() interfaces.ReactionsManager {
+	return &ReactionsManagerImpl{
+		listeners: make(map[string]*watchedMessage),
+	}
+}
+
+func (r *ReactionsManagerImpl) Watch(messageID string, timeout time.Duration, listener *domain.ReactionListener) {
+	watched := &watchedMessage{
+		listener: listener,
+		timeout:  timeout,
+	}
+
+	r.mu.Lock()
+	if previous, ok := r.listeners[messageID]; ok {
+		previous.timer.Stop()
+	}
+	watched.timer = time.AfterFunc(timeout, func() { r.expire(messageID, watched) })
+	r.listeners[messageID] = watched
+	r.mu.Unlock()
+}
+
+// expire drops the listener of a message nobody reacted to for its timeout.
+func (r *ReactionsManagerImpl) expire(messageID string, watched *watchedMessage) {
+	r.mu.Lock()
+	if r.listeners[messageID] != watched {
+		// watched again in the meantime
+		r.mu.Unlock()
+		return
+	}
+	delete(r.listeners, messageID)
+	r.mu.Unlock()
+
+	if watched.listener.OnExpire != nil {
+		watched.listener.OnExpire()
+	}
+}
+
+func (r *ReactionsManagerImpl) Unwatch(messageID string) {
+	r.mu.Lock()
+	if watched, ok := r.listeners[messageID]; ok {
+		watched.timer.Stop()
+		delete(r.listeners, messageID)
+	}
+	r.mu.Unlock()
+}
+
+func (r *ReactionsManagerImpl) IsWatched(messageID string) bool {
+	r.mu.RLock()
+	_, ok := r.listeners[messageID]
+	r.mu.RUnlock()
+	return ok
+}
+
+func (r *ReactionsManagerImpl) getListener(messageID string) (*domain.ReactionListener, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	watched, ok := r.listeners[messageID]
+	if !ok {
+		return nil, false
+	}
+	// the timeout counts from the last reaction
+	watched.timer.Reset(watched.timeout)
+	return watched.listener, true
+}
+
+func (r *ReactionsManagerImpl) HandleReactionAdd(event domain.MessageReactionAddEvent) {
+	if listener, ok := r.getListener(event.MessageID); ok && listener.OnAdd != nil {
+		listener.OnAdd(event)
+	}
+}
+
+func (r *ReactionsManagerImpl) HandleReactionRemove(event domain.MessageReactionRemoveEvent) {
+	if listener, ok := r.getListener(event.MessageID); ok && listener.OnRemove != nil {
+		listener.OnRemove(event)
+	}
+}
+
+func (r *ReactionsManagerImpl) HandleReactionRemoveAll(event domain.MessageReactionRemoveAllEvent) {
+	if listener, ok := r.getListener(event.MessageID); ok && listener.OnRemoveAll != nil {
+		listener.OnRemoveAll(event)
+	}
+}
+
+func (r *ReactionsManagerImpl) HandleReactionRemoveEmoji(event domain.MessageReactionRemoveEmojiEvent) {
+	if listener, ok := r.getListener(event.MessageID); ok && listener.OnRemoveEmoji != nil {
+		listener.OnRemoveEmoji(event)
+	}
+}
@@ -0,0 +1,84 @@
+package reactionsmanager
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+const messageID = "100000000000000001"
+
+func TestReactionsManager_Dispatch(t *testing.T) {
+	manager := NewReactionsManager()
+	var calls []string
+	manager.Watch(messageID, time.Minute, &domain.ReactionListener{
+		OnAdd:         func(event domain.MessageReactionAddEvent) { calls = append(calls, "add "+event.UserID) },
+		OnRemove:      func(event domain.MessageReactionRemoveEvent) { calls = append(calls, "remove "+event.UserID) },
+		OnRemoveEmoji: func(event domain.MessageReactionRemoveEmojiEvent) { calls = append(calls, "remove emoji") },
+	})
+
+	manager.HandleReactionAdd(domain.MessageReactionAddEvent{MessageID: messageID, UserID: "u1"})
+	manager.HandleReactionRemove(domain.MessageReactionRemoveEvent{MessageID: messageID, UserID: "u1"})
+	// no callback for it
+	manager.HandleReactionRemoveAll(domain.MessageReactionRemoveAllEvent{MessageID: messageID})
+	manager.HandleReactionRemoveEmoji(domain.MessageReactionRemoveEmojiEvent{MessageID: messageID})
+	// other messages aren't watched
+	manager.HandleReactionAdd(domain.MessageReactionAddEvent{MessageID: "100000000000000002", UserID: "u2"})
+
+	if len(calls) != 3 || calls[0] != "add u1" || calls[1] != "remove u1" || calls[2] != "remove emoji" {
+		t.Fatalf("Expected the reactions of the watched message, got %v", calls)
+	}
+
+	// watching again replaces the listener
+	manager.Watch(messageID, time.Minute, &domain.ReactionListener{
+		OnRemoveAll: func(event domain.MessageReactionRemoveAllEvent) { calls = append(calls, "remove all") },
+	})
+	manager.HandleReactionAdd(domain.MessageReactionAddEvent{MessageID: messageID})
+	manager.HandleReactionRemoveAll(domain.MessageReactionRemoveAllEvent{MessageID: messageID})
+	if len(calls) != 4 || calls[3] != "remove all" {
+		t.Fatalf("Expected only the new listener to run, got %v", calls)
+	}
+
+	manager.Unwatch(messageID)
+	manager.HandleReactionRemoveAll(domain.MessageReactionRemoveAllEvent{MessageID: messageID})
+	if manager.IsWatched(messageID) || len(calls) != 4 {
+		t.Fatalf("Expected the listener to be gone, got %v", calls)
+	}
+}
+
+func TestReactionsManager_Expires(t *testing.T) {
+	manager := NewReactionsManager()
+	expired := make(chan struct{})
+	var adds atomic.Int32
+	manager.Watch(messageID, 50*time.Millisecond, &domain.ReactionListener{
+		OnAdd:    func(event domain.MessageReactionAddEvent) { adds.Add(1) },
+		OnExpire: func() { close(expired) },
+	})
+
+	// reactions push the expiry back
+	for range 4 {
+		time.Sleep(20 * time.Millisecond)
+		manager.HandleReactionAdd(domain.MessageReactionAddEvent{MessageID: messageID})
+	}
+	if !manager.IsWatched(messageID) || adds.Load() != 4 {
+		t.Fatalf("Expected the listener to be kept while used, got %d reactions", adds.Load())
+	}
+
+	select {
+	case <-expired:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the listener to expire")
+	}
+	if manager.IsWatched(messageID) {
+		t.Fatalf("Expected the expired listener to be dropped")
+	}
+
+	// unwatched listeners don't expire
+	manager.Watch(messageID, 10*time.Millisecond, &domain.ReactionListener{
+		OnExpire: func() { t.Errorf("Expected an unwatched listener not to expire") },
+	})
+	manager.Unwatch(messageID)
+	time.Sleep(30 * time.Millisecond)
+}
@@ -0,0 +1,76 @@
+package requester
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// commandsEndpoint is where the global commands live, or the commands of guildID when it's set.
+func commandsEndpoint(applicationID, guildID string) string {
+	if guildID == "" {
+		return fmt.Sprintf("/applications/%s/commands", applicationID)
+	}
+	return fmt.Sprintf("/applications/%s/guilds/%s/commands", applicationID, guildID)
+}
+
+// https://discord.com/developers/docs/interactions/application-commands#get-global-application-commands
+// https://discord.com/developers/docs/interactions/application-commands#get-guild-application-commands
+//
+// An empty guildID gets the global commands.
+func (api *APIRequesterImpl) GetApplicationCommands(ctx context.Context, applicationID, guildID string) ([]domain.ApplicationCommand, error) {
+	// localizations are left out unless asked for, they are needed to compare commands
+	endpoint := commandsEndpoint(applicationID, guildID) + "?with_localizations=true"
+	var commands []domain.ApplicationCommand
+	if err := api.BaseReq(ctx, "GET", endpoint, nil, &commands); err != nil {
+		return nil, err
+	}
+	return commands, nil
+}
+
+// https://discord.com/developers/docs/interactions/application-commands#create-global-application-command
+// https://discord.com/developers/docs/interactions/application-commands#create-guild-application-command
+//
+// Creating a command with the name of an existing one replaces it.
+func (api *APIRequesterImpl) CreateApplicationCommand(ctx context.Context, applicationID, guildID string, command *domain.ApplicationCommand) (*domain.ApplicationCommand, error) {
+	var created domain.ApplicationCommand
+	if err := api.BaseReq(ctx, "POST", commandsEndpoint(applicationID, guildID), command, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// https://discord.com/developers/docs/interactions/application-commands#edit-global-application-command
+// https://discord.com/developers/docs/interactions/application-commands#edit-guild-application-command
+func (api *APIRequesterImpl) EditApplicationCommand(ctx context.Context, applicationID, guildID, commandID string, command *domain.ApplicationCommand) (*domain.ApplicationCommand, error) {
+	endpoint := commandsEndpoint(applicationID, guildID) + "/" + commandID
+	var edited domain.ApplicationCommand
+	if err := api.BaseReq(ctx, "PATCH", endpoint, command, &edited); err != nil {
+		return nil, err
+	}
+	return &edited, nil
+}
+
+// https://discord.com/developers/docs/interactions/application-commands#delete-global-application-command
+// https://discord.com/developers/docs/interactions/application-commands#delete-guild-application-command
+func (api *APIRequesterImpl) DeleteApplicationCommand(ctx context.Context, applicationID, guildID, commandID string) error {
+	endpoint := commandsEndpoint(applicationID, guildID) + "/" + commandID
+	return api.BaseReq(ctx, "DELETE", endpoint, nil, nil)
+}
+
+// https://discord.com/developers/docs/interactions/application-commands#bulk-overwrite-global-application-commands
+// https://discord.com/developers/docs/interactions/application-commands#bulk-overwrite-guild-application-commands
+//
+// Commands missing from the list are deleted.
+func (api *APIRequesterImpl) BulkOverwriteApplicationCommands(ctx context.Context, applicationID, guildID string, commands []domain.ApplicationCommand) ([]domain.ApplicationCommand, error) {
+	if commands == nil {
+		// discord expects an array, null is rejected
+		commands = []domain.ApplicationCommand{}
+	}
+	var overwritten []domain.ApplicationCommand
+	if err := api.BaseReq(ctx, "PUT", commandsEndpoint(applicationID, guildID), commands, &overwritten); err != nil {
+		return nil, err
+	}
+	return overwritten, nil
+}
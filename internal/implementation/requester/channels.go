@@ -0,0 +1,81 @@
+package requester
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// https://discord.com/developers/docs/resources/channel#get-channel
+func (api *APIRequesterImpl) GetChannel(ctx context.Context, channelID string) (*domain.Channel, error) {
+	endpoint := fmt.Sprintf("/channels/%s", channelID)
+	var channel domain.Channel
+	if err := api.BaseReq(ctx, "GET", endpoint, nil, &channel); err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
+
+// https://discord.com/developers/docs/resources/channel#trigger-typing-indicator
+//
+// The indicator lasts 10 seconds or until the bot sends a message.
+func (api *APIRequesterImpl) TriggerTypingIndicator(ctx context.Context, channelID string) error {
+	endpoint := fmt.Sprintf("/channels/%s/typing", channelID)
+	return api.BaseReq(ctx, "POST", endpoint, nil, nil)
+}
+
+// https://discord.com/developers/docs/resources/guild#get-guild-channels
+func (api *APIRequesterImpl) GetGuildChannels(ctx context.Context, guildID string) ([]domain.Channel, error) {
+	endpoint := fmt.Sprintf("/guilds/%s/channels", guildID)
+	var channels []domain.Channel
+	if err := api.BaseReq(ctx, "GET", endpoint, nil, &channels); err != nil {
+		return nil, err
+	}
+	return channels, nil
+}
+
+// https://discord.com/developers/docs/resources/guild#create-guild-channel
+func (api *APIRequesterImpl) CreateGuildChannel(ctx context.Context, guildID string, channel *domain.CreateChannel, reason string) (*domain.Channel, error) {
+	endpoint := fmt.Sprintf("/guilds/%s/channels", guildID)
+	var created domain.Channel
+	if err := api.BaseReqWithReason(ctx, "POST", endpoint, reason, channel, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// https://discord.com/developers/docs/resources/channel#modify-channel
+func (api *APIRequesterImpl) ModifyChannel(ctx context.Context, channelID string, channel *domain.ModifyChannel, reason string) (*domain.Channel, error) {
+	endpoint := fmt.Sprintf("/channels/%s", channelID)
+	var modified domain.Channel
+	if err := api.BaseReqWithReason(ctx, "PATCH", endpoint, reason, channel, &modified); err != nil {
+		return nil, err
+	}
+	return &modified, nil
+}
+
+// https://discord.com/developers/docs/resources/channel#deleteclose-channel
+func (api *APIRequesterImpl) DeleteChannel(ctx context.Context, channelID, reason string) error {
+	endpoint := fmt.Sprintf("/channels/%s", channelID)
+	return api.BaseReqWithReason(ctx, "DELETE", endpoint, reason, nil, nil)
+}
+
+// https://discord.com/developers/docs/resources/channel#edit-channel-permissions
+//
+// The overwrite is created or replaced, its ID is the role or member it targets.
+func (api *APIRequesterImpl) EditChannelPermissions(ctx context.Context, channelID string, overwrite *domain.PermissionOverwrite, reason string) error {
+	endpoint := fmt.Sprintf("/channels/%s/permissions/%s", channelID, overwrite.ID)
+	body := struct {
+		Allow domain.Permissions             `json:"allow"`
+		Deny  domain.Permissions             `json:"deny"`
+		Type  domain.PermissionOverwriteType `json:"type"`
+	}{Allow: overwrite.Allow, Deny: overwrite.Deny, Type: overwrite.Type}
+	return api.BaseReqWithReason(ctx, "PUT", endpoint, reason, body, nil)
+}
+
+// https://discord.com/developers/docs/resources/channel#delete-channel-permission
+func (api *APIRequesterImpl) DeleteChannelPermission(ctx context.Context, channelID, overwriteID, reason string) error {
+	endpoint := fmt.Sprintf("/channels/%s/permissions/%s", channelID, overwriteID)
+	return api.BaseReqWithReason(ctx, "DELETE", endpoint, reason, nil, nil)
+}
@@ -0,0 +1,63 @@
+package requester
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// https://discord.com/developers/docs/reference#error-messages
+func parseAPIError(status int, body []byte) *domain.APIError {
+	apiErr := &domain.APIError{Status: status}
+
+	var resp struct {
+		Code    domain.APIErrorCode `json:"code"`
+		Message string              `json:"message"`
+		Errors  json.RawMessage     `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		// not JSON, e.g. an HTML page from a proxy in front of discord
+		apiErr.Message = strings.TrimSpace(string(body))
+		return apiErr
+	}
+
+	apiErr.Code = resp.Code
+	apiErr.Message = resp.Message
+	if len(resp.Errors) > 0 {
+		apiErr.Errors = make(map[string][]domain.APIFieldError)
+		flattenFieldErrors(resp.Errors, "", apiErr.Errors)
+	}
+	return apiErr
+}
+
+// flattenFieldErrors walks the nested error object, every "_errors" list is stored under its dotted path.
+func flattenFieldErrors(raw json.RawMessage, path string, into map[string][]domain.APIFieldError) {
+	var node map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return
+	}
+
+	keys := make([]string, 0, len(node))
+	for key := range node {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if key == "_errors" {
+			var fieldErrs []domain.APIFieldError
+			if err := json.Unmarshal(node[key], &fieldErrs); err == nil {
+				into[path] = append(into[path], fieldErrs...)
+			}
+			continue
+		}
+
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+		flattenFieldErrors(node[key], childPath, into)
+	}
+}
@@ -0,0 +1,80 @@
+package requester
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// https://discord.com/developers/docs/resources/guild#get-guild
+func (api *APIRequesterImpl) GetGuild(ctx context.Context, guildID string) (*domain.Guild, error) {
+	endpoint := fmt.Sprintf("/guilds/%s", guildID)
+	var guild domain.Guild
+	if err := api.BaseReq(ctx, "GET", endpoint, nil, &guild); err != nil {
+		return nil, err
+	}
+	return &guild, nil
+}
+
+// https://discord.com/developers/docs/resources/guild#get-guild-bans
+func (api *APIRequesterImpl) GetGuildBans(ctx context.Context, guildID string, query *domain.BansQuery) ([]domain.Ban, error) {
+	endpoint := fmt.Sprintf("/guilds/%s/bans", guildID)
+
+	if query != nil {
+		params := url.Values{}
+		if query.Before != "" {
+			params.Set("before", query.Before)
+		}
+		if query.After != "" {
+			params.Set("after", query.After)
+		}
+		if query.Limit != 0 {
+			if query.Limit < 1 || query.Limit > 1000 {
+				return nil, fmt.Errorf("[Requester] bans limit must be between 1 and 1000, got %d", query.Limit)
+			}
+			params.Set("limit", strconv.Itoa(query.Limit))
+		}
+		if len(params) > 0 {
+			endpoint += "?" + params.Encode()
+		}
+	}
+
+	var bans []domain.Ban
+	if err := api.BaseReq(ctx, "GET", endpoint, nil, &bans); err != nil {
+		return nil, err
+	}
+	return bans, nil
+}
+
+// https://discord.com/developers/docs/resources/guild#get-guild-ban
+func (api *APIRequesterImpl) GetGuildBan(ctx context.Context, guildID, userID string) (*domain.Ban, error) {
+	endpoint := fmt.Sprintf("/guilds/%s/bans/%s", guildID, userID)
+	var ban domain.Ban
+	if err := api.BaseReq(ctx, "GET", endpoint, nil, &ban); err != nil {
+		return nil, err
+	}
+	return &ban, nil
+}
+
+// https://discord.com/developers/docs/resources/guild#create-guild-ban
+//
+// deleteMessageSeconds (0-604800) deletes the messages the user sent in that window.
+func (api *APIRequesterImpl) CreateGuildBan(ctx context.Context, guildID, userID string, deleteMessageSeconds int, reason string) error {
+	if deleteMessageSeconds < 0 || deleteMessageSeconds > 604800 {
+		return fmt.Errorf("[Requester] delete message seconds must be between 0 and 604800, got %d", deleteMessageSeconds)
+	}
+	endpoint := fmt.Sprintf("/guilds/%s/bans/%s", guildID, userID)
+	body := struct {
+		DeleteMessageSeconds int `json:"delete_message_seconds,omitempty"`
+	}{DeleteMessageSeconds: deleteMessageSeconds}
+	return api.BaseReqWithReason(ctx, "PUT", endpoint, reason, body, nil)
+}
+
+// https://discord.com/developers/docs/resources/guild#remove-guild-ban
+func (api *APIRequesterImpl) RemoveGuildBan(ctx context.Context, guildID, userID, reason string) error {
+	endpoint := fmt.Sprintf("/guilds/%s/bans/%s", guildID, userID)
+	return api.BaseReqWithReason(ctx, "DELETE", endpoint, reason, nil, nil)
+}
@@ -0,0 +1,82 @@
+package requester
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#create-interaction-response
+//
+// The initial response must be sent within 3 seconds of receiving the interaction, and only once.
+func (api *APIRequesterImpl) CreateInteractionResponse(ctx context.Context, interactionID, token string, response *domain.InteractionResponse) error {
+	endpoint := fmt.Sprintf("/interactions/%s/%s/callback", interactionID, token)
+	if response.Data != nil && len(response.Data.Files) > 0 && len(response.Data.Attachments) == 0 {
+		withAttachments := *response.Data
+		withAttachments.Attachments = attachmentsFromFiles(response.Data.Files)
+		response = &domain.InteractionResponse{Type: response.Type, Data: &withAttachments}
+	}
+	return api.BaseReq(ctx, "POST", endpoint, response, nil)
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#get-original-interaction-response
+func (api *APIRequesterImpl) GetOriginalInteractionResponse(ctx context.Context, applicationID, token string) (*domain.Message, error) {
+	endpoint := fmt.Sprintf("/webhooks/%s/%s/messages/@original", applicationID, token)
+	var message domain.Message
+	if err := api.BaseReq(ctx, "GET", endpoint, nil, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#edit-original-interaction-response
+//
+// Also how a deferred response gets its content.
+func (api *APIRequesterImpl) EditOriginalInteractionResponse(ctx context.Context, applicationID, token string, message *domain.EditMessage) (*domain.Message, error) {
+	endpoint := fmt.Sprintf("/webhooks/%s/%s/messages/@original", applicationID, token)
+	var edited domain.Message
+	if err := api.BaseReq(ctx, "PATCH", endpoint, message, &edited); err != nil {
+		return nil, err
+	}
+	return &edited, nil
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#delete-original-interaction-response
+func (api *APIRequesterImpl) DeleteOriginalInteractionResponse(ctx context.Context, applicationID, token string) error {
+	endpoint := fmt.Sprintf("/webhooks/%s/%s/messages/@original", applicationID, token)
+	return api.BaseReq(ctx, "DELETE", endpoint, nil, nil)
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#create-followup-message
+//
+// Follow ups can be sent for 15 minutes after the interaction was received.
+func (api *APIRequesterImpl) CreateFollowupMessage(ctx context.Context, applicationID, token string, message *domain.InteractionResponseData) (*domain.Message, error) {
+	endpoint := fmt.Sprintf("/webhooks/%s/%s", applicationID, token)
+	if len(message.Files) > 0 && len(message.Attachments) == 0 {
+		withAttachments := *message
+		withAttachments.Attachments = attachmentsFromFiles(message.Files)
+		message = &withAttachments
+	}
+	var created domain.Message
+	if err := api.BaseReq(ctx, "POST", endpoint, message, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#edit-followup-message
+func (api *APIRequesterImpl) EditFollowupMessage(ctx context.Context, applicationID, token, messageID string, message *domain.EditMessage) (*domain.Message, error) {
+	endpoint := fmt.Sprintf("/webhooks/%s/%s/messages/%s", applicationID, token, messageID)
+	var edited domain.Message
+	if err := api.BaseReq(ctx, "PATCH", endpoint, message, &edited); err != nil {
+		return nil, err
+	}
+	return &edited, nil
+}
+
+// https://discord.com/developers/docs/interactions/receiving-and-responding#delete-followup-message
+func (api *APIRequesterImpl) DeleteFollowupMessage(ctx context.Context, applicationID, token, messageID string) error {
+	endpoint := fmt.Sprintf("/webhooks/%s/%s/messages/%s", applicationID, token, messageID)
+	return api.BaseReq(ctx, "DELETE", endpoint, nil, nil)
+}
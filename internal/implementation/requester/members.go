@@ -0,0 +1,74 @@
+package requester
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// members returned by the API only carry their user object,
+// the cache keys them by ID and guild ID.
+func fillMemberIDs(member *domain.Member, guildID string) {
+	member.GuildID = guildID
+	if member.User != nil {
+		member.ID = member.User.ID
+	}
+}
+
+// https://discord.com/developers/docs/resources/guild#get-guild-member
+func (api *APIRequesterImpl) GetGuildMember(ctx context.Context, guildID, userID string) (*domain.Member, error) {
+	endpoint := fmt.Sprintf("/guilds/%s/members/%s", guildID, userID)
+	var member domain.Member
+	if err := api.BaseReq(ctx, "GET", endpoint, nil, &member); err != nil {
+		return nil, err
+	}
+	fillMemberIDs(&member, guildID)
+	return &member, nil
+}
+
+// https://discord.com/developers/docs/resources/guild#modify-guild-member
+func (api *APIRequesterImpl) ModifyGuildMember(ctx context.Context, guildID, userID string, member *domain.ModifyMember, reason string) (*domain.Member, error) {
+	endpoint := fmt.Sprintf("/guilds/%s/members/%s", guildID, userID)
+	var modified domain.Member
+	if err := api.BaseReqWithReason(ctx, "PATCH", endpoint, reason, member, &modified); err != nil {
+		return nil, err
+	}
+	fillMemberIDs(&modified, guildID)
+	return &modified, nil
+}
+
+// https://discord.com/developers/docs/resources/guild#modify-guild-member
+//
+// until can be at most 28 days in the future, nil removes the timeout.
+func (api *APIRequesterImpl) TimeoutGuildMember(ctx context.Context, guildID, userID string, until *time.Time, reason string) (*domain.Member, error) {
+	endpoint := fmt.Sprintf("/guilds/%s/members/%s", guildID, userID)
+	body := struct {
+		CommunicationDisabledUntil *time.Time `json:"communication_disabled_until"`
+	}{CommunicationDisabledUntil: until}
+	var modified domain.Member
+	if err := api.BaseReqWithReason(ctx, "PATCH", endpoint, reason, body, &modified); err != nil {
+		return nil, err
+	}
+	fillMemberIDs(&modified, guildID)
+	return &modified, nil
+}
+
+// https://discord.com/developers/docs/resources/guild#add-guild-member-role
+func (api *APIRequesterImpl) AddGuildMemberRole(ctx context.Context, guildID, userID, roleID, reason string) error {
+	endpoint := fmt.Sprintf("/guilds/%s/members/%s/roles/%s", guildID, userID, roleID)
+	return api.BaseReqWithReason(ctx, "PUT", endpoint, reason, nil, nil)
+}
+
+// https://discord.com/developers/docs/resources/guild#remove-guild-member-role
+func (api *APIRequesterImpl) RemoveGuildMemberRole(ctx context.Context, guildID, userID, roleID, reason string) error {
+	endpoint := fmt.Sprintf("/guilds/%s/members/%s/roles/%s", guildID, userID, roleID)
+	return api.BaseReqWithReason(ctx, "DELETE", endpoint, reason, nil, nil)
+}
+
+// https://discord.com/developers/docs/resources/guild#remove-guild-member
+func (api *APIRequesterImpl) KickGuildMember(ctx context.Context, guildID, userID, reason string) error {
+	endpoint := fmt.Sprintf("/guilds/%s/members/%s", guildID, userID)
+	return api.BaseReqWithReason(ctx, "DELETE", endpoint, reason, nil, nil)
+}
@@ -1,12 +1,162 @@
 package requester
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/marouane-souiri/vocalize/internal/domain"
 )
 
-func (api *APIRequesterImpl) SendMessage(channelID string, message *domain.SendMessage) error {
+const (
+	bulkDeleteMinMessages = 2
+	bulkDeleteMaxMessages = 100
+	bulkDeleteMaxAge      = 14 * 24 * time.Hour
+)
+
+// https://discord.com/developers/docs/resources/message#create-message
+func (api *APIRequesterImpl) SendMessage(ctx context.Context, channelID string, message *domain.SendMessage) (*domain.Message, error) {
 	endpoint := fmt.Sprintf("/channels/%s/messages", channelID)
-	return api.BaseReq("POST", endpoint, message, nil)
+	if len(message.Files) > 0 && len(message.Attachments) == 0 {
+		withAttachments := *message
+		withAttachments.Attachments = attachmentsFromFiles(message.Files)
+		message = &withAttachments
+	}
+	var created domain.Message
+	if err := api.BaseReq(ctx, "POST", endpoint, message, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// https://discord.com/developers/docs/resources/message#edit-message
+func (api *APIRequesterImpl) EditMessage(ctx context.Context, channelID, messageID string, message *domain.EditMessage) (*domain.Message, error) {
+	endpoint := fmt.Sprintf("/channels/%s/messages/%s", channelID, messageID)
+	var edited domain.Message
+	if err := api.BaseReq(ctx, "PATCH", endpoint, message, &edited); err != nil {
+		return nil, err
+	}
+	return &edited, nil
+}
+
+// https://discord.com/developers/docs/resources/message#delete-message
+func (api *APIRequesterImpl) DeleteMessage(ctx context.Context, channelID, messageID string) error {
+	endpoint := fmt.Sprintf("/channels/%s/messages/%s", channelID, messageID)
+	return api.BaseReq(ctx, "DELETE", endpoint, nil, nil)
+}
+
+// https://discord.com/developers/docs/resources/message#bulk-delete-messages
+//
+// Discord only accepts between 2 and 100 messages, all younger than 14 days.
+// The IDs are checked here so we don't burn a request on a guaranteed 400.
+func (api *APIRequesterImpl) BulkDeleteMessages(ctx context.Context, channelID string, messageIDs []string) error {
+	if len(messageIDs) < bulkDeleteMinMessages || len(messageIDs) > bulkDeleteMaxMessages {
+		return fmt.Errorf("[Requester] bulk delete needs between %d and %d messages, got %d",
+			bulkDeleteMinMessages, bulkDeleteMaxMessages, len(messageIDs))
+	}
+
+	oldest := time.Now().Add(-bulkDeleteMaxAge)
+	for _, ID := range messageIDs {
+		createdAt, err := domain.SnowflakeTime(ID)
+		if err != nil {
+			return fmt.Errorf("[Requester] invalid message ID %q: %w", ID, err)
+		}
+		if createdAt.Before(oldest) {
+			return fmt.Errorf("[Requester] message %s is older than 14 days and can't be bulk deleted", ID)
+		}
+	}
+
+	endpoint := fmt.Sprintf("/channels/%s/messages/bulk-delete", channelID)
+	body := struct {
+		Messages []string `json:"messages"`
+	}{Messages: messageIDs}
+	return api.BaseReq(ctx, "POST", endpoint, body, nil)
+}
+
+// https://discord.com/developers/docs/resources/message#get-channel-message
+func (api *APIRequesterImpl) GetMessage(ctx context.Context, channelID, messageID string) (*domain.Message, error) {
+	endpoint := fmt.Sprintf("/channels/%s/messages/%s", channelID, messageID)
+	var message domain.Message
+	if err := api.BaseReq(ctx, "GET", endpoint, nil, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// https://discord.com/developers/docs/resources/message#get-channel-messages
+func (api *APIRequesterImpl) GetChannelMessages(ctx context.Context, channelID string, query *domain.ChannelMessagesQuery) ([]domain.Message, error) {
+	endpoint := fmt.Sprintf("/channels/%s/messages", channelID)
+
+	if query != nil {
+		params := url.Values{}
+		set := 0
+		if query.Before != "" {
+			params.Set("before", query.Before)
+			set++
+		}
+		if query.After != "" {
+			params.Set("after", query.After)
+			set++
+		}
+		if query.Around != "" {
+			params.Set("around", query.Around)
+			set++
+		}
+		if set > 1 {
+			return nil, errors.New("[Requester] before, after and around are mutually exclusive")
+		}
+		if query.Limit != 0 {
+			if query.Limit < 1 || query.Limit > 100 {
+				return nil, fmt.Errorf("[Requester] messages limit must be between 1 and 100, got %d", query.Limit)
+			}
+			params.Set("limit", strconv.Itoa(query.Limit))
+		}
+		if len(params) > 0 {
+			endpoint += "?" + params.Encode()
+		}
+	}
+
+	var messages []domain.Message
+	if err := api.BaseReq(ctx, "GET", endpoint, nil, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// https://discord.com/developers/docs/resources/message#pin-message
+func (api *APIRequesterImpl) PinMessage(ctx context.Context, channelID, messageID string) error {
+	endpoint := fmt.Sprintf("/channels/%s/pins/%s", channelID, messageID)
+	return api.BaseReq(ctx, "PUT", endpoint, nil, nil)
+}
+
+// https://discord.com/developers/docs/resources/message#unpin-message
+func (api *APIRequesterImpl) UnpinMessage(ctx context.Context, channelID, messageID string) error {
+	endpoint := fmt.Sprintf("/channels/%s/pins/%s", channelID, messageID)
+	return api.BaseReq(ctx, "DELETE", endpoint, nil, nil)
+}
+
+// https://discord.com/developers/docs/resources/message#crosspost-message
+func (api *APIRequesterImpl) CrosspostMessage(ctx context.Context, channelID, messageID string) (*domain.Message, error) {
+	endpoint := fmt.Sprintf("/channels/%s/messages/%s/crosspost", channelID, messageID)
+	var message domain.Message
+	if err := api.BaseReq(ctx, "POST", endpoint, nil, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// attachmentsFromFiles describes each uploaded file, discord drops the descriptions otherwise.
+func attachmentsFromFiles(files []*domain.File) []domain.PartialAttachment {
+	attachments := make([]domain.PartialAttachment, len(files))
+	for i, file := range files {
+		attachments[i] = domain.PartialAttachment{
+			ID:          strconv.Itoa(i),
+			Filename:    file.Name,
+			Description: file.Description,
+		}
+	}
+	return attachments
 }
@@ -0,0 +1,176 @@
+package requester
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// snowflakeAt makes a message ID created at t.
+func snowflakeAt(t time.Time) string {
+	return strconv.FormatUint(uint64(t.UnixMilli()-domain.DiscordEpoch)<<22, 10)
+}
+
+func TestRequester_BulkDeleteMessages(t *testing.T) {
+	var requests atomic.Int32
+	var deleted []string
+	api := newTestRequester(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Method != "POST" || r.URL.Path != "/channels/42/messages/bulk-delete" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var body struct {
+			Messages []string `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Invalid body: %v", err)
+		}
+		deleted = body.Messages
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	fresh := func(count int) []string {
+		IDs := make([]string, count)
+		for i := range IDs {
+			IDs[i] = snowflakeAt(time.Now().Add(-time.Duration(i) * time.Minute))
+		}
+		return IDs
+	}
+	old := snowflakeAt(time.Now().Add(-15 * 24 * time.Hour))
+
+	tests := map[string][]string{
+		"one message":        fresh(1),
+		"101 messages":       fresh(101),
+		"older than 14d":     append(fresh(2), old),
+		"not a snowflake":    append(fresh(2), "abc"),
+		"no messages at all": nil,
+	}
+	for name, IDs := range tests {
+		if err := api.BulkDeleteMessages(context.Background(), "42", IDs); err == nil {
+			t.Fatalf("%s: expected the messages to be refused", name)
+		}
+	}
+	if requests.Load() != 0 {
+		t.Fatalf("Expected refused bulk deletes not to be sent, got %d requests", requests.Load())
+	}
+
+	for _, count := range []int{2, 100} {
+		IDs := fresh(count)
+		if err := api.BulkDeleteMessages(context.Background(), "42", IDs); err != nil {
+			t.Fatalf("%d messages: unexpected error: %v", count, err)
+		}
+		if len(deleted) != count || deleted[0] != IDs[0] {
+			t.Fatalf("%d messages: expected them in the body, got %v", count, deleted)
+		}
+	}
+}
+
+func TestRequester_GetChannelMessagesQuery(t *testing.T) {
+	var query string
+	var requests atomic.Int32
+	api := newTestRequester(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		query = r.URL.RawQuery
+		w.Write([]byte(`[{"id":"1"},{"id":"2"}]`))
+	})
+
+	tests := []struct {
+		name  string
+		query *domain.ChannelMessagesQuery
+		want  string
+	}{
+		{"no query", nil, ""},
+		{"empty query", &domain.ChannelMessagesQuery{}, ""},
+		{"before", &domain.ChannelMessagesQuery{Before: "10", Limit: 100}, "before=10&limit=100"},
+		{"after", &domain.ChannelMessagesQuery{After: "10", Limit: 1}, "after=10&limit=1"},
+		{"around", &domain.ChannelMessagesQuery{Around: "10"}, "around=10"},
+	}
+	for _, test := range tests {
+		messages, err := api.GetChannelMessages(context.Background(), "42", test.query)
+		if err != nil || len(messages) != 2 {
+			t.Fatalf("%s: expected the messages, got %v, %v", test.name, messages, err)
+		}
+		if query != test.want {
+			t.Fatalf("%s: expected the query %q, got %q", test.name, test.want, query)
+		}
+	}
+
+	sent := requests.Load()
+	invalid := map[string]*domain.ChannelMessagesQuery{
+		"before and after": {Before: "10", After: "20"},
+		"after and around": {After: "10", Around: "20"},
+		"negative limit":   {Limit: -1},
+		"limit above 100":  {Limit: 101},
+	}
+	for name, query := range invalid {
+		if _, err := api.GetChannelMessages(context.Background(), "42", query); err == nil {
+			t.Fatalf("%s: expected the query to be refused", name)
+		}
+	}
+	if requests.Load() != sent {
+		t.Fatalf("Expected refused queries not to be sent")
+	}
+}
+
+func TestRequester_SendMessageAttachments(t *testing.T) {
+	var payload domain.SendMessage
+	var filenames []string
+	api := newTestRequester(t, func(w http.ResponseWriter, r *http.Request) {
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			t.Errorf("Invalid Content-Type %q", r.Header.Get("Content-Type"))
+			return
+		}
+		reader := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			data, _ := io.ReadAll(part)
+			if part.FormName() == "payload_json" {
+				json.Unmarshal(data, &payload)
+				continue
+			}
+			filenames = append(filenames, part.FileName())
+		}
+		w.Write([]byte(`{"id":"1"}`))
+	})
+
+	// attachments given with the files are sent as is
+	message := &domain.SendMessage{
+		Attachments: []domain.PartialAttachment{{ID: "0", Filename: "renamed.txt", Description: "kept"}},
+		Files:       []*domain.File{{Name: `say "hi".txt`, Description: "dropped", Reader: strings.NewReader("hi")}},
+	}
+	if _, err := api.SendMessage(context.Background(), "42", message); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if len(payload.Attachments) != 1 || payload.Attachments[0].Description != "kept" {
+		t.Fatalf("Expected the given attachments, got %+v", payload.Attachments)
+	}
+	if len(filenames) != 1 || filenames[0] != `say "hi".txt` {
+		t.Fatalf("Expected the quotes of the filename to survive, got %q", filenames)
+	}
+
+	// the attachments made from the files don't change the message of the caller
+	message = &domain.SendMessage{Files: []*domain.File{{Name: "a.txt", Description: "first", Reader: strings.NewReader("a")}}}
+	if _, err := api.SendMessage(context.Background(), "42", message); err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if len(payload.Attachments) != 1 || payload.Attachments[0].ID != "0" || payload.Attachments[0].Description != "first" {
+		t.Fatalf("Expected an attachment made from the file, got %+v", payload.Attachments)
+	}
+	if message.Attachments != nil {
+		t.Fatalf("Expected the message of the caller to be left alone, got %+v", message.Attachments)
+	}
+}
@@ -0,0 +1,101 @@
+package requester
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// https://discord.com/developers/docs/reference#uploading-files
+//
+// encodeMultipart streams body as the payload_json part followed by one files[n] part per file.
+// The form is written through a pipe while the request is being sent,
+// so files are never held whole in memory.
+func encodeMultipart(body any, files []*domain.File) (io.Reader, string, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("[Requester] failed to marshal request body: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	reader := &multipartReader{PipeReader: pr, done: make(chan struct{})}
+
+	go func() {
+		defer close(reader.done)
+		err := writeMultipart(writer, payload, files)
+		if err == nil {
+			err = writer.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return reader, writer.FormDataContentType(), nil
+}
+
+type multipartReader struct {
+	*io.PipeReader
+	done chan struct{}
+}
+
+// Close stops the form writer and waits for it,
+// once it returns the files are no longer read and can be rewound or closed.
+func (r *multipartReader) Close() error {
+	err := r.PipeReader.Close()
+	<-r.done
+	return err
+}
+
+func writeMultipart(writer *multipart.Writer, payload []byte, files []*domain.File) error {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="payload_json"`)
+	header.Set("Content-Type", "application/json")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("[Requester] failed to create payload_json part: %w", err)
+	}
+	if _, err := part.Write(payload); err != nil {
+		return fmt.Errorf("[Requester] failed to write payload_json part: %w", err)
+	}
+
+	for i, file := range files {
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition",
+			fmt.Sprintf(`form-data; name="files[%d]"; filename="%s"`, i, escapeQuotes(file.Name)))
+		header.Set("Content-Type", contentType)
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return fmt.Errorf("[Requester] failed to create files[%d] part: %w", i, err)
+		}
+		if _, err := io.Copy(part, file.Reader); err != nil {
+			return fmt.Errorf("[Requester] failed to write file %q: %w", file.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func closeFiles(files []*domain.File) {
+	for _, file := range files {
+		if closer, ok := file.Reader.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
@@ -0,0 +1,72 @@
+package requester
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+func reactionsEndpoint(channelID, messageID, emoji string) string {
+	return fmt.Sprintf("/channels/%s/messages/%s/reactions/%s", channelID, messageID, url.PathEscape(emoji))
+}
+
+// https://discord.com/developers/docs/resources/message#create-reaction
+//
+// emoji is either the unicode character or "name:id" for custom emojis, see domain.Emoji.APIName.
+func (api *APIRequesterImpl) CreateReaction(ctx context.Context, channelID, messageID, emoji string) error {
+	return api.BaseReq(ctx, "PUT", reactionsEndpoint(channelID, messageID, emoji)+"/@me", nil, nil)
+}
+
+// https://discord.com/developers/docs/resources/message#delete-own-reaction
+func (api *APIRequesterImpl) DeleteOwnReaction(ctx context.Context, channelID, messageID, emoji string) error {
+	return api.BaseReq(ctx, "DELETE", reactionsEndpoint(channelID, messageID, emoji)+"/@me", nil, nil)
+}
+
+// https://discord.com/developers/docs/resources/message#delete-user-reaction
+func (api *APIRequesterImpl) DeleteUserReaction(ctx context.Context, channelID, messageID, emoji, userID string) error {
+	return api.BaseReq(ctx, "DELETE", reactionsEndpoint(channelID, messageID, emoji)+"/"+userID, nil, nil)
+}
+
+// https://discord.com/developers/docs/resources/message#get-reactions
+func (api *APIRequesterImpl) GetReactions(ctx context.Context, channelID, messageID, emoji string, query *domain.ReactionsQuery) ([]domain.User, error) {
+	endpoint := reactionsEndpoint(channelID, messageID, emoji)
+
+	if query != nil {
+		params := url.Values{}
+		if query.Type != domain.ReactionType_NORMAL {
+			params.Set("type", strconv.Itoa(int(query.Type)))
+		}
+		if query.After != "" {
+			params.Set("after", query.After)
+		}
+		if query.Limit != 0 {
+			if query.Limit < 1 || query.Limit > 100 {
+				return nil, fmt.Errorf("[Requester] reactions limit must be between 1 and 100, got %d", query.Limit)
+			}
+			params.Set("limit", strconv.Itoa(query.Limit))
+		}
+		if len(params) > 0 {
+			endpoint += "?" + params.Encode()
+		}
+	}
+
+	var users []domain.User
+	if err := api.BaseReq(ctx, "GET", endpoint, nil, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// https://discord.com/developers/docs/resources/message#delete-all-reactions
+func (api *APIRequesterImpl) DeleteAllReactions(ctx context.Context, channelID, messageID string) error {
+	endpoint := fmt.Sprintf("/channels/%s/messages/%s/reactions", channelID, messageID)
+	return api.BaseReq(ctx, "DELETE", endpoint, nil, nil)
+}
+
+// https://discord.com/developers/docs/resources/message#delete-all-reactions-for-emoji
+func (api *APIRequesterImpl) DeleteAllReactionsForEmoji(ctx context.Context, channelID, messageID, emoji string) error {
+	return api.BaseReq(ctx, "DELETE", reactionsEndpoint(channelID, messageID, emoji), nil, nil)
+}
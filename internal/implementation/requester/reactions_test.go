@@ -0,0 +1,37 @@
+package requester
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestRequester_ReactionEmojiEscaping(t *testing.T) {
+	var path string
+	api := newTestRequester(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := map[string]string{
+		"👍":                "/channels/1/messages/2/reactions/%F0%9F%91%8D/@me",
+		"#️⃣":              "/channels/1/messages/2/reactions/%23%EF%B8%8F%E2%83%A3/@me",
+		"party:1234567890": "/channels/1/messages/2/reactions/party:1234567890/@me",
+		"a/b?c:1234567890": "/channels/1/messages/2/reactions/a%2Fb%3Fc:1234567890/@me",
+	}
+	for emoji, want := range tests {
+		if err := api.CreateReaction(context.Background(), "1", "2", emoji); err != nil {
+			t.Fatalf("%q: unexpected error: %v", emoji, err)
+		}
+		if path != want {
+			t.Fatalf("%q: expected %s, got %s", emoji, want, path)
+		}
+	}
+
+	if err := api.DeleteUserReaction(context.Background(), "1", "2", "👍", "3"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := "/channels/1/messages/2/reactions/%F0%9F%91%8D/3"; path != want {
+		t.Fatalf("Expected %s, got %s", want, path)
+	}
+}
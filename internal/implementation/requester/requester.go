@@ -2,11 +2,14 @@ package requester
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
-	"strconv"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/marouane-souiri/vocalize/internal/domain"
@@ -19,69 +22,153 @@ const (
 
 type APIRequesterImpl struct {
 	client      *http.Client
+	baseURL     string
 	token       string
 	rateLimiter interfaces.RateLimiter
+	maxRetries  int
+	proxySecret string
 }
 
-func NewAPIRequester(token string, rateLimiter interfaces.RateLimiter) interfaces.APIRequester {
+type APIRequesterOptions struct {
+	Token       string
+	RateLimiter interfaces.RateLimiter
+	// defaults to APIBaseURL
+	BaseURL string
+	// how many times a request is retried after a 429, a 502/503/504 or a network error
+	MaxRetries int
+	// sent to the rate limit proxy when BaseURL points to it
+	ProxySecret string
+}
+
+func NewAPIRequester(options *APIRequesterOptions) interfaces.APIRequester {
+	baseURL := options.BaseURL
+	if baseURL == "" {
+		baseURL = APIBaseURL
+	}
 	return &APIRequesterImpl{
 		client:      &http.Client{Timeout: 30 * time.Second},
-		token:       token,
-		rateLimiter: rateLimiter,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		token:       options.Token,
+		rateLimiter: options.RateLimiter,
+		maxRetries:  max(options.MaxRetries, 0),
+		proxySecret: options.ProxySecret,
 	}
 }
 
-func (api *APIRequesterImpl) BaseReq(method, endpoint string, body any, result any) error {
-	route := method + endpoint
+const (
+	noRetry          time.Duration = -1
+	retryWithBackoff time.Duration = -2
+	retryBaseBackoff               = 500 * time.Millisecond
+	retryMaxBackoff                = 8 * time.Second
+)
+
+func (api *APIRequesterImpl) BaseReq(ctx context.Context, method, endpoint string, body any, result any) error {
+	return api.BaseReqWithReason(ctx, method, endpoint, "", body, result)
+}
 
-	if api.rateLimiter.IsRateLimited(route) {
-		waitTime := api.rateLimiter.RetryAfter(route)
-		time.Sleep(waitTime)
+// BaseReqWithReason is BaseReq with an audit log reason,
+// it shows up in the guild audit log entry created by the request.
+//
+// Rate limited (429), 502/503/504 and network failures are retried up to maxRetries times.
+// ctx cancels both the waits between attempts and the HTTP call itself.
+func (api *APIRequesterImpl) BaseReqWithReason(ctx context.Context, method, endpoint, reason string, body any, result any) error {
+	route := domain.NewRoute(method, endpoint)
+
+	// uploads can only be sent again if every file can be rewound
+	var files []*domain.File
+	if withFiles, ok := body.(filesBody); ok {
+		files = withFiles.GetFiles()
+		defer closeFiles(files)
 	}
+	offsets, rewindable := fileOffsets(files)
 
-	var reqBody io.Reader
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err := rewindFiles(files, offsets); err != nil {
+				return fmt.Errorf("[Requester] failed to rewind files for retry: %w", err)
+			}
+		}
 
-	if body != nil {
-		jsonData, err := json.Marshal(body)
-		if err != nil {
-			return fmt.Errorf("[Requester] failed to marshal request body: %w", err)
+		retryAfter, err := api.do(ctx, route, method, endpoint, reason, body, result)
+		if err == nil {
+			return nil
+		}
+		if retryAfter == noRetry || attempt >= api.maxRetries || !rewindable {
+			return err
+		}
+		if retryAfter == retryWithBackoff {
+			retryAfter = backoff(attempt)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
+
+		log.Printf("[Requester] %s %s failed: %v, retrying in %v (%d/%d)",
+			method, endpoint, err, retryAfter, attempt+1, api.maxRetries)
+
+		if err := domain.SleepContext(ctx, retryAfter); err != nil {
+			return fmt.Errorf("[Requester] gave up waiting to retry: %w", err)
+		}
+	}
+}
+
+// do sends the request once.
+// On failure it also returns how long to wait before retrying, noRetry or retryWithBackoff.
+func (api *APIRequesterImpl) do(ctx context.Context, route domain.Route, method, endpoint, reason string, body any, result any) (time.Duration, error) {
+	if err := api.rateLimiter.Wait(ctx, route); err != nil {
+		return noRetry, fmt.Errorf("[Requester] gave up waiting for rate limit: %w", err)
+	}
+
+	reqBody, contentType, err := encodeBody(body)
+	if err != nil {
+		return noRetry, err
+	}
+	// the transport may close the body asynchronously, make sure it's done before a retry
+	if closer, ok := reqBody.(io.Closer); ok {
+		defer closer.Close()
 	}
 
-	req, err := http.NewRequest(method, APIBaseURL+endpoint, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, api.baseURL+endpoint, reqBody)
 	if err != nil {
-		return fmt.Errorf("[Requester] failed to create request: %w", err)
+		return noRetry, fmt.Errorf("[Requester] failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bot "+api.token)
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
 	req.Header.Set("User-Agent", "Vocalize")
+	if api.proxySecret != "" {
+		req.Header.Set(domain.RateLimitProxySecretHeader, api.proxySecret)
+	}
+	if reason != "" {
+		req.Header.Set("X-Audit-Log-Reason", url.PathEscape(reason))
+	}
 
 	resp, err := api.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("[Requester] failed to send request: %w", err)
+		err = fmt.Errorf("[Requester] failed to send request: %w", err)
+		if ctx.Err() == nil && (isIdempotent(method) && isTransientNetworkError(err) || neverSent(err)) {
+			return retryWithBackoff, err
+		}
+		return noRetry, err
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("[Requester] failed to read response body: %w", err)
+		err = fmt.Errorf("[Requester] failed to read response body: %w", err)
+		// discord already answered, sending it again could do it twice
+		if ctx.Err() == nil && isIdempotent(method) && isTransientNetworkError(err) {
+			return retryWithBackoff, err
+		}
+		return noRetry, err
 	}
 
 	api.processRateLimitHeaders(route, resp.Header)
 
 	// Handle rate limiting responses
 	if resp.StatusCode == http.StatusTooManyRequests {
-		var rateLimitResponse struct {
-			Message    string  `json:"message"`
-			RetryAfter float64 `json:"retry_after"`
-			Global     bool    `json:"global"`
-		}
+		var rateLimitResponse domain.RateLimitResponse
 		if err := json.Unmarshal(respBody, &rateLimitResponse); err != nil {
-			return fmt.Errorf("[Requester] hit rate limit but failed to parse response: %w", err)
+			return noRetry, fmt.Errorf("[Requester] hit rate limit but failed to parse response: %w", err)
 		}
 
 		limit := &domain.RateLimit{
@@ -90,48 +177,63 @@ func (api *APIRequesterImpl) BaseReq(method, endpoint string, body any, result a
 			Global:     rateLimitResponse.Global,
 		}
 
-		api.rateLimiter.UpdateLimit(route, limit)
+		api.rateLimiter.UpdateLimit(route, resp.Header.Get("X-RateLimit-Bucket"), limit)
 
-		return fmt.Errorf("[Requester] rate limited: %s, retry after %.2f seconds",
-			rateLimitResponse.Message, rateLimitResponse.RetryAfter)
+		return limit.ResetAfter, &domain.RateLimitError{
+			Message:    rateLimitResponse.Message,
+			RetryAfter: limit.ResetAfter,
+			Global:     rateLimitResponse.Global,
+			Scope:      resp.Header.Get("X-RateLimit-Scope"),
+			Bucket:     resp.Header.Get("X-RateLimit-Bucket"),
+		}
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("[Requester] API error: %d - %s", resp.StatusCode, string(respBody))
+		apiErr := parseAPIError(resp.StatusCode, respBody)
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			// the request may have gone through behind the failing proxy
+			if isIdempotent(method) {
+				return retryWithBackoff, apiErr
+			}
+		}
+		return noRetry, apiErr
 	}
 
 	if result != nil && len(respBody) > 0 {
 		if err := json.Unmarshal(respBody, result); err != nil {
-			return fmt.Errorf("[Requester] failed to unmarshal response: %w", err)
+			return noRetry, fmt.Errorf("[Requester] failed to unmarshal response: %w", err)
 		}
 	}
 
-	return nil
+	return 0, nil
 }
 
-func (api *APIRequesterImpl) processRateLimitHeaders(route string, headers http.Header) {
-	remaining, _ := strconv.Atoi(headers.Get("X-RateLimit-Remaining"))
-	resetAfterStr := headers.Get("X-RateLimit-Reset-After")
+// filesBody is implemented by request bodies that can carry file uploads.
+type filesBody interface {
+	GetFiles() []*domain.File
+}
 
-	if resetAfterStr != "" {
-		resetAfter, err := strconv.ParseFloat(resetAfterStr, 64)
-		if err != nil {
-			return
-		}
+// encodeBody returns the request body and its content type.
+// Bodies carrying files are sent as multipart/form-data, everything else as JSON.
+func encodeBody(body any) (io.Reader, string, error) {
+	if body == nil {
+		return nil, "", nil
+	}
 
-		isGlobal := headers.Get("X-RateLimit-Global") == "true"
+	if withFiles, ok := body.(filesBody); ok && len(withFiles.GetFiles()) > 0 {
+		return encodeMultipart(body, withFiles.GetFiles())
+	}
 
-		limit := &domain.RateLimit{
-			Remaining:  remaining,
-			ResetAfter: time.Duration(resetAfter * float64(time.Second)),
-			Global:     isGlobal,
-		}
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("[Requester] failed to marshal request body: %w", err)
+	}
+	return bytes.NewReader(jsonData), "application/json", nil
+}
 
-		bucket := headers.Get("X-RateLimit-Bucket")
-		if bucket != "" {
-			api.rateLimiter.UpdateLimit(bucket, limit)
-		} else {
-			api.rateLimiter.UpdateLimit(route, limit)
-		}
+func (api *APIRequesterImpl) processRateLimitHeaders(route domain.Route, headers http.Header) {
+	if limit, bucket := domain.ParseRateLimitHeaders(headers); limit != nil {
+		api.rateLimiter.UpdateLimit(route, bucket, limit)
 	}
 }
@@ -0,0 +1,343 @@
+package requester
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+	"github.com/marouane-souiri/vocalize/internal/implementation/ratelimiter"
+)
+
+func newTestRequester(t *testing.T, handler http.HandlerFunc) *APIRequesterImpl {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return &APIRequesterImpl{
+		client:      &http.Client{Timeout: 5 * time.Second},
+		baseURL:     srv.URL,
+		token:       "test_token",
+		rateLimiter: ratelimiter.NewRateLimiter(),
+		maxRetries:  3,
+	}
+}
+
+func TestRequester_SendMessageWithFiles(t *testing.T) {
+	type receivedFile struct {
+		field, name, contentType, content string
+	}
+	var payload domain.SendMessage
+	var files []receivedFile
+
+	api := newTestRequester(t, func(w http.ResponseWriter, r *http.Request) {
+		mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != "multipart/form-data" {
+			t.Errorf("Expected multipart/form-data, got %q", r.Header.Get("Content-Type"))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		reader := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Errorf("Failed to read part: %v", err)
+				return
+			}
+			data, _ := io.ReadAll(part)
+			if part.FormName() == "payload_json" {
+				if err := json.Unmarshal(data, &payload); err != nil {
+					t.Errorf("Invalid payload_json: %v", err)
+				}
+				continue
+			}
+			files = append(files, receivedFile{
+				field:       part.FormName(),
+				name:        part.FileName(),
+				contentType: part.Header.Get("Content-Type"),
+				content:     string(data),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1","channel_id":"42","content":"here you go","attachments":[{"id":"2","filename":"clip.ogg","size":10}]}`))
+	})
+
+	message, err := api.SendMessage(context.Background(), "42", &domain.SendMessage{
+		Content: "here you go",
+		Files: []*domain.File{
+			{Name: "clip.ogg", Description: "tts clip", ContentType: "audio/ogg", Reader: strings.NewReader("oggvorbis!")},
+			{Name: "log.txt", Reader: io.NopCloser(strings.NewReader("some logs"))},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+
+	if message.ID != "1" || len(message.Attachments) != 1 || message.Attachments[0].Filename != "clip.ogg" {
+		t.Fatalf("Unexpected created message: %+v", message)
+	}
+
+	if payload.Content != "here you go" {
+		t.Fatalf("Expected content in payload_json, got %q", payload.Content)
+	}
+	if len(payload.Attachments) != 2 || payload.Attachments[0].Description != "tts clip" || payload.Attachments[1].ID != "1" {
+		t.Fatalf("Unexpected attachments in payload_json: %+v", payload.Attachments)
+	}
+
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(files))
+	}
+	expected := []receivedFile{
+		{"files[0]", "clip.ogg", "audio/ogg", "oggvorbis!"},
+		{"files[1]", "log.txt", "application/octet-stream", "some logs"},
+	}
+	for i, file := range files {
+		if file != expected[i] {
+			t.Fatalf("Expected file %+v, got %+v", expected[i], file)
+		}
+	}
+}
+
+func TestRequester_SendMessageJSON(t *testing.T) {
+	api := newTestRequester(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected application/json, got %q", r.Header.Get("Content-Type"))
+		}
+		if r.URL.Path != "/channels/42/messages" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"id":"1","channel_id":"42","content":"Pong !!"}`))
+	})
+
+	message, err := api.SendMessage(context.Background(), "42", &domain.SendMessage{Content: "Pong !!"})
+	if err != nil {
+		t.Fatalf("SendMessage failed: %v", err)
+	}
+	if message.Content != "Pong !!" {
+		t.Fatalf("Expected created message to be returned, got %+v", message)
+	}
+}
+
+func TestRequester_AuditLogReason(t *testing.T) {
+	var reason, method, path string
+	api := newTestRequester(t, func(w http.ResponseWriter, r *http.Request) {
+		reason = r.Header.Get("X-Audit-Log-Reason")
+		method, path = r.Method, r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if err := api.CreateGuildBan(context.Background(), "1", "2", 3600, "spamming in #général"); err != nil {
+		t.Fatalf("CreateGuildBan failed: %v", err)
+	}
+	if method != "PUT" || path != "/guilds/1/bans/2" {
+		t.Fatalf("Unexpected request %s %s", method, path)
+	}
+	if reason != "spamming%20in%20%23g%C3%A9n%C3%A9ral" {
+		t.Fatalf("Expected URL encoded reason, got %q", reason)
+	}
+
+	reason = "unset"
+	if _, err := api.GetGuild(context.Background(), "1"); err != nil {
+		t.Fatalf("GetGuild failed: %v", err)
+	}
+	if reason != "" {
+		t.Fatalf("Expected no reason header, got %q", reason)
+	}
+}
+
+func TestRequester_APIErrors(t *testing.T) {
+	api := newTestRequester(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/channels/1/messages/404":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Unknown Message","code":10008}`))
+		case "/channels/1/messages/403":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message":"Missing Permissions","code":50013}`))
+		case "/channels/1/messages":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":50035,"message":"Invalid Form Body","errors":{
+				"content":{"_errors":[{"code":"BASE_TYPE_MAX_LENGTH","message":"Must be 2000 or fewer in length."}]},
+				"embeds":{"0":{"description":{"_errors":[{"code":"BASE_TYPE_REQUIRED","message":"This field is required"}]}}}
+			}}`))
+		case "/channels/1/messages/429":
+			w.Header().Set("X-RateLimit-Scope", "user")
+			w.Header().Set("X-RateLimit-Bucket", "abcd")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message":"You are being rate limited.","retry_after":0.05,"global":false}`))
+		}
+	})
+
+	_, err := api.GetMessage(context.Background(), "1", "404")
+	if !domain.IsNotFound(err) || !domain.HasAPIErrorCode(err, domain.APIErrorCode_UNKNOWN_MESSAGE) {
+		t.Fatalf("Expected unknown message error, got %v", err)
+	}
+	if domain.IsMissingPermissions(err) {
+		t.Fatalf("Unknown message reported as missing permissions")
+	}
+
+	_, err = api.GetMessage(context.Background(), "1", "403")
+	if !domain.IsMissingPermissions(err) || domain.IsNotFound(err) {
+		t.Fatalf("Expected missing permissions error, got %v", err)
+	}
+
+	_, err = api.SendMessage(context.Background(), "1", &domain.SendMessage{Content: "too long"})
+	var apiErr *domain.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *domain.APIError, got %T", err)
+	}
+	if apiErr.Status != http.StatusBadRequest || apiErr.Code != domain.APIErrorCode_INVALID_FORM_BODY {
+		t.Fatalf("Unexpected status/code %d/%d", apiErr.Status, apiErr.Code)
+	}
+	if len(apiErr.Errors) != 2 ||
+		apiErr.Errors["content"][0].Code != "BASE_TYPE_MAX_LENGTH" ||
+		apiErr.Errors["embeds.0.description"][0].Code != "BASE_TYPE_REQUIRED" {
+		t.Fatalf("Unexpected field errors: %+v", apiErr.Errors)
+	}
+
+	_, err = api.GetMessage(context.Background(), "1", "429")
+	var rateLimitErr *domain.RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Expected *domain.RateLimitError, got %T: %v", err, err)
+	}
+	if rateLimitErr.Scope != "user" || rateLimitErr.Bucket != "abcd" || rateLimitErr.RetryAfter != 50*time.Millisecond {
+		t.Fatalf("Unexpected rate limit error: %+v", rateLimitErr)
+	}
+	if domain.IsNotFound(err) {
+		t.Fatalf("Rate limit reported as not found")
+	}
+}
+
+func TestRequester_RetriesRateLimitsAndServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	api := newTestRequester(t, func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message":"You are being rate limited.","retry_after":0.05,"global":false}`))
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"id":"1","content":"finally"}`))
+		}
+	})
+
+	message, err := api.GetMessage(context.Background(), "1", "1")
+	if err != nil {
+		t.Fatalf("Expected the request to succeed after retries, got %v", err)
+	}
+	if message.Content != "finally" || calls.Load() != 3 {
+		t.Fatalf("Expected 3 calls and the message, got %d calls and %+v", calls.Load(), message)
+	}
+}
+
+func TestRequester_DoesntRetryServerErrorsOfNonIdempotentRequests(t *testing.T) {
+	var calls atomic.Int32
+	api := newTestRequester(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	// the message may have been sent behind the failing proxy
+	_, err := api.SendMessage(context.Background(), "1", &domain.SendMessage{Content: "once"})
+	if err == nil || calls.Load() != 1 {
+		t.Fatalf("Expected the error without retrying, got err=%v calls=%d", err, calls.Load())
+	}
+}
+
+func TestNeverSent(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("no route to host")}
+	readErr := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	if !neverSent(fmt.Errorf("wrapped: %w", dialErr)) || !neverSent(syscall.ECONNREFUSED) {
+		t.Fatalf("Expected dial failures to be reported as never sent")
+	}
+	if neverSent(readErr) || neverSent(io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected failures after connecting to be reported as maybe sent")
+	}
+}
+
+func TestRequester_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	api := newTestRequester(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"message":"You are being rate limited.","retry_after":0.01,"global":false}`))
+	})
+	api.maxRetries = 2
+
+	_, err := api.GetMessage(context.Background(), "1", "2")
+	if !domain.IsRateLimited(err) {
+		t.Fatalf("Expected a rate limit error, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("Expected 1 call and 2 retries, got %d calls", calls.Load())
+	}
+}
+
+func TestRequester_ContextCancelsRetryWait(t *testing.T) {
+	api := newTestRequester(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"message":"You are being rate limited.","retry_after":10,"global":false}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := api.GetMessage(ctx, "1", "2")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("Cancellation didn't abort the wait, took %v", time.Since(start))
+	}
+}
+
+func TestRequester_RetriesUploadsOnlyWhenSeekable(t *testing.T) {
+	var calls atomic.Int32
+	var lastFile string
+	api := newTestRequester(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseMultipartForm(1 << 20)
+		if file, _, err := r.FormFile("files[0]"); err == nil {
+			data, _ := io.ReadAll(file)
+			lastFile = string(data)
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"message":"You are being rate limited.","retry_after":0.01,"global":false}`))
+			return
+		}
+		w.Write([]byte(`{"id":"1"}`))
+	})
+
+	_, err := api.SendMessage(context.Background(), "1", &domain.SendMessage{
+		Files: []*domain.File{{Name: "a.txt", Reader: strings.NewReader("seekable")}},
+	})
+	if err != nil || calls.Load() != 2 || lastFile != "seekable" {
+		t.Fatalf("Expected seekable upload to be retried whole, got err=%v calls=%d file=%q", err, calls.Load(), lastFile)
+	}
+
+	calls.Store(0)
+	_, err = api.SendMessage(context.Background(), "1", &domain.SendMessage{
+		Files: []*domain.File{{Name: "a.txt", Reader: io.MultiReader(strings.NewReader("stream"))}},
+	})
+	if err == nil || calls.Load() != 1 {
+		t.Fatalf("Expected non seekable upload not to be retried, got err=%v calls=%d", err, calls.Load())
+	}
+}
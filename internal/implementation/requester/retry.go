@@ -0,0 +1,82 @@
+package requester
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// backoff returns the wait before the next retry: 0.5s, 1s, 2s, 4s, 8s, 8s... plus up to 30% jitter.
+func backoff(attempt int) time.Duration {
+	delay := retryMaxBackoff
+	if attempt < 5 {
+		delay = min(retryBaseBackoff*time.Duration(1<<attempt), retryMaxBackoff)
+	}
+	jitter := time.Duration(rand.Float64() * float64(delay) * 0.3)
+	return delay + jitter
+}
+
+func isTransientNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// isIdempotent tells if sending the request twice does the same as sending it once.
+// Other requests, like creating a message, are only retried when discord surely didn't get them.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// neverSent tells if the request failed before any of it was written, so retrying can't repeat it.
+func neverSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// fileOffsets records where each file reader currently is, so a retry can send it again from there.
+// It reports false if one of them can't seek.
+func fileOffsets(files []*domain.File) ([]int64, bool) {
+	offsets := make([]int64, len(files))
+	for i, file := range files {
+		seeker, ok := file.Reader.(io.Seeker)
+		if !ok {
+			return nil, false
+		}
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, false
+		}
+		offsets[i] = offset
+	}
+	return offsets, true
+}
+
+func rewindFiles(files []*domain.File, offsets []int64) error {
+	for i, file := range files {
+		if _, err := file.Reader.(io.Seeker).Seek(offsets[i], io.SeekStart); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package requester
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// https://discord.com/developers/docs/resources/guild#get-guild-roles
+func (api *APIRequesterImpl) GetGuildRoles(ctx context.Context, guildID string) ([]domain.Role, error) {
+	endpoint := fmt.Sprintf("/guilds/%s/roles", guildID)
+	var roles []domain.Role
+	if err := api.BaseReq(ctx, "GET", endpoint, nil, &roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// https://discord.com/developers/docs/resources/guild#create-guild-role
+func (api *APIRequesterImpl) CreateGuildRole(ctx context.Context, guildID string, role *domain.RoleParams, reason string) (*domain.Role, error) {
+	endpoint := fmt.Sprintf("/guilds/%s/roles", guildID)
+	var created domain.Role
+	if err := api.BaseReqWithReason(ctx, "POST", endpoint, reason, role, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// https://discord.com/developers/docs/resources/guild#modify-guild-role
+func (api *APIRequesterImpl) ModifyGuildRole(ctx context.Context, guildID, roleID string, role *domain.RoleParams, reason string) (*domain.Role, error) {
+	endpoint := fmt.Sprintf("/guilds/%s/roles/%s", guildID, roleID)
+	var modified domain.Role
+	if err := api.BaseReqWithReason(ctx, "PATCH", endpoint, reason, role, &modified); err != nil {
+		return nil, err
+	}
+	return &modified, nil
+}
+
+// https://discord.com/developers/docs/resources/guild#modify-guild-role-positions
+func (api *APIRequesterImpl) ModifyGuildRolePositions(ctx context.Context, guildID string, positions []domain.RolePosition, reason string) ([]domain.Role, error) {
+	endpoint := fmt.Sprintf("/guilds/%s/roles", guildID)
+	var roles []domain.Role
+	if err := api.BaseReqWithReason(ctx, "PATCH", endpoint, reason, positions, &roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// https://discord.com/developers/docs/resources/guild#delete-guild-role
+func (api *APIRequesterImpl) DeleteGuildRole(ctx context.Context, guildID, roleID, reason string) error {
+	endpoint := fmt.Sprintf("/guilds/%s/roles/%s", guildID, roleID)
+	return api.BaseReqWithReason(ctx, "DELETE", endpoint, reason, nil, nil)
+}
@@ -0,0 +1,18 @@
+package requester
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// https://discord.com/developers/docs/resources/user#get-user
+func (api *APIRequesterImpl) GetUser(ctx context.Context, userID string) (*domain.User, error) {
+	endpoint := fmt.Sprintf("/users/%s", userID)
+	var user domain.User
+	if err := api.BaseReq(ctx, "GET", endpoint, nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+type ArgumentsParser interface {
+	// Parse converts the arguments typed after a prefix command, see domain.Tokenize.
+	// Users, members, channels and roles are resolved through the cache, guildID is empty in DMs.
+	// Invalid input gives a *domain.UsageError, an invalid schema a plain error.
+	Parse(ctx context.Context, guildID string, arguments []domain.Argument, input string) (*domain.Args, error)
+}
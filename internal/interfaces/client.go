@@ -1,6 +1,8 @@
 package interfaces
 
 import (
+	"context"
+
 	"github.com/marouane-souiri/vocalize/internal/domain"
 )
 
@@ -18,7 +20,10 @@ type Client interface {
 	// This object is shared between goroutines.
 	// Mutating it directly can lead to data races and undefined behavior.
 	// Always copy it before making changes - or we will be fucked.
-	GetGuild(ID string) (*domain.Guild, error)
+	//
+	// On a cache miss the guild is fetched from the API and cached,
+	// ctx bounds how long the caller is willing to wait for it.
+	GetGuild(ctx context.Context, ID string) (*domain.Guild, error)
 	// WARNING:
 	// Do not modify the values of the returned map (*Guild).
 	// The Guild objects are shared between goroutines.
@@ -33,7 +38,14 @@ type Client interface {
 	// This object is shared between goroutines.
 	// Mutating it directly can lead to data races and undefined behavior.
 	// Always copy it before making changes - or we will be fucked.
-	GetChannel(ID string) (*domain.Channel, error)
+	//
+	// On a cache miss the channel is fetched from the API and cached.
+	GetChannel(ctx context.Context, ID string) (*domain.Channel, error)
+	// WARNING:
+	// Do not modify the returned Channels, same as GetChannel.
+	//
+	// Only the cached channels are returned, the ones received with the guild and kept up to date since.
+	GetGuildChannels(guildID string) []*domain.Channel
 
 	SetMember(member *domain.Member)
 	DelMember(memberID, guildID string)
@@ -42,7 +54,31 @@ type Client interface {
 	// This object is shared between goroutines.
 	// Mutating it directly can lead to data races and undefined behavior.
 	// Always copy it before making changes - or we will be fucked.
-	GetMember(memberID, guildID string) (*domain.Member, error)
+	//
+	// On a cache miss the member is fetched from the API and cached.
+	GetMember(ctx context.Context, memberID, guildID string) (*domain.Member, error)
+
+	// GetSelfUser returns the bot user, nil until the client is ready.
+	GetSelfUser() *domain.User
+
+	// GetAPIRequester gives access to the whole REST API,
+	// for moderation and setup endpoints that don't have a shortcut on the client.
+	GetAPIRequester() APIRequester
+
+	SendMessage(ctx context.Context, channelID string, message *domain.SendMessage) (*domain.Message, error)
+	EditMessage(ctx context.Context, channelID, messageID string, message *domain.EditMessage) (*domain.Message, error)
+	DeleteMessage(ctx context.Context, channelID, messageID string) error
+	BulkDeleteMessages(ctx context.Context, channelID string, messageIDs []string) error
+	GetMessage(ctx context.Context, channelID, messageID string) (*domain.Message, error)
+	GetChannelMessages(ctx context.Context, channelID string, query *domain.ChannelMessagesQuery) ([]domain.Message, error)
+	PinMessage(ctx context.Context, channelID, messageID string) error
+	UnpinMessage(ctx context.Context, channelID, messageID string) error
+	CrosspostMessage(ctx context.Context, channelID, messageID string) (*domain.Message, error)
 
-	SendMessage(channelID string, message *domain.SendMessage) error
+	CreateReaction(ctx context.Context, channelID, messageID, emoji string) error
+	DeleteOwnReaction(ctx context.Context, channelID, messageID, emoji string) error
+	DeleteUserReaction(ctx context.Context, channelID, messageID, emoji, userID string) error
+	GetReactions(ctx context.Context, channelID, messageID, emoji string, query *domain.ReactionsQuery) ([]domain.User, error)
+	DeleteAllReactions(ctx context.Context, channelID, messageID string) error
+	DeleteAllReactionsForEmoji(ctx context.Context, channelID, messageID, emoji string) error
 }
@@ -0,0 +1,21 @@
+package interfaces
+
+// CommandFailure is an error returned while calling a command, with where it happened.
+type CommandFailure struct {
+	Err error
+	// nil when the command wasn't found
+	Command BaseCommand
+	// the command as typed with its prefix, e.g. ".voice set"
+	Invoked   string
+	UserID    string
+	GuildID   string
+	ChannelID string
+}
+
+// CommandErrorHandler is where prefix and slash commands report their errors.
+type CommandErrorHandler interface {
+	// Handle logs the failure and returns what to tell the user.
+	// User errors (usage, denials, cooldowns) are explained, internal ones only get an error ID
+	// to find the details in the logs.
+	Handle(failure *CommandFailure) string
+}
@@ -1,12 +1,136 @@
 package interfaces
 
-import "github.com/marouane-souiri/vocalize/internal/domain"
+import (
+	"context"
 
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// CommandContext is what a command runs with, the same whether it was called
+// with the prefix in a guild, in DMs or as a slash command.
 type CommandContext interface {
+	// GetGuildID is empty in DMs.
+	GetGuildID() string
+	GetChannelID() string
+	GetAuthor() *domain.User
+	// GetMember is nil in DMs.
+	GetMember() *domain.Member
+	// GetMessage returns the message that called the command, or the message of a component.
+	// It's nil for slash commands.
+	GetMessage() *domain.Message
+	// GetArgs returns the arguments converted according to the command, see BaseCommand.GetArguments.
+	// The options of slash commands are there too.
+	GetArgs() *domain.Args
+	// GetPrefix is "/" for slash commands.
+	GetPrefix() string
+	// GetInvokedName returns the name or alias the command was called with.
+	GetInvokedName() string
+	// Context is done once replying isn't possible anymore, or the command ran for too long.
+	Context() context.Context
+
+	// Reply answers the message or the interaction, flags like ephemeral only apply to interactions.
+	Reply(ctx context.Context, data *domain.InteractionResponseData) error
+	ReplyEmbed(ctx context.Context, embed domain.Embed) error
+	// React adds a reaction to the message that called the command, or to the reply of an interaction.
+	React(ctx context.Context, emoji string) error
+	// Typing shows the bot is working on it, "is typing..." for messages and "thinking..." for interactions.
+	Typing(ctx context.Context) error
+}
+
+// InteractionContext is the context of a command run as a slash command.
+//
+// Discord needs an initial response within 3 seconds,
+// the reply is deferred automatically when the command takes longer.
+type InteractionContext interface {
+	CommandContext
+	GetInteraction() *domain.Interaction
+	GetCommandData() *domain.ApplicationCommandInteractionData
+	// GetOption finds an option by name, looking inside the subcommand that was used.
+	GetOption(name string) (*domain.ApplicationCommandInteractionDataOption, bool)
+
+	// SetEphemeral makes the next responses visible only to the user, it must be set before deferring.
+	SetEphemeral(ephemeral bool)
+	// Reply sends the initial response, fills a deferred one, or sends a follow up after that.
+	Reply(ctx context.Context, data *domain.InteractionResponseData) error
+	// DeferReply shows "thinking..." until Reply or EditOriginal, it does nothing once responded.
+	DeferReply(ctx context.Context) error
+	FollowUp(ctx context.Context, data *domain.InteractionResponseData) (*domain.Message, error)
+	EditOriginal(ctx context.Context, message *domain.EditMessage) (*domain.Message, error)
+	// ShowModal answers with a form instead of a message, it must be the initial response.
+	// The submit is routed by the modal custom ID, see InteractionsRouter.HandleModal.
+	ShowModal(ctx context.Context, modal *domain.Modal) error
+	// Responded tells if the initial response (a reply, a defer or a modal) was sent.
+	Responded() bool
+}
+
+// ComponentContext is the context of a click on a button or a choice in a select menu.
+type ComponentContext interface {
+	CommandContext
+	GetInteraction() *domain.Interaction
+	GetComponentData() *domain.MessageComponentInteractionData
+	// GetParam returns a segment of the custom ID captured by the pattern, e.g. "guild_id" for "player:skip:{guild_id}".
+	GetParam(name string) string
+	// GetValues returns the selected values of a select menu.
+	GetValues() []string
+
+	SetEphemeral(ephemeral bool)
+	// Reply sends a new message, after an update it's a follow up.
+	Reply(ctx context.Context, data *domain.InteractionResponseData) error
+	DeferReply(ctx context.Context) error
+	// Update edits the message the component is on.
+	Update(ctx context.Context, data *domain.InteractionResponseData) error
+	// DeferUpdate acknowledges the click without changing anything yet, the message can be updated later.
+	DeferUpdate(ctx context.Context) error
+	FollowUp(ctx context.Context, data *domain.InteractionResponseData) (*domain.Message, error)
+	EditOriginal(ctx context.Context, message *domain.EditMessage) (*domain.Message, error)
+	ShowModal(ctx context.Context, modal *domain.Modal) error
+	Responded() bool
+}
+
+// ModalContext is the context of a submitted modal.
+type ModalContext interface {
+	CommandContext
+	GetInteraction() *domain.Interaction
+	GetModalData() *domain.ModalSubmitInteractionData
+	// GetParam returns a segment of the modal custom ID captured by the pattern.
+	GetParam(name string) string
+	// GetValue returns what was typed in the text input with this custom ID.
+	GetValue(customID string) string
+	GetValues() map[string]string
+
+	SetEphemeral(ephemeral bool)
+	Reply(ctx context.Context, data *domain.InteractionResponseData) error
+	DeferReply(ctx context.Context) error
+	// Update edits the message of the component that opened the modal, if any.
+	Update(ctx context.Context, data *domain.InteractionResponseData) error
+	DeferUpdate(ctx context.Context) error
+	FollowUp(ctx context.Context, data *domain.InteractionResponseData) (*domain.Message, error)
+	EditOriginal(ctx context.Context, message *domain.EditMessage) (*domain.Message, error)
+	Responded() bool
+}
+
+// AutocompleteContext is the context of an option being typed, see AutocompleteProvider.
+// It can't reply, the provider returns the choices instead.
+type AutocompleteContext interface {
 	GetGuildID() string
 	GetChannelID() string
+	GetAuthor() *domain.User
+	GetMember() *domain.Member
+	GetInteraction() *domain.Interaction
+	GetCommandData() *domain.ApplicationCommandInteractionData
+	// GetFocused returns the option being typed, its value is the partial input as a string.
+	GetFocused() *domain.ApplicationCommandInteractionDataOption
+	// GetOption finds one of the other options already filled.
+	GetOption(name string) (*domain.ApplicationCommandInteractionDataOption, bool)
+	// Context is done once the choices can't be sent anymore.
+	Context() context.Context
 }
 
+// The ctx of the contexts made bounds how long the command can run, see CommandContext.Context.
 type CommandsContextMaker interface {
-	FromMessageEvent(event domain.MessageCreateEvent) CommandContext
+	FromMessageEvent(ctx context.Context, event domain.MessageCreateEvent, args *domain.Args, prefix, invokedName string, api APIRequester) CommandContext
+	FromInteraction(ctx context.Context, interaction *domain.Interaction, data *domain.ApplicationCommandInteractionData, api APIRequester, responder InteractionResponder) InteractionContext
+	FromComponentInteraction(ctx context.Context, interaction *domain.Interaction, data *domain.MessageComponentInteractionData, params map[string]string, api APIRequester, responder InteractionResponder) ComponentContext
+	FromModalInteraction(ctx context.Context, interaction *domain.Interaction, data *domain.ModalSubmitInteractionData, params map[string]string, api APIRequester, responder InteractionResponder) ModalContext
+	FromAutocompleteInteraction(ctx context.Context, interaction *domain.Interaction, data *domain.ApplicationCommandInteractionData, focused *domain.ApplicationCommandInteractionDataOption) AutocompleteContext
 }
@@ -1,14 +1,78 @@
 package interfaces
 
+import (
+	"context"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
 type BaseCommand interface {
 	GetName() string
 	GetAliases() []string
 	GetDescription() string
+	// GetCategory groups the command with others in the help, empty for uncategorized.
+	GetCategory() string
+	// GetArguments returns the arguments of the prefix command, see ArgumentsParser.
+	// Commands without an explicit slash definition get their options from them.
+	GetArguments() []domain.Argument
+	// GetSubcommands returns the commands nested under this one, e.g. set and list for voice.
+	// They're slash subcommands (and subcommand groups for nested ones) too.
+	GetSubcommands() []BaseCommand
+	// GetDefaultSubcommand returns the subcommand run when none is given, if any.
+	GetDefaultSubcommand() (BaseCommand, bool)
+	// GetParent returns the command this one is a subcommand of, nil for top level commands.
+	// It's set when the top level command is added to the CommandsManager.
+	GetParent() BaseCommand
+	// GetApplicationCommand returns the slash command definition registered for this command,
+	// nil when it's only available with the prefix.
+	GetApplicationCommand() *domain.ApplicationCommand
+	// GetAutocompleteProvider returns the provider of an option, subcommands is the path of the subcommand
+	// it belongs to, e.g. ["voice", "set"].
+	GetAutocompleteProvider(subcommands []string, option string) (AutocompleteProvider, bool)
+	// GetRequirements returns the permissions and flags checked before the command runs.
+	// Those of its parents are checked too.
+	GetRequirements() domain.CommandRequirements
+	// GetGuards returns the custom checks run after the requirements, in order, after those of its parents.
+	GetGuards() []CommandGuard
+	// GetLimits returns the cooldown and the concurrency limit of the command.
+	// Those of its parents apply too, shared by all their subcommands.
+	GetLimits() domain.CommandLimits
 	Run(client Client, ctx CommandContext) error
 }
 
+// AutocompleteProvider suggests values for an option while the user types it,
+// it must answer before ctx.Context() is done and at most 25 choices are kept.
+type AutocompleteProvider func(client Client, ctx AutocompleteContext) ([]domain.ApplicationCommandOptionChoice, error)
+
+// CommandGuard allows or denies a command, it returns a *domain.CommandDeniedError to deny it.
+type CommandGuard func(client Client, ctx CommandContext) error
+
+// CommandRunner runs a command, the last one of the pipeline calls command.Run.
+type CommandRunner func(client Client, command BaseCommand, ctx CommandContext) error
+
+// CommandMiddleware wraps the runner of the commands, it can stop a command by not calling next.
+type CommandMiddleware func(next CommandRunner) CommandRunner
+
 type CommandsManager interface {
 	AddCommand(command BaseCommand)
 	AddCommands(commands ...BaseCommand)
 	GetCommand(NameOrAlias string) (BaseCommand, bool)
+	// ResolveCommand finds the deepest command the first words of line name, e.g. "voice set 50"
+	// gives the set subcommand of voice, the path as typed ["voice", "set"] and the rest "50".
+	ResolveCommand(line string) (command BaseCommand, path []string, input string, ok bool)
+	// GetCommandPath follows the names of subcommands from a command, the way slash commands give them.
+	GetCommandPath(name string, subcommands []string) (BaseCommand, bool)
+	// GetCommands returns every command once, aliases aren't repeated.
+	GetCommands() []BaseCommand
+	// Use adds middlewares to the pipeline commands run through, the first one added runs first.
+	Use(middlewares ...CommandMiddleware)
+	// RunCommand runs a command through the middlewares, prefix and slash commands alike.
+	// A panic of the command is returned as a *domain.PanicError.
+	RunCommand(client Client, command BaseCommand, ctx CommandContext) error
+	// SuggestCommands returns the names and aliases closest to an unknown one, best first.
+	SuggestCommands(name string) []string
+	// SyncApplicationCommands makes the commands registered on discord match the local definitions,
+	// only what changed is created, edited or deleted.
+	// An empty guildID syncs the global commands.
+	SyncApplicationCommands(ctx context.Context, api APIRequester, applicationID, guildID string) (*domain.ApplicationCommandsSync, error)
 }
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// CooldownStore counts the uses and the runs in progress of commands, by key.
+type CooldownStore interface {
+	// Take records a use under key if the cooldown allows it,
+	// otherwise it returns how long until the oldest use leaves the window.
+	Take(key string, cooldown domain.Cooldown, now time.Time) (retryAfter time.Duration, ok bool)
+	// Acquire starts a run under key if fewer than max are in progress,
+	// release must be called once the run is over.
+	Acquire(key string, max int) (release func(), ok bool)
+	// Len returns how many keys have uses recorded.
+	Len() int
+}
@@ -30,6 +30,9 @@ type DiscordCacheManager interface {
 	// Mutating it directly can lead to data races and undefined behavior.
 	// Always copy it before making changes - or we will be fucked.
 	GetChannel(ID string) (*domain.Channel, bool)
+	// WARNING:
+	// Do not modify the returned Channels, same as GetChannel.
+	GetGuildChannels(guildID string) []*domain.Channel
 	ChannelsCount() int
 
 	/** Members Cache */
@@ -0,0 +1,30 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// GuildSettingsRepository stores the settings of the guilds, in memory or on disk.
+type GuildSettingsRepository interface {
+	// Get returns the settings of a guild, domain.NewGuildSettings when it didn't configure anything.
+	// The settings returned are a copy, changes are kept with Save or Update.
+	Get(ctx context.Context, guildID string) (*domain.GuildSettings, error)
+	Save(ctx context.Context, settings *domain.GuildSettings) error
+	// Update applies change to the settings of a guild and saves them,
+	// no other change of the guild happens in between. Nothing is saved when change fails.
+	Update(ctx context.Context, guildID string, change func(settings *domain.GuildSettings) error) (*domain.GuildSettings, error)
+	// Delete forgets a guild, when the bot leaves it.
+	Delete(ctx context.Context, guildID string) error
+}
+
+// PrefixResolver finds the prefix a message calls a command with.
+type PrefixResolver interface {
+	// GetPrefixes returns the prefixes of a guild, the default one when it has none.
+	// guildID is empty in DMs, where only the default prefix works.
+	GetPrefixes(ctx context.Context, guildID string) ([]string, error)
+	// MatchPrefix returns the prefix content starts with and the command line after it,
+	// nil when content doesn't start with one. The mention of the bot always works as a prefix.
+	MatchPrefix(ctx context.Context, guildID, content string) (*domain.PrefixMatch, error)
+}
@@ -0,0 +1,38 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+// InteractionResponder sends the initial response of an interaction.
+// Over the gateway it's a REST callback, over the HTTP endpoint it's the body of the HTTP response.
+type InteractionResponder interface {
+	Respond(ctx context.Context, interaction *domain.Interaction, response *domain.InteractionResponse) error
+}
+
+type ComponentHandler func(client Client, ctx ComponentContext) error
+
+type ModalHandler func(client Client, ctx ModalContext) error
+
+type InteractionsRouter interface {
+	// HandleInteraction runs what the interaction targets, responder sends the initial response.
+	// It returns once the handler is done.
+	HandleInteraction(interaction *domain.Interaction, responder InteractionResponder)
+
+	// HandleComponent routes the components whose custom ID matches pattern to handler.
+	// Segments are separated by ":" and "{name}" segments capture state, e.g. "player:skip:{guild_id}".
+	// The first matching pattern wins, in registration order.
+	// Since the state lives in the custom ID, these keep working after a restart.
+	HandleComponent(pattern string, handler ComponentHandler)
+	// WatchComponents routes the components of one message to handler, before the patterns,
+	// nil leaves the routing to the patterns.
+	// Once the components weren't used for timeout they are disabled on the message and the handler dropped.
+	WatchComponents(channelID, messageID string, timeout time.Duration, handler ComponentHandler)
+
+	// HandleModal routes the submitted modals whose custom ID matches pattern to handler,
+	// patterns work like the component ones.
+	HandleModal(pattern string, handler ModalHandler)
+}
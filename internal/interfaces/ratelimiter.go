@@ -1,13 +1,22 @@
 package interfaces
 
 import (
+	"context"
 	"time"
 
 	"github.com/marouane-souiri/vocalize/internal/domain"
 )
 
 type RateLimiter interface {
-	UpdateLimit(route string, limit *domain.RateLimit)
-	IsRateLimited(route string) bool
-	RetryAfter(route string) time.Duration
+	// Wait blocks until a request to route can be sent, requests of a bucket are let through in FIFO order.
+	// It takes a permit from the bucket: call it once per request, right before sending it.
+	Wait(ctx context.Context, route domain.Route) error
+	// UpdateLimit records the limit discord answered with for route.
+	// bucket is the X-RateLimit-Bucket hash, empty when discord didn't send one.
+	UpdateLimit(route domain.Route, bucket string, limit *domain.RateLimit)
+	IsRateLimited(route domain.Route) bool
+	RetryAfter(route domain.Route) time.Duration
+	// QueueMetrics returns the time spent queued, per bucket.
+	// Buckets idle for a while are forgotten, with their metrics.
+	QueueMetrics() map[string]domain.RateLimitQueueMetrics
 }
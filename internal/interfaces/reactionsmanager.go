@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
+
+type ReactionsManager interface {
+	// Watch replaces any listener already registered for the message.
+	// Once nobody reacted for timeout the listener is dropped, see ReactionListener.OnExpire.
+	Watch(messageID string, timeout time.Duration, listener *domain.ReactionListener)
+	Unwatch(messageID string)
+	IsWatched(messageID string) bool
+
+	HandleReactionAdd(event domain.MessageReactionAddEvent)
+	HandleReactionRemove(event domain.MessageReactionRemoveEvent)
+	HandleReactionRemoveAll(event domain.MessageReactionRemoveAllEvent)
+	HandleReactionRemoveEmoji(event domain.MessageReactionRemoveEmojiEvent)
+}
@@ -1,7 +1,85 @@
 package interfaces
 
-import "github.com/marouane-souiri/vocalize/internal/domain"
+import (
+	"context"
+	"time"
+
+	"github.com/marouane-souiri/vocalize/internal/domain"
+)
 
 type APIRequester interface {
-	SendMessage(channelID string, message *domain.SendMessage) error
+	/** Messages */
+	SendMessage(ctx context.Context, channelID string, message *domain.SendMessage) (*domain.Message, error)
+	EditMessage(ctx context.Context, channelID, messageID string, message *domain.EditMessage) (*domain.Message, error)
+	DeleteMessage(ctx context.Context, channelID, messageID string) error
+	BulkDeleteMessages(ctx context.Context, channelID string, messageIDs []string) error
+	GetMessage(ctx context.Context, channelID, messageID string) (*domain.Message, error)
+	GetChannelMessages(ctx context.Context, channelID string, query *domain.ChannelMessagesQuery) ([]domain.Message, error)
+	PinMessage(ctx context.Context, channelID, messageID string) error
+	UnpinMessage(ctx context.Context, channelID, messageID string) error
+	CrosspostMessage(ctx context.Context, channelID, messageID string) (*domain.Message, error)
+
+	/** Reactions */
+	CreateReaction(ctx context.Context, channelID, messageID, emoji string) error
+	DeleteOwnReaction(ctx context.Context, channelID, messageID, emoji string) error
+	DeleteUserReaction(ctx context.Context, channelID, messageID, emoji, userID string) error
+	GetReactions(ctx context.Context, channelID, messageID, emoji string, query *domain.ReactionsQuery) ([]domain.User, error)
+	DeleteAllReactions(ctx context.Context, channelID, messageID string) error
+	DeleteAllReactionsForEmoji(ctx context.Context, channelID, messageID, emoji string) error
+
+	// The reason parameters are written to the guild audit log, leave them empty to skip it.
+
+	/** Guilds */
+	GetGuild(ctx context.Context, guildID string) (*domain.Guild, error)
+
+	/** Channels */
+	GetChannel(ctx context.Context, channelID string) (*domain.Channel, error)
+	TriggerTypingIndicator(ctx context.Context, channelID string) error
+	GetGuildChannels(ctx context.Context, guildID string) ([]domain.Channel, error)
+	CreateGuildChannel(ctx context.Context, guildID string, channel *domain.CreateChannel, reason string) (*domain.Channel, error)
+	ModifyChannel(ctx context.Context, channelID string, channel *domain.ModifyChannel, reason string) (*domain.Channel, error)
+	DeleteChannel(ctx context.Context, channelID, reason string) error
+	EditChannelPermissions(ctx context.Context, channelID string, overwrite *domain.PermissionOverwrite, reason string) error
+	DeleteChannelPermission(ctx context.Context, channelID, overwriteID, reason string) error
+
+	/** Roles */
+	GetGuildRoles(ctx context.Context, guildID string) ([]domain.Role, error)
+	CreateGuildRole(ctx context.Context, guildID string, role *domain.RoleParams, reason string) (*domain.Role, error)
+	ModifyGuildRole(ctx context.Context, guildID, roleID string, role *domain.RoleParams, reason string) (*domain.Role, error)
+	ModifyGuildRolePositions(ctx context.Context, guildID string, positions []domain.RolePosition, reason string) ([]domain.Role, error)
+	DeleteGuildRole(ctx context.Context, guildID, roleID, reason string) error
+
+	/** Members */
+	GetGuildMember(ctx context.Context, guildID, userID string) (*domain.Member, error)
+	ModifyGuildMember(ctx context.Context, guildID, userID string, member *domain.ModifyMember, reason string) (*domain.Member, error)
+	TimeoutGuildMember(ctx context.Context, guildID, userID string, until *time.Time, reason string) (*domain.Member, error)
+	AddGuildMemberRole(ctx context.Context, guildID, userID, roleID, reason string) error
+	RemoveGuildMemberRole(ctx context.Context, guildID, userID, roleID, reason string) error
+	KickGuildMember(ctx context.Context, guildID, userID, reason string) error
+
+	/** Bans */
+	GetGuildBans(ctx context.Context, guildID string, query *domain.BansQuery) ([]domain.Ban, error)
+	GetGuildBan(ctx context.Context, guildID, userID string) (*domain.Ban, error)
+	CreateGuildBan(ctx context.Context, guildID, userID string, deleteMessageSeconds int, reason string) error
+	RemoveGuildBan(ctx context.Context, guildID, userID, reason string) error
+
+	/** Users */
+	GetUser(ctx context.Context, userID string) (*domain.User, error)
+
+	/** Application commands */
+	// An empty guildID targets the global commands.
+	GetApplicationCommands(ctx context.Context, applicationID, guildID string) ([]domain.ApplicationCommand, error)
+	CreateApplicationCommand(ctx context.Context, applicationID, guildID string, command *domain.ApplicationCommand) (*domain.ApplicationCommand, error)
+	EditApplicationCommand(ctx context.Context, applicationID, guildID, commandID string, command *domain.ApplicationCommand) (*domain.ApplicationCommand, error)
+	DeleteApplicationCommand(ctx context.Context, applicationID, guildID, commandID string) error
+	BulkOverwriteApplicationCommands(ctx context.Context, applicationID, guildID string, commands []domain.ApplicationCommand) ([]domain.ApplicationCommand, error)
+
+	/** Interactions */
+	CreateInteractionResponse(ctx context.Context, interactionID, token string, response *domain.InteractionResponse) error
+	GetOriginalInteractionResponse(ctx context.Context, applicationID, token string) (*domain.Message, error)
+	EditOriginalInteractionResponse(ctx context.Context, applicationID, token string, message *domain.EditMessage) (*domain.Message, error)
+	DeleteOriginalInteractionResponse(ctx context.Context, applicationID, token string) error
+	CreateFollowupMessage(ctx context.Context, applicationID, token string, message *domain.InteractionResponseData) (*domain.Message, error)
+	EditFollowupMessage(ctx context.Context, applicationID, token, messageID string, message *domain.EditMessage) (*domain.Message, error)
+	DeleteFollowupMessage(ctx context.Context, applicationID, token, messageID string) error
 }